@@ -8,6 +8,8 @@ require (
 	github.com/google/uuid v1.6.0
 	github.com/spf13/viper v1.17.0
 	go.uber.org/zap v1.24.0
+	golang.org/x/crypto v0.23.0
+	golang.org/x/net v0.25.0
 	gorm.io/driver/mysql v1.5.6
 	gorm.io/gorm v1.30.0
 )
@@ -53,9 +55,7 @@ require (
 	go.uber.org/atomic v1.9.0 // indirect
 	go.uber.org/multierr v1.11.0 // indirect
 	golang.org/x/arch v0.8.0 // indirect
-	golang.org/x/crypto v0.23.0 // indirect
 	golang.org/x/exp v0.0.0-20250620022241-b7579e27df2b // indirect
-	golang.org/x/net v0.25.0 // indirect
 	golang.org/x/sys v0.36.0 // indirect
 	golang.org/x/text v0.20.0 // indirect
 	google.golang.org/protobuf v1.34.1 // indirect
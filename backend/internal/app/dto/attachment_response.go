@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"time"
+
+	"todolist/backend/internal/domain/attachment"
+)
+
+type AttachmentResponse struct {
+	ID          uint64 `json:"id"`
+	TaskUUID    string `json:"taskUuid"`
+	FileName    string `json:"fileName"`
+	ContentType string `json:"contentType"`
+	Size        int64  `json:"size"`
+	Checksum    string `json:"checksum"`
+	CreatedAt   string `json:"createdAt"`
+}
+
+func FromAttachment(model attachment.Attachment) AttachmentResponse {
+	return AttachmentResponse{
+		ID:          model.ID,
+		TaskUUID:    model.TaskUUID,
+		FileName:    model.FileName,
+		ContentType: model.ContentType,
+		Size:        model.Size,
+		Checksum:    model.Checksum,
+		CreatedAt:   model.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func FromAttachments(list []attachment.Attachment) []AttachmentResponse {
+	result := make([]AttachmentResponse, 0, len(list))
+	for _, a := range list {
+		result = append(result, FromAttachment(a))
+	}
+	return result
+}
@@ -0,0 +1,19 @@
+package dto
+
+type RegisterRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type LoginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// CreateTokenRequest issues a personal access token. ExpiresAt is RFC 3339;
+// leave it out for a token that does not expire.
+type CreateTokenRequest struct {
+	Name      string   `json:"name" binding:"required,max=128"`
+	Scopes    []string `json:"scopes" binding:"required,min=1,dive,oneof=read write admin"`
+	ExpiresAt *string  `json:"expiresAt"`
+}
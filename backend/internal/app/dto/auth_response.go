@@ -0,0 +1,83 @@
+package dto
+
+import (
+	"time"
+
+	"todolist/backend/internal/domain/user"
+)
+
+type UserResponse struct {
+	ID        uint64 `json:"id"`
+	Username  string `json:"username"`
+	CreatedAt string `json:"createdAt"`
+}
+
+type SessionResponse struct {
+	Token     string       `json:"token"`
+	ExpiresAt string       `json:"expiresAt"`
+	User      UserResponse `json:"user"`
+}
+
+func FromUser(model user.User) UserResponse {
+	return UserResponse{
+		ID:        model.ID,
+		Username:  model.Username,
+		CreatedAt: model.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func FromSession(token string, session user.Session, u user.User) SessionResponse {
+	return SessionResponse{
+		Token:     token,
+		ExpiresAt: session.ExpiresAt.Format(time.RFC3339),
+		User:      FromUser(u),
+	}
+}
+
+type TokenResponse struct {
+	ID         uint64   `json:"id"`
+	Name       string   `json:"name"`
+	Prefix     string   `json:"prefix"`
+	Scopes     []string `json:"scopes"`
+	ExpiresAt  *string  `json:"expiresAt"`
+	LastUsedAt *string  `json:"lastUsedAt"`
+	CreatedAt  string   `json:"createdAt"`
+}
+
+// CreatedTokenResponse is only returned on creation, the one time the plain
+// token is available.
+type CreatedTokenResponse struct {
+	TokenResponse
+	Token string `json:"token"`
+}
+
+func FromToken(model user.APIToken) TokenResponse {
+	scopes := make([]string, 0, len(model.ScopeList()))
+	for _, s := range model.ScopeList() {
+		scopes = append(scopes, string(s))
+	}
+	resp := TokenResponse{
+		ID:        model.ID,
+		Name:      model.Name,
+		Prefix:    model.Prefix,
+		Scopes:    scopes,
+		CreatedAt: model.CreatedAt.Format(time.RFC3339),
+	}
+	if model.ExpiresAt != nil {
+		formatted := model.ExpiresAt.Format(time.RFC3339)
+		resp.ExpiresAt = &formatted
+	}
+	if model.LastUsedAt != nil {
+		formatted := model.LastUsedAt.Format(time.RFC3339)
+		resp.LastUsedAt = &formatted
+	}
+	return resp
+}
+
+func FromTokens(list []user.APIToken) []TokenResponse {
+	result := make([]TokenResponse, 0, len(list))
+	for _, t := range list {
+		result = append(result, FromToken(t))
+	}
+	return result
+}
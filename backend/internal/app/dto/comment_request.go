@@ -0,0 +1,10 @@
+package dto
+
+type CommentRequest struct {
+	Body string `json:"body" binding:"required"`
+}
+
+type ActivityQuery struct {
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=200"`
+	Before uint64 `form:"before"`
+}
@@ -0,0 +1,77 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	domain "todolist/backend/internal/domain/task"
+)
+
+type CommentResponse struct {
+	ID        uint64  `json:"id"`
+	TaskUUID  string  `json:"taskUuid"`
+	AuthorID  uint64  `json:"authorId"`
+	Author    string  `json:"author"`
+	Body      string  `json:"body"`
+	Edited    bool    `json:"edited"`
+	EditedAt  *string `json:"editedAt,omitempty"`
+	CreatedAt string  `json:"createdAt"`
+	UpdatedAt string  `json:"updatedAt"`
+}
+
+type ActivityResponse struct {
+	ID        uint64          `json:"id"`
+	TaskUUID  string          `json:"taskUuid"`
+	Action    string          `json:"action"`
+	Actor     string          `json:"actor"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+	CreatedAt string          `json:"createdAt"`
+}
+
+func FromComment(model domain.Comment) CommentResponse {
+	resp := CommentResponse{
+		ID:        model.ID,
+		TaskUUID:  model.TaskUUID,
+		AuthorID:  model.AuthorID,
+		Author:    model.Author,
+		Body:      model.Body,
+		Edited:    model.EditedAt != nil,
+		CreatedAt: model.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: model.UpdatedAt.Format(time.RFC3339),
+	}
+	if model.EditedAt != nil {
+		formatted := model.EditedAt.Format(time.RFC3339)
+		resp.EditedAt = &formatted
+	}
+	return resp
+}
+
+func FromComments(list []domain.Comment) []CommentResponse {
+	result := make([]CommentResponse, 0, len(list))
+	for _, c := range list {
+		result = append(result, FromComment(c))
+	}
+	return result
+}
+
+func FromActivity(model domain.ActivityLog) ActivityResponse {
+	resp := ActivityResponse{
+		ID:        model.ID,
+		TaskUUID:  model.TaskUUID,
+		Action:    model.Action,
+		Actor:     model.Actor,
+		CreatedAt: model.CreatedAt.Format(time.RFC3339),
+	}
+	if model.Payload != "" {
+		resp.Payload = json.RawMessage(model.Payload)
+	}
+	return resp
+}
+
+func FromActivities(list []domain.ActivityLog) []ActivityResponse {
+	result := make([]ActivityResponse, 0, len(list))
+	for _, a := range list {
+		result = append(result, FromActivity(a))
+	}
+	return result
+}
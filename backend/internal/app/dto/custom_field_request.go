@@ -0,0 +1,18 @@
+package dto
+
+type CreateCustomFieldRequest struct {
+	Key         string   `json:"key" binding:"required,min=1,max=64"`
+	Name        string   `json:"name" binding:"required,min=1,max=128"`
+	Type        string   `json:"type" binding:"required,oneof=text number date select url checkbox"`
+	Options     []string `json:"options"`
+	WorkspaceID *uint64  `json:"workspaceId"`
+}
+
+type UpdateCustomFieldRequest struct {
+	Name    *string   `json:"name" binding:"omitempty,min=1,max=128"`
+	Options *[]string `json:"options"`
+}
+
+type CustomFieldListQuery struct {
+	Workspace *uint64 `form:"workspace"`
+}
@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"time"
+
+	"todolist/backend/internal/domain/customfield"
+)
+
+type CustomFieldResponse struct {
+	ID          uint64   `json:"id"`
+	WorkspaceID uint64   `json:"workspaceId"`
+	Key         string   `json:"key"`
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Options     []string `json:"options,omitempty"`
+	CreatedAt   string   `json:"createdAt"`
+	UpdatedAt   string   `json:"updatedAt"`
+}
+
+func FromCustomField(model customfield.Field) CustomFieldResponse {
+	return CustomFieldResponse{
+		ID:          model.ID,
+		WorkspaceID: model.WorkspaceID,
+		Key:         model.Key,
+		Name:        model.Name,
+		Type:        string(model.Type),
+		Options:     model.Choices(),
+		CreatedAt:   model.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   model.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func FromCustomFields(list []customfield.Field) []CustomFieldResponse {
+	result := make([]CustomFieldResponse, 0, len(list))
+	for _, f := range list {
+		result = append(result, FromCustomField(f))
+	}
+	return result
+}
@@ -0,0 +1,28 @@
+package dto
+
+import (
+	"strconv"
+	"time"
+
+	"todolist/backend/internal/pkg/events"
+)
+
+type EventResponse struct {
+	ID          string      `json:"id"`
+	Type        string      `json:"type"`
+	WorkspaceID uint64      `json:"workspaceId"`
+	ActorID     uint64      `json:"actorId,omitempty"`
+	At          string      `json:"at"`
+	Data        interface{} `json:"data,omitempty"`
+}
+
+func FromEvent(e events.Event) EventResponse {
+	return EventResponse{
+		ID:          strconv.FormatUint(e.ID, 10),
+		Type:        e.Type,
+		WorkspaceID: e.WorkspaceID,
+		ActorID:     e.ActorID,
+		At:          e.At.Format(time.RFC3339Nano),
+		Data:        e.Data,
+	}
+}
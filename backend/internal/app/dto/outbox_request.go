@@ -0,0 +1,11 @@
+package dto
+
+type OutboxMessagesQuery struct {
+	After uint64 `form:"after"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=500"`
+}
+
+// ReplayOutboxRequest names the first offset the consumer gets again.
+type ReplayOutboxRequest struct {
+	Offset uint64 `json:"offset" binding:"required,min=1"`
+}
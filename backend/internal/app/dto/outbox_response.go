@@ -0,0 +1,68 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"todolist/backend/internal/domain/outbox"
+)
+
+type OutboxStatusResponse struct {
+	Head      uint64                   `json:"head"`
+	Consumers []OutboxConsumerResponse `json:"consumers"`
+}
+
+type OutboxConsumerResponse struct {
+	Name       string  `json:"name"`
+	LastOffset uint64  `json:"lastOffset"`
+	Lag        uint64  `json:"lag"`
+	UpdatedAt  *string `json:"updatedAt"`
+}
+
+type OutboxMessageResponse struct {
+	Offset      uint64          `json:"offset"`
+	EventID     string          `json:"eventId"`
+	Type        string          `json:"type"`
+	WorkspaceID uint64          `json:"workspaceId"`
+	ActorID     uint64          `json:"actorId,omitempty"`
+	OccurredAt  string          `json:"occurredAt"`
+	Data        json.RawMessage `json:"data"`
+	CreatedAt   string          `json:"createdAt"`
+}
+
+func FromOutboxConsumer(model outbox.ConsumerStatus, head uint64) OutboxConsumerResponse {
+	resp := OutboxConsumerResponse{Name: model.Name, LastOffset: model.LastOffset}
+	if head > model.LastOffset {
+		resp.Lag = head - model.LastOffset
+	}
+	if !model.UpdatedAt.IsZero() {
+		v := model.UpdatedAt.Format(time.RFC3339)
+		resp.UpdatedAt = &v
+	}
+	return resp
+}
+
+func FromOutboxStatus(head uint64, consumers []outbox.ConsumerStatus) OutboxStatusResponse {
+	resp := OutboxStatusResponse{Head: head, Consumers: make([]OutboxConsumerResponse, 0, len(consumers))}
+	for _, c := range consumers {
+		resp.Consumers = append(resp.Consumers, FromOutboxConsumer(c, head))
+	}
+	return resp
+}
+
+func FromOutboxMessages(list []outbox.Message) []OutboxMessageResponse {
+	result := make([]OutboxMessageResponse, 0, len(list))
+	for _, m := range list {
+		result = append(result, OutboxMessageResponse{
+			Offset:      m.ID,
+			EventID:     m.EventID,
+			Type:        m.Type,
+			WorkspaceID: m.WorkspaceID,
+			ActorID:     m.ActorID,
+			OccurredAt:  m.OccurredAt.Format(time.RFC3339Nano),
+			Data:        json.RawMessage(m.Payload),
+			CreatedAt:   m.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return result
+}
@@ -0,0 +1,17 @@
+package dto
+
+type CreateProjectRequest struct {
+	Name        string  `json:"name" binding:"required,min=1,max=128"`
+	Color       *string `json:"color" binding:"omitempty,hexcolor"`
+	WorkspaceID *uint64 `json:"workspaceId"`
+}
+
+type UpdateProjectRequest struct {
+	Name  *string        `json:"name" binding:"omitempty,min=1,max=128"`
+	Color NullableString `json:"color"`
+}
+
+type ProjectListQuery struct {
+	Workspace       *uint64 `form:"workspace"`
+	IncludeArchived bool    `form:"includeArchived"`
+}
@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"time"
+
+	"todolist/backend/internal/domain/project"
+)
+
+type ProjectResponse struct {
+	UUID        string  `json:"uuid"`
+	WorkspaceID uint64  `json:"workspaceId"`
+	Name        string  `json:"name"`
+	Color       *string `json:"color,omitempty"`
+	Archived    bool    `json:"archived"`
+	CreatedAt   string  `json:"createdAt"`
+	UpdatedAt   string  `json:"updatedAt"`
+}
+
+func FromProject(model project.Project) ProjectResponse {
+	return ProjectResponse{
+		UUID:        model.UUID,
+		WorkspaceID: model.WorkspaceID,
+		Name:        model.Name,
+		Color:       model.Color,
+		Archived:    model.Archived,
+		CreatedAt:   model.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   model.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func FromProjects(list []project.Project) []ProjectResponse {
+	result := make([]ProjectResponse, 0, len(list))
+	for _, p := range list {
+		result = append(result, FromProject(p))
+	}
+	return result
+}
@@ -0,0 +1,104 @@
+package dto
+
+import (
+	domain "todolist/backend/internal/domain/task"
+)
+
+type EstimateReportItemResponse struct {
+	UUID            string   `json:"uuid"`
+	Title           string   `json:"title"`
+	Estimate        int      `json:"estimate"`
+	EstimateUnit    string   `json:"estimateUnit"`
+	EstimateMinutes int      `json:"estimateMinutes"`
+	LeadTimeMinutes int      `json:"leadTimeMinutes"`
+	TrackedMinutes  int      `json:"trackedMinutes"`
+	TrackedRatio    *float64 `json:"trackedRatio,omitempty"`
+}
+
+type EstimateReportResponse struct {
+	From            string                       `json:"from"`
+	To              string                       `json:"to"`
+	Completed       int                          `json:"completed"`
+	Estimated       int                          `json:"estimated"`
+	EstimateMinutes int                          `json:"estimateMinutes"`
+	LeadTimeMinutes int                          `json:"leadTimeMinutes"`
+	TrackedMinutes  int                          `json:"trackedMinutes"`
+	TrackedRatio    *float64                     `json:"trackedRatio,omitempty"`
+	Items           []EstimateReportItemResponse `json:"items"`
+}
+
+type CapacityDayResponse struct {
+	Date            string `json:"date"`
+	CapacityMinutes int    `json:"capacityMinutes"`
+	DueMinutes      int    `json:"dueMinutes"`
+	OverCapacity    bool   `json:"overCapacity"`
+}
+
+type CapacityResponse struct {
+	WeekStart             string                `json:"weekStart"`
+	DailyCapacityMinutes  int                   `json:"dailyCapacityMinutes"`
+	WeeklyCapacityMinutes int                   `json:"weeklyCapacityMinutes"`
+	PlannedMinutes        int                   `json:"plannedMinutes"`
+	UnestimatedTasks      int                   `json:"unestimatedTasks"`
+	OverCapacity          bool                  `json:"overCapacity"`
+	Days                  []CapacityDayResponse `json:"days"`
+	Warnings              []string              `json:"warnings"`
+}
+
+func FromEstimateReport(report domain.EstimateReport) EstimateReportResponse {
+	resp := EstimateReportResponse{
+		From:            report.From.Format("2006-01-02"),
+		To:              report.To.AddDate(0, 0, -1).Format("2006-01-02"),
+		Completed:       report.Completed,
+		Estimated:       len(report.Items),
+		EstimateMinutes: report.EstimateMinutes,
+		LeadTimeMinutes: report.LeadTimeMinutes,
+		TrackedMinutes:  report.TrackedMinutes,
+		TrackedRatio:    ratio(report.TrackedMinutes, report.EstimateMinutes),
+		Items:           make([]EstimateReportItemResponse, 0, len(report.Items)),
+	}
+	for _, item := range report.Items {
+		resp.Items = append(resp.Items, EstimateReportItemResponse{
+			UUID:            item.Task.UUID,
+			Title:           item.Task.Title,
+			Estimate:        *item.Task.Estimate,
+			EstimateUnit:    string(item.Task.EstimateUnit),
+			EstimateMinutes: item.EstimateMinutes,
+			LeadTimeMinutes: item.LeadTimeMinutes,
+			TrackedMinutes:  item.TrackedMinutes,
+			TrackedRatio:    ratio(item.TrackedMinutes, item.EstimateMinutes),
+		})
+	}
+	return resp
+}
+
+func FromCapacity(report domain.CapacityReport) CapacityResponse {
+	resp := CapacityResponse{
+		WeekStart:             report.WeekStart.Format("2006-01-02"),
+		DailyCapacityMinutes:  report.DailyCapacityMinutes,
+		WeeklyCapacityMinutes: report.WeeklyCapacityMinutes,
+		PlannedMinutes:        report.PlannedMinutes,
+		UnestimatedTasks:      report.UnestimatedTasks,
+		OverCapacity:          len(report.Warnings) > 0,
+		Days:                  make([]CapacityDayResponse, 0, len(report.Days)),
+		Warnings:              report.Warnings,
+	}
+	for _, d := range report.Days {
+		resp.Days = append(resp.Days, CapacityDayResponse{
+			Date:            d.Date,
+			CapacityMinutes: d.CapacityMinutes,
+			DueMinutes:      d.DueMinutes,
+			OverCapacity:    d.DueMinutes > d.CapacityMinutes,
+		})
+	}
+	return resp
+}
+
+// ratio returns actual/estimate, or nil when there is nothing to compare with.
+func ratio(actual, estimate int) *float64 {
+	if estimate <= 0 {
+		return nil
+	}
+	r := float64(actual) / float64(estimate)
+	return &r
+}
@@ -0,0 +1,18 @@
+package dto
+
+type CreateTagRequest struct {
+	Name        string  `json:"name" binding:"required,min=1,max=64"`
+	WorkspaceID *uint64 `json:"workspaceId"`
+}
+
+type RenameTagRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=64"`
+}
+
+type MergeTagRequest struct {
+	TargetID uint64 `json:"targetId" binding:"required"`
+}
+
+type TagListQuery struct {
+	Workspace *uint64 `form:"workspace"`
+}
@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"time"
+
+	"todolist/backend/internal/domain/tag"
+)
+
+type TagResponse struct {
+	ID          uint64 `json:"id"`
+	WorkspaceID uint64 `json:"workspaceId"`
+	Name        string `json:"name"`
+	CreatedAt   string `json:"createdAt"`
+	UpdatedAt   string `json:"updatedAt"`
+}
+
+func FromTag(model tag.Tag) TagResponse {
+	return TagResponse{
+		ID:          model.ID,
+		WorkspaceID: model.WorkspaceID,
+		Name:        model.Name,
+		CreatedAt:   model.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   model.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func FromTags(list []tag.Tag) []TagResponse {
+	result := make([]TagResponse, 0, len(list))
+	for _, t := range list {
+		result = append(result, FromTag(t))
+	}
+	return result
+}
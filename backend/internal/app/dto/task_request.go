@@ -6,40 +6,97 @@ import (
 )
 
 type CreateTaskRequest struct {
-	Title      string  `json:"title" binding:"required,min=1,max=255"`
-	Notes      *string `json:"notes"`
-	Deadline   *string `json:"deadline"`
-	Status     *string `json:"status" binding:"omitempty,oneof=now future history"`
-	SortWeight *int64  `json:"sortWeight"`
-	ParentUUID *string `json:"parentUuid"`
+	Title        string         `json:"title" binding:"required,min=1,max=255"`
+	Notes        *string        `json:"notes"`
+	Deadline     *string        `json:"deadline"`
+	Status       *string        `json:"status" binding:"omitempty,max=32"`
+	Priority     *string        `json:"priority" binding:"omitempty,oneof=none low medium high urgent"`
+	Estimate     *int           `json:"estimate" binding:"omitempty,min=0,max=100000"`
+	EstimateUnit *string        `json:"estimateUnit" binding:"omitempty,oneof=minutes points"`
+	SortWeight   *int64         `json:"sortWeight"`
+	ParentUUID   *string        `json:"parentUuid"`
+	ProjectUUID  *string        `json:"projectUuid"`
+	WorkspaceID  *uint64        `json:"workspaceId"`
+	AssigneeID   *uint64        `json:"assigneeId"`
+	TagIDs       []uint64       `json:"tagIds"`
+	CustomFields map[string]any `json:"customFields"`
 }
 
 type UpdateTaskRequest struct {
-	Title    *string        `json:"title"`
-	Notes    NullableString `json:"notes"`
-	Deadline NullableDate   `json:"deadline"`
+	Title        *string        `json:"title"`
+	Notes        NullableString `json:"notes"`
+	Deadline     NullableDate   `json:"deadline"`
+	Priority     *string        `json:"priority" binding:"omitempty,oneof=none low medium high urgent"`
+	Estimate     NullableInt    `json:"estimate"`
+	EstimateUnit *string        `json:"estimateUnit" binding:"omitempty,oneof=minutes points"`
+	TagIDs       *[]uint64      `json:"tagIds"`
+	CustomFields map[string]any `json:"customFields"`
 }
 
 type StatusUpdateRequest struct {
-	Status      string  `json:"status" binding:"required,oneof=now future history"`
+	Status      string  `json:"status" binding:"required,max=32"`
 	SortWeight  *int64  `json:"sortWeight"`
 	CompletedAt *string `json:"completedAt"`
 }
 
 type BulkOperationRequest struct {
-	IDs []string `json:"ids" binding:"required,min=1,dive,required"`
+	IDs         []string `json:"ids" binding:"required,min=1,dive,required"`
+	ProjectUUID *string  `json:"projectUuid"`
 }
 
 type BulkMoveRequest struct {
-	IDs    []string `json:"ids" binding:"required,min=1,dive,required"`
-	Status string   `json:"targetStatus" binding:"required,oneof=now future history"`
+	IDs         []string `json:"ids" binding:"required,min=1,dive,required"`
+	Status      string   `json:"targetStatus" binding:"required,max=32"`
+	ProjectUUID *string  `json:"projectUuid"`
 }
 
-type OrderUpdateRequest struct {
-	Status     string   `json:"status" binding:"required,oneof=now future history"`
+type BulkTagRequest struct {
+	IDs         []string `json:"ids" binding:"required,min=1,dive,required"`
+	TagIDs      []uint64 `json:"tagIds" binding:"required,min=1,dive,required"`
+	ProjectUUID *string  `json:"projectUuid"`
+}
+
+type BulkAssignRequest struct {
+	IDs         []string `json:"ids" binding:"required,min=1,dive,required"`
+	AssigneeID  uint64   `json:"assigneeId" binding:"required"`
+	ProjectUUID *string  `json:"projectUuid"`
+}
+
+type AssignedOrderRequest struct {
 	OrderedIDs []string `json:"orderedIds" binding:"required,min=1,dive,required"`
 }
 
+type OrderUpdateRequest struct {
+	Status      string   `json:"status" binding:"required,max=32"`
+	OrderedIDs  []string `json:"orderedIds" binding:"required,min=1,dive,required"`
+	ProjectUUID *string  `json:"projectUuid"`
+}
+
+type ReparentRequest struct {
+	ParentUUID *string `json:"parentUuid"`
+}
+
+type MoveProjectRequest struct {
+	ProjectUUID *string `json:"projectUuid"`
+}
+
+type AddDependencyRequest struct {
+	BlockerUUID string `json:"blockerUuid" binding:"required"`
+}
+
+type ChecklistItemRequest struct {
+	Text string `json:"text" binding:"required,min=1,max=500"`
+}
+
+type UpdateChecklistItemRequest struct {
+	Text    *string `json:"text" binding:"omitempty,min=1,max=500"`
+	Checked *bool   `json:"checked"`
+}
+
+type ChecklistOrderRequest struct {
+	OrderedIDs []uint64 `json:"orderedIds" binding:"required,min=1,dive,required"`
+}
+
 type UndoRequest struct {
 	Token string `json:"token" binding:"required"`
 }
@@ -63,6 +120,25 @@ func (ns *NullableString) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+type NullableInt struct {
+	Value *int
+	Set   bool
+}
+
+func (ni *NullableInt) UnmarshalJSON(data []byte) error {
+	ni.Set = true
+	if string(data) == "null" {
+		ni.Value = nil
+		return nil
+	}
+	var n int
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	ni.Value = &n
+	return nil
+}
+
 type NullableDate struct {
 	Value *time.Time
 	Set   bool
@@ -91,8 +167,47 @@ func (nd *NullableDate) UnmarshalJSON(data []byte) error {
 }
 
 type ListQuery struct {
-	Status   string `form:"status"`
-	Keyword  string `form:"keyword"`
-	Page     int    `form:"page"`
-	PageSize int    `form:"pageSize"`
+	Status      string   `form:"status"`
+	Priority    string   `form:"priority" binding:"omitempty,oneof=none low medium high urgent"`
+	Sort        string   `form:"sort" binding:"omitempty,oneof=deadline urgency progress completed manual"`
+	Order       string   `form:"order" binding:"omitempty,oneof=asc desc"`
+	Blocked     *bool    `form:"blocked"`
+	ProgressMin *int     `form:"progressMin" binding:"omitempty,min=0,max=100"`
+	ProgressMax *int     `form:"progressMax" binding:"omitempty,min=0,max=100"`
+	Depth       int      `form:"depth" binding:"omitempty,min=1,max=10"`
+	Project     string   `form:"project"`
+	Workspace   *uint64  `form:"workspace"`
+	Assignee    string   `form:"assignee"`
+	Keyword     string   `form:"keyword"`
+	Tags        []string `form:"tag"`
+	Page        int      `form:"page"`
+	PageSize    int      `form:"pageSize"`
+}
+
+type DailyPlanQuery struct {
+	Limit   int    `form:"limit" binding:"omitempty,min=1,max=50"`
+	Sort    string `form:"sort" binding:"omitempty,oneof=deadline urgency"`
+	Project string `form:"project"`
+}
+
+type TreeQuery struct {
+	Depth int `form:"depth" binding:"omitempty,min=1,max=10"`
+}
+
+type EstimateReportQuery struct {
+	From    string `form:"from" binding:"required"`
+	To      string `form:"to"`
+	Project string `form:"project"`
+}
+
+type CapacityQuery struct {
+	Week    string `form:"week"`
+	Project string `form:"project"`
+}
+
+type DuplicateTaskRequest struct {
+	IncludeChildren bool    `json:"includeChildren"`
+	ResetDeadline   bool    `json:"resetDeadline"`
+	Status          *string `json:"targetStatus" binding:"omitempty,max=32"`
+	Title           *string `json:"title" binding:"omitempty,min=1,max=255"`
 }
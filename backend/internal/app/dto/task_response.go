@@ -7,17 +7,42 @@ import (
 )
 
 type TaskResponse struct {
-	UUID        string         `json:"uuid"`
-	ParentUUID  *string        `json:"parentUuid,omitempty"`
-	Children    []TaskResponse `json:"children,omitempty"`
-	Title       string         `json:"title"`
-	Notes       *string        `json:"notes,omitempty"`
-	Deadline    *string        `json:"deadline,omitempty"`
-	Status      string         `json:"status"`
-	SortWeight  int64          `json:"sortWeight"`
-	CreatedAt   string         `json:"createdAt"`
-	UpdatedAt   string         `json:"updatedAt"`
-	CompletedAt *string        `json:"completedAt,omitempty"`
+	UUID                string                  `json:"uuid"`
+	WorkspaceID         uint64                  `json:"workspaceId"`
+	AssigneeID          *uint64                 `json:"assigneeId,omitempty"`
+	ParentUUID          *string                 `json:"parentUuid,omitempty"`
+	ProjectUUID         *string                 `json:"projectUuid,omitempty"`
+	Children            []TaskResponse          `json:"children,omitempty"`
+	Tags                []TagResponse           `json:"tags,omitempty"`
+	BlockedBy           []string                `json:"blockedBy,omitempty"`
+	Blocks              []string                `json:"blocks,omitempty"`
+	Checklist           []ChecklistItemResponse `json:"checklist,omitempty"`
+	CustomFields        map[string]any          `json:"customFields,omitempty"`
+	ChildCount          int64                   `json:"childCount"`
+	CompletedChildCount int64                   `json:"completedChildCount"`
+	Progress            *int                    `json:"progress,omitempty"`
+	Title               string                  `json:"title"`
+	Notes               *string                 `json:"notes,omitempty"`
+	Deadline            *string                 `json:"deadline,omitempty"`
+	Status              string                  `json:"status"`
+	Priority            string                  `json:"priority"`
+	Estimate            *int                    `json:"estimate,omitempty"`
+	EstimateUnit        string                  `json:"estimateUnit,omitempty"`
+	Urgency             int                     `json:"urgency"`
+	TrackedSeconds      int64                   `json:"trackedSeconds"`
+	TimerRunning        bool                    `json:"timerRunning"`
+	SortWeight          int64                   `json:"sortWeight"`
+	CreatedAt           string                  `json:"createdAt"`
+	UpdatedAt           string                  `json:"updatedAt"`
+	CompletedAt         *string                 `json:"completedAt,omitempty"`
+	DeletedAt           *string                 `json:"deletedAt,omitempty"`
+}
+
+type ChecklistItemResponse struct {
+	ID       uint64 `json:"id"`
+	Text     string `json:"text"`
+	Checked  bool   `json:"checked"`
+	Position int64  `json:"position"`
 }
 
 type TaskListResponse struct {
@@ -27,26 +52,70 @@ type TaskListResponse struct {
 
 func FromTask(model domain.Task) TaskResponse {
 	resp := TaskResponse{
-		UUID:       model.UUID,
-		ParentUUID: model.ParentUUID,
-		Title:      model.Title,
-		Notes:      model.Notes,
-		Status:     string(model.Status),
-		SortWeight: model.SortWeight,
-		CreatedAt:  model.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:  model.UpdatedAt.Format(time.RFC3339),
+		UUID:                model.UUID,
+		WorkspaceID:         model.WorkspaceID,
+		AssigneeID:          model.AssigneeID,
+		ParentUUID:          model.ParentUUID,
+		ProjectUUID:         model.ProjectUUID,
+		Title:               model.Title,
+		Notes:               model.Notes,
+		Status:              string(model.Status),
+		Priority:            string(model.Priority),
+		Urgency:             model.Urgency(time.Now()),
+		TrackedSeconds:      model.TrackedTime(time.Now()),
+		TimerRunning:        model.RunningSince != nil,
+		ChildCount:          model.ChildCount,
+		CompletedChildCount: model.CompletedChildCount,
+		Progress:            model.Progress(),
+		SortWeight:          model.SortWeight,
+		CreatedAt:           model.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:           model.UpdatedAt.Format(time.RFC3339),
 	}
 	if model.Deadline != nil {
 		formatted := model.Deadline.Format("2006-01-02")
 		resp.Deadline = &formatted
 	}
+	if model.Estimate != nil {
+		resp.Estimate = model.Estimate
+		resp.EstimateUnit = string(model.EstimateUnit)
+	}
 	if model.CompletedAt != nil {
 		formatted := model.CompletedAt.Format(time.RFC3339)
 		resp.CompletedAt = &formatted
 	}
+	if model.DeletedAt.Valid {
+		formatted := model.DeletedAt.Time.Format(time.RFC3339)
+		resp.DeletedAt = &formatted
+	}
 	if len(model.Children) > 0 {
 		resp.Children = FromTasks(model.Children)
 	}
+	if len(model.Tags) > 0 {
+		resp.Tags = FromTags(model.Tags)
+	}
+	for _, item := range model.Checklist {
+		resp.Checklist = append(resp.Checklist, ChecklistItemResponse{
+			ID:       item.ID,
+			Text:     item.Text,
+			Checked:  item.Checked,
+			Position: item.Position,
+		})
+	}
+	for _, v := range model.CustomValues {
+		if v.Field == nil {
+			continue
+		}
+		if resp.CustomFields == nil {
+			resp.CustomFields = make(map[string]any, len(model.CustomValues))
+		}
+		resp.CustomFields[v.Field.Key] = v.Field.Decode(v.Value)
+	}
+	for _, d := range model.BlockedBy {
+		resp.BlockedBy = append(resp.BlockedBy, d.BlockerUUID)
+	}
+	for _, d := range model.Blocks {
+		resp.Blocks = append(resp.Blocks, d.TaskUUID)
+	}
 	return resp
 }
 
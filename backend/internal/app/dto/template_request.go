@@ -0,0 +1,43 @@
+package dto
+
+type TemplateNode struct {
+	Title          string         `json:"title"`
+	Notes          *string        `json:"notes,omitempty"`
+	DeadlineOffset *int           `json:"deadlineOffset,omitempty"`
+	Priority       string         `json:"priority,omitempty"`
+	Estimate       *int           `json:"estimate,omitempty"`
+	EstimateUnit   string         `json:"estimateUnit,omitempty"`
+	Checklist      []string       `json:"checklist,omitempty"`
+	Children       []TemplateNode `json:"children,omitempty"`
+}
+
+type CreateTemplateRequest struct {
+	Name        string       `json:"name" binding:"required,min=1,max=255"`
+	Description *string      `json:"description"`
+	Root        TemplateNode `json:"root" binding:"required"`
+	WorkspaceID *uint64      `json:"workspaceId"`
+}
+
+type UpdateTemplateRequest struct {
+	Name        *string        `json:"name" binding:"omitempty,min=1,max=255"`
+	Description NullableString `json:"description"`
+	Root        *TemplateNode  `json:"root"`
+}
+
+type InstantiateTemplateRequest struct {
+	AnchorDate  *string           `json:"anchorDate"`
+	Variables   map[string]string `json:"variables"`
+	Status      *string           `json:"status" binding:"omitempty,max=32"`
+	ProjectUUID *string           `json:"projectUuid"`
+	ParentUUID  *string           `json:"parentUuid"`
+	WorkspaceID *uint64           `json:"workspaceId"`
+}
+
+type SaveTemplateRequest struct {
+	Name        string  `json:"name" binding:"required,min=1,max=255"`
+	Description *string `json:"description"`
+}
+
+type TemplateListQuery struct {
+	Workspace *uint64 `form:"workspace"`
+}
@@ -0,0 +1,81 @@
+package dto
+
+import (
+	"time"
+
+	"todolist/backend/internal/domain/task"
+	"todolist/backend/internal/domain/template"
+)
+
+type TemplateResponse struct {
+	ID          uint64       `json:"id"`
+	WorkspaceID uint64       `json:"workspaceId"`
+	Name        string       `json:"name"`
+	Description *string      `json:"description,omitempty"`
+	Variables   []string     `json:"variables"`
+	Root        TemplateNode `json:"root"`
+	CreatedAt   string       `json:"createdAt"`
+	UpdatedAt   string       `json:"updatedAt"`
+}
+
+func FromTemplate(model template.Template) (TemplateResponse, error) {
+	root, err := model.Root()
+	if err != nil {
+		return TemplateResponse{}, err
+	}
+	return TemplateResponse{
+		ID:          model.ID,
+		WorkspaceID: model.WorkspaceID,
+		Name:        model.Name,
+		Description: model.Description,
+		Variables:   template.Variables(root),
+		Root:        FromTemplateNode(root),
+		CreatedAt:   model.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   model.UpdatedAt.Format(time.RFC3339),
+	}, nil
+}
+
+func FromTemplates(list []template.Template) ([]TemplateResponse, error) {
+	result := make([]TemplateResponse, 0, len(list))
+	for _, t := range list {
+		resp, err := FromTemplate(t)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, resp)
+	}
+	return result, nil
+}
+
+func FromTemplateNode(n template.Node) TemplateNode {
+	node := TemplateNode{
+		Title:          n.Title,
+		Notes:          n.Notes,
+		DeadlineOffset: n.DeadlineOffset,
+		Priority:       string(n.Priority),
+		Estimate:       n.Estimate,
+		EstimateUnit:   string(n.EstimateUnit),
+		Checklist:      n.Checklist,
+	}
+	for _, child := range n.Children {
+		node.Children = append(node.Children, FromTemplateNode(child))
+	}
+	return node
+}
+
+// ToTemplateNode converts a request tree into the domain node type.
+func ToTemplateNode(n TemplateNode) template.Node {
+	node := template.Node{
+		Title:          n.Title,
+		Notes:          n.Notes,
+		DeadlineOffset: n.DeadlineOffset,
+		Priority:       task.Priority(n.Priority),
+		Estimate:       n.Estimate,
+		EstimateUnit:   task.EstimateUnit(n.EstimateUnit),
+		Checklist:      n.Checklist,
+	}
+	for _, child := range n.Children {
+		node.Children = append(node.Children, ToTemplateNode(child))
+	}
+	return node
+}
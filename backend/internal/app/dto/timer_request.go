@@ -0,0 +1,23 @@
+package dto
+
+type StartTimerRequest struct {
+	Note *string `json:"note" binding:"omitempty,max=500"`
+}
+
+type CreateTimeEntryRequest struct {
+	StartedAt string  `json:"startedAt" binding:"required"`
+	EndedAt   string  `json:"endedAt" binding:"required"`
+	Note      *string `json:"note" binding:"omitempty,max=500"`
+}
+
+type UpdateTimeEntryRequest struct {
+	StartedAt *string        `json:"startedAt"`
+	EndedAt   *string        `json:"endedAt"`
+	Note      NullableString `json:"note"`
+}
+
+type TimesheetQuery struct {
+	From   string `form:"from" binding:"required"`
+	To     string `form:"to"`
+	Format string `form:"format" binding:"omitempty,oneof=json csv"`
+}
@@ -0,0 +1,65 @@
+package dto
+
+import (
+	"time"
+
+	domain "todolist/backend/internal/domain/task"
+)
+
+type TimeEntryResponse struct {
+	ID              uint64  `json:"id"`
+	TaskUUID        string  `json:"taskUuid"`
+	StartedAt       string  `json:"startedAt"`
+	EndedAt         *string `json:"endedAt,omitempty"`
+	DurationSeconds int64   `json:"durationSeconds"`
+	Running         bool    `json:"running"`
+	Note            *string `json:"note,omitempty"`
+}
+
+type TimesheetDayResponse struct {
+	Date         string                 `json:"date"`
+	TotalSeconds int64                  `json:"totalSeconds"`
+	Tasks        []TimesheetRowResponse `json:"tasks"`
+}
+
+type TimesheetRowResponse struct {
+	TaskUUID string `json:"taskUuid"`
+	Title    string `json:"title"`
+	Seconds  int64  `json:"seconds"`
+}
+
+func FromTimeEntry(model domain.TimeEntry) TimeEntryResponse {
+	resp := TimeEntryResponse{
+		ID:              model.ID,
+		TaskUUID:        model.TaskUUID,
+		StartedAt:       model.StartedAt.Format(time.RFC3339),
+		DurationSeconds: model.Duration(time.Now()),
+		Running:         model.EndedAt == nil,
+		Note:            model.Note,
+	}
+	if model.EndedAt != nil {
+		formatted := model.EndedAt.Format(time.RFC3339)
+		resp.EndedAt = &formatted
+	}
+	return resp
+}
+
+func FromTimeEntries(list []domain.TimeEntry) []TimeEntryResponse {
+	result := make([]TimeEntryResponse, 0, len(list))
+	for _, e := range list {
+		result = append(result, FromTimeEntry(e))
+	}
+	return result
+}
+
+func FromTimesheet(days []domain.TimesheetDay) []TimesheetDayResponse {
+	result := make([]TimesheetDayResponse, 0, len(days))
+	for _, d := range days {
+		rows := make([]TimesheetRowResponse, 0, len(d.Tasks))
+		for _, r := range d.Tasks {
+			rows = append(rows, TimesheetRowResponse{TaskUUID: r.TaskUUID, Title: r.Title, Seconds: r.Seconds})
+		}
+		result = append(result, TimesheetDayResponse{Date: d.Date, TotalSeconds: d.TotalSeconds, Tasks: rows})
+	}
+	return result
+}
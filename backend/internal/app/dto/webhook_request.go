@@ -0,0 +1,20 @@
+package dto
+
+type CreateWebhookRequest struct {
+	URL    string   `json:"url" binding:"required,max=2048"`
+	Secret string   `json:"secret" binding:"max=255"`
+	Events []string `json:"events"`
+	Active *bool    `json:"active"`
+}
+
+type UpdateWebhookRequest struct {
+	URL    *string   `json:"url" binding:"omitempty,max=2048"`
+	Secret *string   `json:"secret" binding:"omitempty,max=255"`
+	Events *[]string `json:"events"`
+	Active *bool     `json:"active"`
+}
+
+type DeliveryQuery struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending succeeded failed"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
@@ -0,0 +1,130 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"todolist/backend/internal/domain/webhook"
+)
+
+type WebhookResponse struct {
+	ID          uint64   `json:"id"`
+	WorkspaceID uint64   `json:"workspaceId"`
+	URL         string   `json:"url"`
+	Events      []string `json:"events"`
+	Active      bool     `json:"active"`
+	CreatedBy   uint64   `json:"createdBy"`
+	CreatedAt   string   `json:"createdAt"`
+	UpdatedAt   string   `json:"updatedAt"`
+}
+
+// WebhookSecretResponse is only returned when the signing secret was just
+// set, so it is not handed out on every read.
+type WebhookSecretResponse struct {
+	WebhookResponse
+	Secret string `json:"secret"`
+}
+
+type DeliveryResponse struct {
+	ID             uint64            `json:"id"`
+	WebhookID      uint64            `json:"webhookId"`
+	EventID        string            `json:"eventId"`
+	EventType      string            `json:"eventType"`
+	Status         string            `json:"status"`
+	Attempts       int               `json:"attempts"`
+	NextAttemptAt  *string           `json:"nextAttemptAt"`
+	LastAttemptAt  *string           `json:"lastAttemptAt"`
+	ResponseStatus int               `json:"responseStatus,omitempty"`
+	LastError      string            `json:"lastError,omitempty"`
+	RedeliveryOf   *uint64           `json:"redeliveryOf,omitempty"`
+	Payload        json.RawMessage   `json:"payload,omitempty"`
+	AttemptLog     []AttemptResponse `json:"attemptLog,omitempty"`
+	CreatedAt      string            `json:"createdAt"`
+}
+
+type AttemptResponse struct {
+	Number     int    `json:"number"`
+	StatusCode int    `json:"statusCode,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"durationMs"`
+	CreatedAt  string `json:"createdAt"`
+}
+
+func FromWebhook(model webhook.Subscription) WebhookResponse {
+	events := model.EventList()
+	if events == nil {
+		events = []string{"*"}
+	}
+	return WebhookResponse{
+		ID:          model.ID,
+		WorkspaceID: model.WorkspaceID,
+		URL:         model.URL,
+		Events:      events,
+		Active:      model.Active,
+		CreatedBy:   model.CreatedBy,
+		CreatedAt:   model.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   model.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func FromWebhooks(list []webhook.Subscription) []WebhookResponse {
+	result := make([]WebhookResponse, 0, len(list))
+	for _, sub := range list {
+		result = append(result, FromWebhook(sub))
+	}
+	return result
+}
+
+func FromWebhookWithSecret(model webhook.Subscription) WebhookSecretResponse {
+	return WebhookSecretResponse{WebhookResponse: FromWebhook(model), Secret: model.Secret}
+}
+
+// FromDelivery describes a delivery; the payload and attempt log are only
+// included when the delivery is fetched on its own.
+func FromDelivery(model webhook.Delivery) DeliveryResponse {
+	resp := DeliveryResponse{
+		ID:             model.ID,
+		WebhookID:      model.WebhookID,
+		EventID:        model.EventID,
+		EventType:      model.EventType,
+		Status:         string(model.Status),
+		Attempts:       model.Attempts,
+		ResponseStatus: model.ResponseStatus,
+		LastError:      model.LastError,
+		RedeliveryOf:   model.RedeliveryOf,
+		CreatedAt:      model.CreatedAt.Format(time.RFC3339),
+	}
+	if model.Status == webhook.StatusPending {
+		v := model.NextAttemptAt.Format(time.RFC3339)
+		resp.NextAttemptAt = &v
+	}
+	if model.LastAttemptAt != nil {
+		v := model.LastAttemptAt.Format(time.RFC3339)
+		resp.LastAttemptAt = &v
+	}
+	return resp
+}
+
+func FromDeliveries(list []webhook.Delivery) []DeliveryResponse {
+	result := make([]DeliveryResponse, 0, len(list))
+	for _, d := range list {
+		result = append(result, FromDelivery(d))
+	}
+	return result
+}
+
+func FromDeliveryDetail(model webhook.Delivery, attempts []webhook.Attempt) DeliveryResponse {
+	resp := FromDelivery(model)
+	resp.Payload = json.RawMessage(model.Payload)
+	resp.AttemptLog = make([]AttemptResponse, 0, len(attempts))
+	for _, a := range attempts {
+		resp.AttemptLog = append(resp.AttemptLog, AttemptResponse{
+			Number:     a.Number,
+			StatusCode: a.StatusCode,
+			Error:      a.Error,
+			DurationMS: a.DurationMS,
+			CreatedAt:  a.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return resp
+}
@@ -0,0 +1,47 @@
+package dto
+
+import (
+	domain "todolist/backend/internal/domain/task"
+)
+
+type WorkflowStatusResponse struct {
+	Key         string   `json:"key"`
+	Name        string   `json:"name"`
+	Terminal    bool     `json:"terminal"`
+	DefaultSort string   `json:"defaultSort,omitempty"`
+	Transitions []string `json:"transitions"`
+}
+
+type WorkflowResponse struct {
+	Initial          string                   `json:"initial"`
+	Active           string                   `json:"active"`
+	Backlog          string                   `json:"backlog"`
+	CompletionStatus string                   `json:"completionStatus"`
+	Statuses         []WorkflowStatusResponse `json:"statuses"`
+}
+
+func FromWorkflow(w *domain.Workflow) WorkflowResponse {
+	resp := WorkflowResponse{
+		Initial:          string(w.Initial),
+		Active:           string(w.Active),
+		Backlog:          string(w.Backlog),
+		CompletionStatus: string(w.CompletionStatus()),
+		Statuses:         make([]WorkflowStatusResponse, 0, len(w.Statuses)),
+	}
+	for _, def := range w.Statuses {
+		transitions := make([]string, 0, len(w.Statuses))
+		for _, to := range w.Statuses {
+			if to.Key != def.Key && w.CanTransition(def.Key, to.Key) {
+				transitions = append(transitions, string(to.Key))
+			}
+		}
+		resp.Statuses = append(resp.Statuses, WorkflowStatusResponse{
+			Key:         string(def.Key),
+			Name:        def.Name,
+			Terminal:    def.Terminal,
+			DefaultSort: string(def.DefaultSort),
+			Transitions: transitions,
+		})
+	}
+	return resp
+}
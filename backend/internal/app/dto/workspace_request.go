@@ -0,0 +1,18 @@
+package dto
+
+type CreateWorkspaceRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=128"`
+}
+
+type RenameWorkspaceRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=128"`
+}
+
+type UpdateMemberRequest struct {
+	Role string `json:"role" binding:"required,oneof=owner editor viewer"`
+}
+
+type InviteRequest struct {
+	Username string `json:"username" binding:"required,max=64"`
+	Role     string `json:"role" binding:"required,oneof=owner editor viewer"`
+}
@@ -0,0 +1,93 @@
+package dto
+
+import (
+	"time"
+
+	"todolist/backend/internal/domain/workspace"
+)
+
+type WorkspaceResponse struct {
+	ID        uint64 `json:"id"`
+	Name      string `json:"name"`
+	Personal  bool   `json:"personal"`
+	Role      string `json:"role,omitempty"`
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+type MemberResponse struct {
+	UserID    uint64 `json:"userId"`
+	Username  string `json:"username"`
+	Role      string `json:"role"`
+	CreatedAt string `json:"createdAt"`
+}
+
+type InvitationResponse struct {
+	ID              uint64 `json:"id"`
+	WorkspaceID     uint64 `json:"workspaceId"`
+	WorkspaceName   string `json:"workspaceName,omitempty"`
+	InviteeID       uint64 `json:"inviteeId"`
+	InviteeUsername string `json:"inviteeUsername,omitempty"`
+	Role            string `json:"role"`
+	InvitedBy       uint64 `json:"invitedBy"`
+	ExpiresAt       string `json:"expiresAt"`
+	CreatedAt       string `json:"createdAt"`
+}
+
+func FromWorkspace(model workspace.Workspace) WorkspaceResponse {
+	return WorkspaceResponse{
+		ID:        model.ID,
+		Name:      model.Name,
+		Personal:  model.IsPersonal(),
+		Role:      string(model.Role),
+		CreatedAt: model.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: model.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func FromWorkspaces(list []workspace.Workspace) []WorkspaceResponse {
+	result := make([]WorkspaceResponse, 0, len(list))
+	for _, w := range list {
+		result = append(result, FromWorkspace(w))
+	}
+	return result
+}
+
+func FromMember(model workspace.Member) MemberResponse {
+	return MemberResponse{
+		UserID:    model.UserID,
+		Username:  model.Username,
+		Role:      string(model.Role),
+		CreatedAt: model.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func FromMembers(list []workspace.Member) []MemberResponse {
+	result := make([]MemberResponse, 0, len(list))
+	for _, m := range list {
+		result = append(result, FromMember(m))
+	}
+	return result
+}
+
+func FromInvitation(model workspace.Invitation) InvitationResponse {
+	return InvitationResponse{
+		ID:              model.ID,
+		WorkspaceID:     model.WorkspaceID,
+		WorkspaceName:   model.WorkspaceName,
+		InviteeID:       model.InviteeID,
+		InviteeUsername: model.InviteeUsername,
+		Role:            string(model.Role),
+		InvitedBy:       model.InvitedBy,
+		ExpiresAt:       model.ExpiresAt.Format(time.RFC3339),
+		CreatedAt:       model.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func FromInvitations(list []workspace.Invitation) []InvitationResponse {
+	result := make([]InvitationResponse, 0, len(list))
+	for _, inv := range list {
+		result = append(result, FromInvitation(inv))
+	}
+	return result
+}
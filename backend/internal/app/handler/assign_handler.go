@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"todolist/backend/internal/app/dto"
+	"todolist/backend/internal/domain/task"
+	"todolist/backend/internal/pkg/auth"
+	"todolist/backend/internal/pkg/response"
+)
+
+func (h *TaskHandler) BulkAssign(c *gin.Context) {
+	var req dto.BulkAssignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	tasks, undoToken, err := h.service.BulkAssign(c.Request.Context(), req.IDs, req.AssigneeID, req.ProjectUUID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto.TaskListResponse{Items: dto.FromTasks(tasks), Total: int64(len(tasks))}, undoToken)
+}
+
+func (h *TaskHandler) BulkUnassign(c *gin.Context) {
+	var req dto.BulkOperationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	tasks, undoToken, err := h.service.BulkUnassign(c.Request.Context(), req.IDs, req.ProjectUUID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto.TaskListResponse{Items: dto.FromTasks(tasks), Total: int64(len(tasks))}, undoToken)
+}
+
+// ReorderAssigned saves the caller's own order of the tasks assigned to them.
+func (h *TaskHandler) ReorderAssigned(c *gin.Context) {
+	var req dto.AssignedOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.service.ReorderAssigned(c.Request.Context(), req.OrderedIDs); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, gin.H{"orderedIds": req.OrderedIDs})
+}
+
+// applyAssigneeFilter reads assignee=me|none|<id> into filter. It writes the
+// error response and returns false on a bad value.
+func applyAssigneeFilter(c *gin.Context, value string, filter *task.ListFilter) bool {
+	switch value {
+	case "":
+	case "none":
+		filter.Unassigned = true
+	case "me":
+		p, ok := auth.FromContext(c.Request.Context())
+		if !ok {
+			response.Unauthorized(c, "authentication required")
+			return false
+		}
+		filter.AssigneeID = &p.UserID
+	default:
+		id, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			response.BadRequest(c, "invalid assignee")
+			return false
+		}
+		filter.AssigneeID = &id
+	}
+	return true
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"todolist/backend/internal/app/dto"
+	"todolist/backend/internal/domain/attachment"
+	"todolist/backend/internal/pkg/response"
+)
+
+const (
+	// multipartOverhead is the room left above the size limit for the form
+	// boundaries and headers around the file.
+	multipartOverhead = 1 << 20
+
+	DefaultTransferTimeout = 10 * time.Minute
+)
+
+type AttachmentHandler struct {
+	service         *attachment.Service
+	transferTimeout time.Duration
+}
+
+func NewAttachmentHandler(service *attachment.Service, transferTimeout time.Duration) *AttachmentHandler {
+	if transferTimeout <= 0 {
+		transferTimeout = DefaultTransferTimeout
+	}
+	return &AttachmentHandler{service: service, transferTimeout: transferTimeout}
+}
+
+// MaxUploadSize is the largest request body Upload accepts.
+func (h *AttachmentHandler) MaxUploadSize() int64 {
+	return h.service.MaxSize() + multipartOverhead
+}
+
+func (h *AttachmentHandler) List(c *gin.Context) {
+	attachments, err := h.service.List(c.Request.Context(), c.Param("uuid"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, dto.FromAttachments(attachments))
+}
+
+// Upload takes a multipart/form-data request with the file in the "file" part.
+func (h *AttachmentHandler) Upload(c *gin.Context) {
+	h.extendDeadlines(c)
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.MaxUploadSize())
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			response.Error(c, attachment.ErrTooLarge)
+			return
+		}
+		response.BadRequest(c, err.Error())
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	defer file.Close()
+
+	a, err := h.service.Upload(c.Request.Context(), c.Param("uuid"), attachment.UploadInput{
+		FileName:    header.Filename,
+		ContentType: header.Header.Get("Content-Type"),
+		Size:        header.Size,
+		Body:        file,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Created(c, dto.FromAttachment(*a))
+}
+
+// Download streams the attachment, honouring Range and conditional headers.
+func (h *AttachmentHandler) Download(c *gin.Context) {
+	id, ok := attachmentIDParam(c)
+	if !ok {
+		return
+	}
+	a, err := h.service.Get(c.Request.Context(), c.Param("uuid"), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	content := h.service.Open(c.Request.Context(), a)
+	defer content.Close()
+
+	h.extendDeadlines(c)
+	c.Header("Content-Type", a.ContentType)
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": a.FileName}))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("ETag", `"`+a.Checksum+`"`)
+	http.ServeContent(c.Writer, c.Request, a.FileName, a.CreatedAt, content)
+}
+
+func (h *AttachmentHandler) Delete(c *gin.Context) {
+	id, ok := attachmentIDParam(c)
+	if !ok {
+		return
+	}
+	if err := h.service.Delete(c.Request.Context(), c.Param("uuid"), id); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, gin.H{"id": id})
+}
+
+// extendDeadlines lets a transfer run for the transfer timeout instead of the
+// server's read and write timeouts, which are sized for JSON requests.
+func (h *AttachmentHandler) extendDeadlines(c *gin.Context) {
+	deadline := time.Now().Add(h.transferTimeout)
+	rc := http.NewResponseController(c.Writer)
+	_ = rc.SetReadDeadline(deadline)
+	_ = rc.SetWriteDeadline(deadline)
+}
+
+func attachmentIDParam(c *gin.Context) (uint64, bool) {
+	id, err := strconv.ParseUint(c.Param("attachmentId"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "invalid attachment id")
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"todolist/backend/internal/app/dto"
+	"todolist/backend/internal/app/middleware"
+	"todolist/backend/internal/domain/user"
+	"todolist/backend/internal/pkg/response"
+)
+
+type AuthHandler struct {
+	service      *user.Service
+	secureCookie bool
+}
+
+func NewAuthHandler(service *user.Service, secureCookie bool) *AuthHandler {
+	return &AuthHandler{service: service, secureCookie: secureCookie}
+}
+
+func (h *AuthHandler) Register(c *gin.Context) {
+	var req dto.RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	u, err := h.service.Register(c.Request.Context(), req.Username, req.Password)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Created(c, dto.FromUser(*u))
+}
+
+// Login returns the session token in the body for API clients and also sets
+// it as an HttpOnly cookie for browsers.
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req dto.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	token, session, u, err := h.service.Login(c.Request.Context(), req.Username, req.Password)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	h.setSessionCookie(c, token, int(time.Until(session.ExpiresAt).Seconds()))
+	response.Success(c, dto.FromSession(token, *session, *u))
+}
+
+func (h *AuthHandler) Logout(c *gin.Context) {
+	if err := h.service.Logout(c.Request.Context(), middleware.Credential(c)); err != nil {
+		response.Error(c, err)
+		return
+	}
+	h.setSessionCookie(c, "", -1)
+	response.Success(c, nil)
+}
+
+func (h *AuthHandler) Me(c *gin.Context) {
+	u, err := h.service.Me(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, dto.FromUser(*u))
+}
+
+func (h *AuthHandler) setSessionCookie(c *gin.Context, token string, maxAge int) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(middleware.SessionCookie, token, maxAge, "/", "", h.secureCookie, true)
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"todolist/backend/internal/app/dto"
+	"todolist/backend/internal/domain/task"
+	"todolist/backend/internal/pkg/response"
+)
+
+func (h *TaskHandler) AddChecklistItem(c *gin.Context) {
+	var req dto.ChecklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	updated, undoToken, err := h.service.AddChecklistItem(c.Request.Context(), c.Param("uuid"), req.Text)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, dto.FromTask(*updated), undoToken)
+}
+
+func (h *TaskHandler) UpdateChecklistItem(c *gin.Context) {
+	itemID, ok := checklistItemIDParam(c)
+	if !ok {
+		return
+	}
+	var req dto.UpdateChecklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	updated, undoToken, err := h.service.UpdateChecklistItem(c.Request.Context(), c.Param("uuid"), itemID, task.ChecklistItemPatch{
+		Text:    req.Text,
+		Checked: req.Checked,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto.FromTask(*updated), undoToken)
+}
+
+func (h *TaskHandler) ToggleChecklistItem(c *gin.Context) {
+	itemID, ok := checklistItemIDParam(c)
+	if !ok {
+		return
+	}
+
+	updated, undoToken, err := h.service.ToggleChecklistItem(c.Request.Context(), c.Param("uuid"), itemID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto.FromTask(*updated), undoToken)
+}
+
+func (h *TaskHandler) ReorderChecklist(c *gin.Context) {
+	var req dto.ChecklistOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	updated, undoToken, err := h.service.ReorderChecklist(c.Request.Context(), c.Param("uuid"), req.OrderedIDs)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto.FromTask(*updated), undoToken)
+}
+
+func (h *TaskHandler) DeleteChecklistItem(c *gin.Context) {
+	itemID, ok := checklistItemIDParam(c)
+	if !ok {
+		return
+	}
+
+	updated, undoToken, err := h.service.DeleteChecklistItem(c.Request.Context(), c.Param("uuid"), itemID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto.FromTask(*updated), undoToken)
+}
+
+func (h *TaskHandler) ConvertChecklistItem(c *gin.Context) {
+	itemID, ok := checklistItemIDParam(c)
+	if !ok {
+		return
+	}
+
+	subtask, undoToken, err := h.service.ConvertChecklistItem(c.Request.Context(), c.Param("uuid"), itemID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, dto.FromTask(*subtask), undoToken)
+}
+
+func checklistItemIDParam(c *gin.Context) (uint64, bool) {
+	id, err := strconv.ParseUint(c.Param("itemId"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "invalid checklist item id")
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"todolist/backend/internal/app/dto"
+	"todolist/backend/internal/pkg/response"
+)
+
+func (h *TaskHandler) ListComments(c *gin.Context) {
+	comments, err := h.service.ListComments(c.Request.Context(), c.Param("uuid"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, dto.FromComments(comments))
+}
+
+func (h *TaskHandler) AddComment(c *gin.Context) {
+	var req dto.CommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	comment, err := h.service.AddComment(c.Request.Context(), c.Param("uuid"), req.Body)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Created(c, dto.FromComment(*comment))
+}
+
+func (h *TaskHandler) EditComment(c *gin.Context) {
+	id, ok := commentIDParam(c)
+	if !ok {
+		return
+	}
+	var req dto.CommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	comment, err := h.service.EditComment(c.Request.Context(), c.Param("uuid"), id, req.Body)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, dto.FromComment(*comment))
+}
+
+func (h *TaskHandler) DeleteComment(c *gin.Context) {
+	id, ok := commentIDParam(c)
+	if !ok {
+		return
+	}
+	if err := h.service.DeleteComment(c.Request.Context(), c.Param("uuid"), id); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, gin.H{"id": id})
+}
+
+func (h *TaskHandler) Activity(c *gin.Context) {
+	var query dto.ActivityQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	logs, err := h.service.Activity(c.Request.Context(), c.Param("uuid"), query.Limit, query.Before)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, dto.FromActivities(logs))
+}
+
+func commentIDParam(c *gin.Context) (uint64, bool) {
+	id, err := strconv.ParseUint(c.Param("commentId"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "invalid comment id")
+		return 0, false
+	}
+	return id, true
+}
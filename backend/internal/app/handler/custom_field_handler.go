@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"todolist/backend/internal/app/dto"
+	"todolist/backend/internal/domain/customfield"
+	"todolist/backend/internal/pkg/response"
+)
+
+type CustomFieldHandler struct {
+	service *customfield.Service
+}
+
+func NewCustomFieldHandler(service *customfield.Service) *CustomFieldHandler {
+	return &CustomFieldHandler{service: service}
+}
+
+func (h *CustomFieldHandler) List(c *gin.Context) {
+	var query dto.CustomFieldListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	fields, err := h.service.List(c.Request.Context(), query.Workspace)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, dto.FromCustomFields(fields))
+}
+
+func (h *CustomFieldHandler) Get(c *gin.Context) {
+	id, ok := customFieldIDParam(c)
+	if !ok {
+		return
+	}
+	f, err := h.service.Get(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, dto.FromCustomField(*f))
+}
+
+func (h *CustomFieldHandler) Create(c *gin.Context) {
+	var req dto.CreateCustomFieldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	f, err := h.service.Create(c.Request.Context(), customfield.CreateInput{
+		Key:         req.Key,
+		Name:        req.Name,
+		Type:        customfield.Type(req.Type),
+		Options:     req.Options,
+		WorkspaceID: req.WorkspaceID,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Created(c, dto.FromCustomField(*f))
+}
+
+func (h *CustomFieldHandler) Update(c *gin.Context) {
+	id, ok := customFieldIDParam(c)
+	if !ok {
+		return
+	}
+	var req dto.UpdateCustomFieldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	input := customfield.UpdateInput{Name: req.Name}
+	if req.Options != nil {
+		input.Options = *req.Options
+		input.OptionsSet = true
+	}
+	f, err := h.service.Update(c.Request.Context(), id, input)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, dto.FromCustomField(*f))
+}
+
+func (h *CustomFieldHandler) Delete(c *gin.Context) {
+	id, ok := customFieldIDParam(c)
+	if !ok {
+		return
+	}
+	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, gin.H{"id": id})
+}
+
+func customFieldIDParam(c *gin.Context) (uint64, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "invalid custom field id")
+		return 0, false
+	}
+	return id, true
+}
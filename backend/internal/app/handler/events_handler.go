@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"todolist/backend/internal/app/dto"
+	"todolist/backend/internal/domain/workspace"
+	"todolist/backend/internal/pkg/events"
+	"todolist/backend/internal/pkg/response"
+)
+
+// EventReset tells a resuming client that events were lost and it has to
+// reload.
+const EventReset = "stream.reset"
+
+// DefaultHeartbeat is how often an idle stream sends a comment line.
+const DefaultHeartbeat = 25 * time.Second
+
+type EventsHandler struct {
+	bus        *events.Bus
+	workspaces *workspace.Service
+	heartbeat  time.Duration
+}
+
+func NewEventsHandler(bus *events.Bus, workspaces *workspace.Service, heartbeat time.Duration) *EventsHandler {
+	if heartbeat <= 0 {
+		heartbeat = DefaultHeartbeat
+	}
+	return &EventsHandler{bus: bus, workspaces: workspaces, heartbeat: heartbeat}
+}
+
+// Stream sends the changes in the caller's workspaces as Server-Sent Events.
+// A reconnecting client resumes after the ID in Last-Event-ID, or in the
+// lastEventId query parameter for the first connection; if those events are
+// gone it gets a stream.reset event instead. The membership the stream
+// filters by is refreshed with every heartbeat.
+func (h *EventsHandler) Stream(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	lastID := c.GetHeader("Last-Event-ID")
+	if lastID == "" {
+		lastID = c.Query("lastEventId")
+	}
+	var after uint64
+	if lastID != "" {
+		id, err := strconv.ParseUint(lastID, 10, 64)
+		if err != nil {
+			response.BadRequest(c, "invalid last event id")
+			return
+		}
+		after = id
+	}
+
+	visible, err := h.visibleWorkspaces(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	sub, missed, complete := h.bus.Subscribe(after)
+	defer sub.Close()
+
+	// Streams outlive the server's write timeout.
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	w := c.Writer
+	if !complete {
+		fmt.Fprintf(w, "event: %s\ndata: {}\n\n", EventReset)
+	}
+	for _, e := range missed {
+		if visible[e.WorkspaceID] {
+			writeEvent(w, e)
+		}
+	}
+	w.Flush()
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			if visible[e.WorkspaceID] {
+				writeEvent(w, e)
+				w.Flush()
+			}
+		case <-ticker.C:
+			if refreshed, err := h.visibleWorkspaces(c); err == nil {
+				visible = refreshed
+			}
+			io.WriteString(w, ": heartbeat\n\n")
+			w.Flush()
+		}
+	}
+}
+
+func (h *EventsHandler) visibleWorkspaces(c *gin.Context) (map[uint64]bool, error) {
+	list, err := h.workspaces.List(c.Request.Context())
+	if err != nil {
+		return nil, err
+	}
+	visible := make(map[uint64]bool, len(list))
+	for _, w := range list {
+		visible[w.ID] = true
+	}
+	return visible, nil
+}
+
+func writeEvent(w io.Writer, e events.Event) {
+	data, err := json.Marshal(dto.FromEvent(e))
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"todolist/backend/internal/app/dto"
+	"todolist/backend/internal/domain/outbox"
+	"todolist/backend/internal/pkg/response"
+)
+
+// OutboxHandler serves the operator endpoints of the event outbox.
+type OutboxHandler struct {
+	service *outbox.Service
+}
+
+func NewOutboxHandler(service *outbox.Service) *OutboxHandler {
+	return &OutboxHandler{service: service}
+}
+
+// Status reports the latest offset and how far each consumer has got.
+func (h *OutboxHandler) Status(c *gin.Context) {
+	head, consumers, err := h.service.Status(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, dto.FromOutboxStatus(head, consumers))
+}
+
+func (h *OutboxHandler) Messages(c *gin.Context) {
+	var query dto.OutboxMessagesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	list, err := h.service.Messages(c.Request.Context(), query.After, query.Limit)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, dto.FromOutboxMessages(list))
+}
+
+// Replay rewinds a consumer so it gets every message from the given offset
+// again.
+func (h *OutboxHandler) Replay(c *gin.Context) {
+	var req dto.ReplayOutboxRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	head, status, err := h.service.Replay(c.Request.Context(), c.Param("name"), req.Offset)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, dto.FromOutboxConsumer(*status, head))
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"todolist/backend/internal/app/dto"
+	"todolist/backend/internal/domain/project"
+	"todolist/backend/internal/pkg/response"
+)
+
+type ProjectHandler struct {
+	service *project.Service
+}
+
+func NewProjectHandler(service *project.Service) *ProjectHandler {
+	return &ProjectHandler{service: service}
+}
+
+func (h *ProjectHandler) List(c *gin.Context) {
+	var query dto.ProjectListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	projects, err := h.service.List(c.Request.Context(), query.Workspace, query.IncludeArchived)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, dto.FromProjects(projects))
+}
+
+func (h *ProjectHandler) Get(c *gin.Context) {
+	p, err := h.service.Get(c.Request.Context(), c.Param("uuid"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, dto.FromProject(*p))
+}
+
+func (h *ProjectHandler) Create(c *gin.Context) {
+	var req dto.CreateProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	p, err := h.service.Create(c.Request.Context(), project.CreateInput{
+		Name:        req.Name,
+		Color:       req.Color,
+		WorkspaceID: req.WorkspaceID,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Created(c, dto.FromProject(*p))
+}
+
+func (h *ProjectHandler) Update(c *gin.Context) {
+	var req dto.UpdateProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	p, err := h.service.Update(c.Request.Context(), c.Param("uuid"), project.UpdateInput{
+		Name:     req.Name,
+		Color:    req.Color.Value,
+		ColorSet: req.Color.Set,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, dto.FromProject(*p))
+}
+
+func (h *ProjectHandler) Archive(c *gin.Context) {
+	h.setArchived(c, true)
+}
+
+func (h *ProjectHandler) Unarchive(c *gin.Context) {
+	h.setArchived(c, false)
+}
+
+func (h *ProjectHandler) setArchived(c *gin.Context, archived bool) {
+	p, err := h.service.SetArchived(c.Request.Context(), c.Param("uuid"), archived)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, dto.FromProject(*p))
+}
+
+func (h *ProjectHandler) Delete(c *gin.Context) {
+	uuid := c.Param("uuid")
+	if err := h.service.Delete(c.Request.Context(), uuid); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, gin.H{"uuid": uuid})
+}
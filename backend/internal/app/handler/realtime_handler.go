@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+
+	"todolist/backend/internal/app/realtime"
+)
+
+var errOriginNotAllowed = errors.New("origin not allowed")
+
+type RealtimeHandler struct {
+	server websocket.Server
+}
+
+// NewRealtimeHandler serves the realtime channel to the origins in
+// allowOrigins, the CORS allow-list. A wildcard there only admits clients
+// sending their token in the Authorization header: a browser would attach
+// the session cookie to an upgrade started by any page.
+func NewRealtimeHandler(hub *realtime.Hub, allowOrigins []string) *RealtimeHandler {
+	return &RealtimeHandler{server: websocket.Server{
+		Handshake: func(cfg *websocket.Config, r *http.Request) error {
+			if err := checkOrigin(r, allowOrigins); err != nil {
+				return err
+			}
+			cfg.Origin, _ = websocket.Origin(cfg, r)
+			return nil
+		},
+		Handler: hub.Serve,
+	}}
+}
+
+// Connect upgrades the request to the realtime WebSocket channel.
+func (h *RealtimeHandler) Connect(c *gin.Context) {
+	h.server.ServeHTTP(c.Writer, c.Request)
+}
+
+// checkOrigin admits upgrades without an Origin, which come from non-browser
+// clients, from the server's own origin and from allowed ones.
+func checkOrigin(r *http.Request, allowOrigins []string) error {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return nil
+	}
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+		return nil
+	}
+	for _, allowed := range allowOrigins {
+		if allowed == "*" && r.Header.Get("Authorization") != "" {
+			return nil
+		}
+		if strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return nil
+		}
+	}
+	return errOriginNotAllowed
+}
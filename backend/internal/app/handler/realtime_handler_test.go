@@ -0,0 +1,297 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"golang.org/x/net/websocket"
+
+	"todolist/backend/internal/app/realtime"
+	"todolist/backend/internal/domain/project"
+	"todolist/backend/internal/domain/task"
+	"todolist/backend/internal/domain/workspace"
+	"todolist/backend/internal/pkg/auth"
+	"todolist/backend/internal/pkg/events"
+)
+
+const testTaskUUID = "0b8f6c1e-3d0a-4c55-9a51-7f1f4a1e2c3d"
+
+var testUsers = map[string]auth.Principal{
+	"alice": {UserID: 1, Username: "alice"},
+	"bob":   {UserID: 2, Username: "bob"},
+}
+
+// fakeTasks stands in for task.Service: one task in workspace 1, and renames
+// announced on the bus the way committed changes are.
+type fakeTasks struct {
+	bus *events.Bus
+
+	mu      sync.Mutex
+	renamed []string
+}
+
+func (f *fakeTasks) Workflow() *task.Workflow {
+	return task.DefaultWorkflow()
+}
+
+func (f *fakeTasks) Get(ctx context.Context, uuid string, depth int) (*task.Task, error) {
+	if uuid != testTaskUUID {
+		return nil, task.ErrTaskNotFound
+	}
+	return &task.Task{UUID: uuid, WorkspaceID: 1, Title: "Draft", Status: task.StatusNow}, nil
+}
+
+func (f *fakeTasks) Update(ctx context.Context, uuid string, payload task.UpdatePayload) (*task.Task, string, error) {
+	t, err := f.Get(ctx, uuid, 0)
+	if err != nil {
+		return nil, "", err
+	}
+	t.Title = *payload.Title
+	f.mu.Lock()
+	f.renamed = append(f.renamed, t.Title)
+	f.mu.Unlock()
+	p, _ := auth.FromContext(ctx)
+	f.bus.Publish(events.Event{
+		Type:        task.EventUpdated,
+		WorkspaceID: t.WorkspaceID,
+		ActorID:     p.UserID,
+		At:          time.Now(),
+		Data:        task.ChangeData{TaskUUIDs: []string{uuid}, Tasks: []task.Snapshot{t.ToSnapshot()}},
+	})
+	return t, "undo-1", nil
+}
+
+func (f *fakeTasks) UpdateStatus(ctx context.Context, uuid string, input task.UpdateStatusInput) (*task.Task, string, error) {
+	return nil, "", errors.New("not supported")
+}
+
+func (f *fakeTasks) UpdateOrder(ctx context.Context, status task.Status, ordered []string, projectUUID *string) (string, error) {
+	return "", errors.New("not supported")
+}
+
+type fakeProjects struct{}
+
+func (fakeProjects) Get(ctx context.Context, uuid string) (*project.Project, error) {
+	return nil, project.ErrProjectNotFound
+}
+
+// fakeWorkspaces puts every test user into workspace 1.
+type fakeWorkspaces struct{}
+
+func (fakeWorkspaces) List(ctx context.Context) ([]workspace.Workspace, error) {
+	return []workspace.Workspace{{ID: 1}}, nil
+}
+
+// newRealtimeServer serves the realtime channel behind a stand-in for Auth
+// that signs in the user named by the "user" query parameter.
+func newRealtimeServer(t *testing.T, allowOrigins []string) (*httptest.Server, *fakeTasks) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	bus := events.NewBus(0)
+	tasks := &fakeTasks{bus: bus}
+	hub := realtime.NewHub(bus, tasks, fakeProjects{}, fakeWorkspaces{}, realtime.Options{}, zap.NewNop())
+	go hub.Run()
+
+	engine := gin.New()
+	engine.GET("/ws", func(c *gin.Context) {
+		if p, ok := testUsers[c.Query("user")]; ok {
+			c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), p))
+		}
+	}, NewRealtimeHandler(hub, allowOrigins).Connect)
+	srv := httptest.NewServer(engine)
+	t.Cleanup(func() {
+		bus.Close()
+		srv.Close()
+	})
+	return srv, tasks
+}
+
+func dialRealtime(srv *httptest.Server, user, origin, authorization string) (*websocket.Conn, error) {
+	cfg, err := websocket.NewConfig("ws"+strings.TrimPrefix(srv.URL, "http")+"/ws?user="+user, srv.URL)
+	if err != nil {
+		return nil, err
+	}
+	// The client always sends an Origin; an empty one reads as absent.
+	cfg.Origin = &url.URL{}
+	if origin != "" {
+		if cfg.Origin, err = url.Parse(origin); err != nil {
+			return nil, err
+		}
+	}
+	if authorization != "" {
+		cfg.Header.Set("Authorization", authorization)
+	}
+	return websocket.DialConfig(cfg)
+}
+
+// serverMessage holds any message the server sends.
+type serverMessage struct {
+	Type      string            `json:"type"`
+	Ref       string            `json:"ref"`
+	Channel   string            `json:"channel"`
+	TaskUUID  string            `json:"taskUuid"`
+	Viewers   []realtime.Viewer `json:"viewers"`
+	UndoToken string            `json:"undoToken"`
+	Message   string            `json:"message"`
+	Event     *struct {
+		Type    string `json:"type"`
+		ActorID uint64 `json:"actorId"`
+		Data    struct {
+			Tasks []struct {
+				Title string `json:"title"`
+			} `json:"tasks"`
+		} `json:"data"`
+	} `json:"event"`
+}
+
+type client struct {
+	t  *testing.T
+	ws *websocket.Conn
+}
+
+func connect(t *testing.T, srv *httptest.Server, user string) *client {
+	t.Helper()
+	ws, err := dialRealtime(srv, user, "", "")
+	if err != nil {
+		t.Fatalf("dial as %s: %v", user, err)
+	}
+	t.Cleanup(func() { ws.Close() })
+	return &client{t: t, ws: ws}
+}
+
+func (c *client) send(msg realtime.ClientMessage) {
+	c.t.Helper()
+	if err := websocket.JSON.Send(c.ws, msg); err != nil {
+		c.t.Fatalf("send %s: %v", msg.Type, err)
+	}
+}
+
+func (c *client) receive() serverMessage {
+	c.t.Helper()
+	_ = c.ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var raw []byte
+	if err := websocket.Message.Receive(c.ws, &raw); err != nil {
+		c.t.Fatalf("receive: %v", err)
+	}
+	var msg serverMessage
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		c.t.Fatalf("decode %s: %v", raw, err)
+	}
+	return msg
+}
+
+// expect reads the next message and checks its type.
+func (c *client) expect(typ string) serverMessage {
+	c.t.Helper()
+	msg := c.receive()
+	if msg.Type != typ {
+		c.t.Fatalf("got %s message %+v, want %s", msg.Type, msg, typ)
+	}
+	return msg
+}
+
+func TestRealtimeSubscribePresenceAndIntent(t *testing.T) {
+	srv, tasks := newRealtimeServer(t, nil)
+	channel := "status:" + string(task.StatusNow)
+
+	alice := connect(t, srv, "alice")
+	alice.send(realtime.ClientMessage{Type: realtime.TypeSubscribe, Ref: "a1", Channel: channel})
+	if msg := alice.expect(realtime.TypeSubscribed); msg.Ref != "a1" || msg.Channel != channel {
+		t.Fatalf("subscribe answered with %+v", msg)
+	}
+	alice.send(realtime.ClientMessage{Type: realtime.TypeSubscribe, Ref: "a2", Channel: "status:nonsense"})
+	if msg := alice.expect(realtime.TypeError); msg.Ref != "a2" {
+		t.Fatalf("invalid channel answered with %+v", msg)
+	}
+
+	bob := connect(t, srv, "bob")
+	bob.send(realtime.ClientMessage{Type: realtime.TypeSubscribe, Ref: "b1", Channel: channel})
+	bob.expect(realtime.TypeSubscribed)
+
+	// Presence reaches everyone following the channel, the sender included.
+	alice.send(realtime.ClientMessage{Type: realtime.TypePresence, Channel: channel, TaskUUID: testTaskUUID, State: realtime.StateEditing})
+	for _, c := range []*client{bob, alice} {
+		msg := c.expect(realtime.TypePresence)
+		want := []realtime.Viewer{{UserID: 1, Username: "alice", State: realtime.StateEditing}}
+		if msg.TaskUUID != testTaskUUID || len(msg.Viewers) != 1 || msg.Viewers[0] != want[0] {
+			t.Fatalf("presence = %+v, want %+v", msg, want)
+		}
+	}
+
+	// An edit intent is acknowledged with its undo token and comes back to
+	// every subscriber as an event.
+	title := "Renamed"
+	bob.send(realtime.ClientMessage{Type: realtime.TypeIntent, Ref: "b2", Intent: realtime.IntentRename, TaskUUID: testTaskUUID, Title: &title})
+	var acked, notified bool
+	for !acked || !notified {
+		switch msg := bob.receive(); msg.Type {
+		case realtime.TypeAck:
+			if msg.Ref != "b2" || msg.UndoToken != "undo-1" {
+				t.Fatalf("intent answered with %+v", msg)
+			}
+			acked = true
+		case realtime.TypeEvent:
+			notified = true
+		default:
+			t.Fatalf("unexpected %+v", msg)
+		}
+	}
+	event := alice.expect(realtime.TypeEvent).Event
+	if event == nil || event.Type != task.EventUpdated || event.ActorID != 2 ||
+		len(event.Data.Tasks) != 1 || event.Data.Tasks[0].Title != title {
+		t.Fatalf("event = %+v", event)
+	}
+	tasks.mu.Lock()
+	defer tasks.mu.Unlock()
+	if len(tasks.renamed) != 1 || tasks.renamed[0] != title {
+		t.Fatalf("renamed = %v", tasks.renamed)
+	}
+
+	// Leaving withdraws the presence.
+	alice.ws.Close()
+	if msg := bob.expect(realtime.TypePresence); len(msg.Viewers) != 0 {
+		t.Fatalf("presence after leaving = %+v", msg.Viewers)
+	}
+}
+
+func TestRealtimeChecksOrigin(t *testing.T) {
+	cases := []struct {
+		name          string
+		allowOrigins  []string
+		origin        string
+		authorization string
+		accepted      bool
+	}{
+		{name: "no origin", accepted: true},
+		{name: "allowed origin", allowOrigins: []string{"https://app.example.com"}, origin: "https://app.example.com", accepted: true},
+		{name: "foreign origin", allowOrigins: []string{"https://app.example.com"}, origin: "https://evil.example"},
+		{name: "same origin", origin: "same", accepted: true},
+		{name: "wildcard with cookie", allowOrigins: []string{"*"}, origin: "https://evil.example"},
+		{name: "wildcard with bearer token", allowOrigins: []string{"*"}, origin: "https://evil.example", authorization: "Bearer t", accepted: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, _ := newRealtimeServer(t, tc.allowOrigins)
+			origin := tc.origin
+			if origin == "same" {
+				origin = srv.URL
+			}
+			ws, err := dialRealtime(srv, "alice", origin, tc.authorization)
+			if ws != nil {
+				ws.Close()
+			}
+			if accepted := err == nil; accepted != tc.accepted {
+				t.Fatalf("accepted = %v (err %v), want %v", accepted, err, tc.accepted)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"todolist/backend/internal/app/dto"
+	"todolist/backend/internal/pkg/response"
+)
+
+// EstimateReport compares estimates with lead time and tracked time for tasks
+// completed between from and to (inclusive, defaulting to today).
+func (h *TaskHandler) EstimateReport(c *gin.Context) {
+	var query dto.EstimateReportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	from, err := time.ParseInLocation("2006-01-02", query.From, time.Local)
+	if err != nil {
+		response.BadRequest(c, "invalid time format")
+		return
+	}
+	to := time.Now()
+	if query.To != "" {
+		to, err = time.ParseInLocation("2006-01-02", query.To, time.Local)
+		if err != nil {
+			response.BadRequest(c, "invalid time format")
+			return
+		}
+	}
+	end := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, 1)
+
+	var projectUUID *string
+	if query.Project != "" {
+		projectUUID = &query.Project
+	}
+
+	report, err := h.service.EstimateReport(c.Request.Context(), from, end, projectUUID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto.FromEstimateReport(*report))
+}
+
+// Capacity reports the week containing ?week (default today) against the
+// configured capacity.
+func (h *TaskHandler) Capacity(c *gin.Context) {
+	var query dto.CapacityQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	day := time.Now()
+	if query.Week != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", query.Week, time.Local)
+		if err != nil {
+			response.BadRequest(c, "invalid time format")
+			return
+		}
+		day = parsed
+	}
+
+	var projectUUID *string
+	if query.Project != "" {
+		projectUUID = &query.Project
+	}
+
+	report, err := h.service.Capacity(c.Request.Context(), day, projectUUID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto.FromCapacity(*report))
+}
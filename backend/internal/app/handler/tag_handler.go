@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"todolist/backend/internal/app/dto"
+	"todolist/backend/internal/domain/tag"
+	"todolist/backend/internal/pkg/response"
+)
+
+type TagHandler struct {
+	service *tag.Service
+}
+
+func NewTagHandler(service *tag.Service) *TagHandler {
+	return &TagHandler{service: service}
+}
+
+func (h *TagHandler) List(c *gin.Context) {
+	var query dto.TagListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	tags, err := h.service.List(c.Request.Context(), query.Workspace)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, dto.FromTags(tags))
+}
+
+func (h *TagHandler) Get(c *gin.Context) {
+	id, ok := tagIDParam(c)
+	if !ok {
+		return
+	}
+	t, err := h.service.Get(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, dto.FromTag(*t))
+}
+
+func (h *TagHandler) Create(c *gin.Context) {
+	var req dto.CreateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	t, err := h.service.Create(c.Request.Context(), req.Name, req.WorkspaceID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Created(c, dto.FromTag(*t))
+}
+
+func (h *TagHandler) Rename(c *gin.Context) {
+	id, ok := tagIDParam(c)
+	if !ok {
+		return
+	}
+	var req dto.RenameTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	t, err := h.service.Rename(c.Request.Context(), id, req.Name)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, dto.FromTag(*t))
+}
+
+func (h *TagHandler) Delete(c *gin.Context) {
+	id, ok := tagIDParam(c)
+	if !ok {
+		return
+	}
+	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, gin.H{"id": id})
+}
+
+func (h *TagHandler) Merge(c *gin.Context) {
+	id, ok := tagIDParam(c)
+	if !ok {
+		return
+	}
+	var req dto.MergeTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	target, err := h.service.Merge(c.Request.Context(), id, req.TargetID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, dto.FromTag(*target))
+}
+
+func tagIDParam(c *gin.Context) (uint64, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "invalid tag id")
+		return 0, false
+	}
+	return id, true
+}
@@ -28,14 +28,39 @@ func (h *TaskHandler) List(c *gin.Context) {
 	}
 
 	filter := task.ListFilter{
-		Keyword:  query.Keyword,
-		Page:     query.Page,
-		PageSize: query.PageSize,
+		WorkspaceID: query.Workspace,
+		Keyword:     query.Keyword,
+		Tags:        query.Tags,
+		Page:        query.Page,
+		PageSize:    query.PageSize,
+		Sort:        task.SortKey(query.Sort),
+		Blocked:     query.Blocked,
+		Depth:       query.Depth,
+		OrderDesc:   query.Order == "desc",
+		ProgressMin: query.ProgressMin,
+		ProgressMax: query.ProgressMax,
+	}
+	// Custom fields are filtered as cf[key]=value.
+	if fields := c.QueryMap("cf"); len(fields) > 0 {
+		filter.CustomFields = fields
+	}
+	if query.Priority != "" {
+		priority := task.Priority(query.Priority)
+		filter.Priority = &priority
 	}
 	if query.Status != "" {
 		status := task.Status(query.Status)
 		filter.Status = &status
 	}
+	if query.Project == "none" {
+		filter.NoProject = true
+	} else if query.Project != "" {
+		projectUUID := query.Project
+		filter.ProjectUUID = &projectUUID
+	}
+	if !applyAssigneeFilter(c, query.Assignee, &filter) {
+		return
+	}
 
 	result, err := h.service.List(c.Request.Context(), filter)
 	if err != nil {
@@ -46,9 +71,39 @@ func (h *TaskHandler) List(c *gin.Context) {
 	response.Success(c, dto.TaskListResponse{Items: dto.FromTasks(result.Tasks), Total: result.Total})
 }
 
+func (h *TaskHandler) DailyPlan(c *gin.Context) {
+	var query dto.DailyPlanQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	input := task.DailyPlanInput{
+		Limit: query.Limit,
+		Sort:  task.SortKey(query.Sort),
+	}
+	if query.Project != "" {
+		input.ProjectUUID = &query.Project
+	}
+
+	tasks, err := h.service.DailyPlan(c.Request.Context(), input)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto.TaskListResponse{Items: dto.FromTasks(tasks), Total: int64(len(tasks))})
+}
+
 func (h *TaskHandler) Get(c *gin.Context) {
 	uuid := c.Param("uuid")
-	taskModel, err := h.service.Get(c.Request.Context(), uuid)
+	var query dto.TreeQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	taskModel, err := h.service.Get(c.Request.Context(), uuid, query.Depth)
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -64,10 +119,18 @@ func (h *TaskHandler) Create(c *gin.Context) {
 		return
 	}
 
-	status := task.StatusFuture
+	var status task.Status
 	if req.Status != nil {
 		status = task.Status(*req.Status)
 	}
+	priority := task.PriorityNone
+	if req.Priority != nil {
+		priority = task.Priority(*req.Priority)
+	}
+	estimateUnit := task.EstimateMinutes
+	if req.EstimateUnit != nil {
+		estimateUnit = task.EstimateUnit(*req.EstimateUnit)
+	}
 
 	var deadline *time.Time
 	if req.Deadline != nil && *req.Deadline != "" {
@@ -80,12 +143,20 @@ func (h *TaskHandler) Create(c *gin.Context) {
 	}
 
 	taskModel, undoToken, err := h.service.Create(c.Request.Context(), task.CreateTaskInput{
-		Title:      req.Title,
-		Notes:      req.Notes,
-		Deadline:   deadline,
-		Status:     status,
-		SortWeight: req.SortWeight,
-		ParentUUID: req.ParentUUID,
+		Title:        req.Title,
+		Notes:        req.Notes,
+		Deadline:     deadline,
+		Status:       status,
+		Priority:     priority,
+		Estimate:     req.Estimate,
+		EstimateUnit: estimateUnit,
+		SortWeight:   req.SortWeight,
+		ParentUUID:   req.ParentUUID,
+		ProjectUUID:  req.ProjectUUID,
+		WorkspaceID:  req.WorkspaceID,
+		AssigneeID:   req.AssigneeID,
+		TagIDs:       req.TagIDs,
+		CustomFields: req.CustomFields,
 	})
 	if err != nil {
 		response.Error(c, err)
@@ -105,20 +176,33 @@ func (h *TaskHandler) Update(c *gin.Context) {
 
 	deadline := req.Deadline.Value
 	payload := task.UpdatePayload{
-		Title:       req.Title,
-		Notes:       req.Notes.Value,
-		NotesSet:    req.Notes.Set,
-		Deadline:    deadline,
-		DeadlineSet: req.Deadline.Set,
+		Title:        req.Title,
+		Notes:        req.Notes.Value,
+		NotesSet:     req.Notes.Set,
+		Deadline:     deadline,
+		DeadlineSet:  req.Deadline.Set,
+		CustomFields: req.CustomFields,
+	}
+	if req.Priority != nil {
+		priority := task.Priority(*req.Priority)
+		payload.Priority = &priority
+	}
+	if req.TagIDs != nil {
+		payload.TagIDs = *req.TagIDs
+		payload.TagsSet = true
+	}
+	if req.Estimate.Set {
+		payload.Estimate = req.Estimate.Value
+		payload.EstimateSet = true
+	}
+	if req.EstimateUnit != nil {
+		unit := task.EstimateUnit(*req.EstimateUnit)
+		payload.EstimateUnit = &unit
 	}
 
 	updated, undoToken, err := h.service.Update(c.Request.Context(), uuid, payload)
 	if err != nil {
-		if err == task.ErrTaskNotFound {
-			response.NotFound(c, "task not found")
-			return
-		}
-		response.InternalServerError(c, err.Error())
+		response.Error(c, err)
 		return
 	}
 
@@ -134,10 +218,6 @@ func (h *TaskHandler) UpdateStatus(c *gin.Context) {
 	}
 
 	status := task.Status(req.Status)
-	if !task.IsValidStatus(status) {
-		response.BadRequest(c, "invalid status")
-		return
-	}
 	var completedAt *time.Time
 	if req.CompletedAt != nil && *req.CompletedAt != "" {
 		parsed, err := time.Parse(time.RFC3339, *req.CompletedAt)
@@ -154,11 +234,7 @@ func (h *TaskHandler) UpdateStatus(c *gin.Context) {
 		CompletedTime: completedAt,
 	})
 	if err != nil {
-		if err == task.ErrTaskNotFound {
-			response.NotFound(c, "task not found")
-			return
-		}
-		response.InternalServerError(c, err.Error())
+		response.Error(c, err)
 		return
 	}
 
@@ -189,11 +265,7 @@ func (h *TaskHandler) Complete(c *gin.Context) {
 
 	updated, undoToken, err := h.service.Complete(c.Request.Context(), uuid, completedAt)
 	if err != nil {
-		if err == task.ErrTaskNotFound {
-			response.NotFound(c, "task not found")
-			return
-		}
-		response.InternalServerError(c, err.Error())
+		response.Error(c, err)
 		return
 	}
 
@@ -204,16 +276,73 @@ func (h *TaskHandler) Delete(c *gin.Context) {
 	uuid := c.Param("uuid")
 	undoToken, err := h.service.Delete(c.Request.Context(), uuid)
 	if err != nil {
-		if err == task.ErrTaskNotFound {
-			response.NotFound(c, "task not found")
-			return
-		}
-		response.InternalServerError(c, err.Error())
+		response.Error(c, err)
 		return
 	}
 	response.Success(c, gin.H{"uuid": uuid}, undoToken)
 }
 
+func (h *TaskHandler) AddDependency(c *gin.Context) {
+	uuid := c.Param("uuid")
+	var req dto.AddDependencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	updated, err := h.service.AddDependency(c.Request.Context(), uuid, req.BlockerUUID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto.FromTask(*updated))
+}
+
+func (h *TaskHandler) RemoveDependency(c *gin.Context) {
+	updated, err := h.service.RemoveDependency(c.Request.Context(), c.Param("uuid"), c.Param("blockerUuid"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto.FromTask(*updated))
+}
+
+func (h *TaskHandler) Reparent(c *gin.Context) {
+	uuid := c.Param("uuid")
+	var req dto.ReparentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	moved, undoToken, err := h.service.Reparent(c.Request.Context(), uuid, req.ParentUUID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto.FromTask(*moved), undoToken)
+}
+
+func (h *TaskHandler) MoveToProject(c *gin.Context) {
+	uuid := c.Param("uuid")
+	var req dto.MoveProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	moved, undoToken, err := h.service.MoveToProject(c.Request.Context(), uuid, req.ProjectUUID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto.FromTask(*moved), undoToken)
+}
+
 func (h *TaskHandler) BulkMove(c *gin.Context) {
 	var req dto.BulkMoveRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -221,18 +350,10 @@ func (h *TaskHandler) BulkMove(c *gin.Context) {
 		return
 	}
 	status := task.Status(req.Status)
-	if !task.IsValidStatus(status) {
-		response.BadRequest(c, "invalid status")
-		return
-	}
 
-	tasks, undoToken, err := h.service.BulkMove(c.Request.Context(), req.IDs, status)
+	tasks, undoToken, err := h.service.BulkMove(c.Request.Context(), req.IDs, status, req.ProjectUUID)
 	if err != nil {
-		if err == task.ErrTaskNotFound {
-			response.NotFound(c, "task not found")
-			return
-		}
-		response.InternalServerError(c, err.Error())
+		response.Error(c, err)
 		return
 	}
 
@@ -246,13 +367,9 @@ func (h *TaskHandler) BulkComplete(c *gin.Context) {
 		return
 	}
 
-	tasks, undoToken, err := h.service.BulkMove(c.Request.Context(), req.IDs, task.StatusHistory)
+	tasks, undoToken, err := h.service.BulkMove(c.Request.Context(), req.IDs, h.service.Workflow().CompletionStatus(), req.ProjectUUID)
 	if err != nil {
-		if err == task.ErrTaskNotFound {
-			response.NotFound(c, "task not found")
-			return
-		}
-		response.InternalServerError(c, err.Error())
+		response.Error(c, err)
 		return
 	}
 
@@ -265,18 +382,46 @@ func (h *TaskHandler) BulkDelete(c *gin.Context) {
 		response.BadRequest(c, err.Error())
 		return
 	}
-	undoToken, err := h.service.BulkDelete(c.Request.Context(), req.IDs)
+	undoToken, err := h.service.BulkDelete(c.Request.Context(), req.IDs, req.ProjectUUID)
 	if err != nil {
-		if err == task.ErrTaskNotFound {
-			response.NotFound(c, "task not found")
-			return
-		}
-		response.InternalServerError(c, err.Error())
+		response.Error(c, err)
 		return
 	}
 	response.Success(c, gin.H{"deleted": req.IDs}, undoToken)
 }
 
+func (h *TaskHandler) BulkTag(c *gin.Context) {
+	var req dto.BulkTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	tasks, undoToken, err := h.service.BulkTag(c.Request.Context(), req.IDs, req.TagIDs, req.ProjectUUID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto.TaskListResponse{Items: dto.FromTasks(tasks), Total: int64(len(tasks))}, undoToken)
+}
+
+func (h *TaskHandler) BulkUntag(c *gin.Context) {
+	var req dto.BulkTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	tasks, undoToken, err := h.service.BulkUntag(c.Request.Context(), req.IDs, req.TagIDs, req.ProjectUUID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto.TaskListResponse{Items: dto.FromTasks(tasks), Total: int64(len(tasks))}, undoToken)
+}
+
 func (h *TaskHandler) UpdateOrder(c *gin.Context) {
 	var req dto.OrderUpdateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -284,19 +429,44 @@ func (h *TaskHandler) UpdateOrder(c *gin.Context) {
 		return
 	}
 	status := task.Status(req.Status)
-	if !task.IsValidStatus(status) {
-		response.BadRequest(c, "invalid status")
+
+	undoToken, err := h.service.UpdateOrder(c.Request.Context(), status, req.OrderedIDs, req.ProjectUUID)
+	if err != nil {
+		response.Error(c, err)
 		return
 	}
+	response.Success(c, gin.H{"status": status, "orderedIds": req.OrderedIDs}, undoToken)
+}
 
-	undoToken, err := h.service.UpdateOrder(c.Request.Context(), status, req.OrderedIDs)
-	if err != nil {
-		if err == task.ErrTaskNotFound {
-			response.NotFound(c, "task not found")
+func (h *TaskHandler) Duplicate(c *gin.Context) {
+	var req dto.DuplicateTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if !errors.Is(err, io.EOF) {
+			response.BadRequest(c, err.Error())
 			return
 		}
-		response.InternalServerError(c, err.Error())
+	}
+
+	input := task.DuplicateInput{
+		IncludeChildren: req.IncludeChildren,
+		ResetDeadline:   req.ResetDeadline,
+		Title:           req.Title,
+	}
+	if req.Status != nil {
+		status := task.Status(*req.Status)
+		input.Status = &status
+	}
+
+	clone, undoToken, err := h.service.Duplicate(c.Request.Context(), c.Param("uuid"), input)
+	if err != nil {
+		response.Error(c, err)
 		return
 	}
-	response.Success(c, gin.H{"status": status, "orderedIds": req.OrderedIDs}, undoToken)
+
+	response.Created(c, dto.FromTask(*clone), undoToken)
+}
+
+// Workflow describes the statuses tasks can take and how they connect.
+func (h *TaskHandler) Workflow(c *gin.Context) {
+	response.Success(c, dto.FromWorkflow(h.service.Workflow()))
 }
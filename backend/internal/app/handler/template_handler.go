@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"todolist/backend/internal/app/dto"
+	"todolist/backend/internal/domain/task"
+	"todolist/backend/internal/domain/template"
+	"todolist/backend/internal/pkg/response"
+)
+
+type TemplateHandler struct {
+	service *template.Service
+}
+
+func NewTemplateHandler(service *template.Service) *TemplateHandler {
+	return &TemplateHandler{service: service}
+}
+
+func (h *TemplateHandler) List(c *gin.Context) {
+	var query dto.TemplateListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	templates, err := h.service.List(c.Request.Context(), query.Workspace)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	resp, err := dto.FromTemplates(templates)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, resp)
+}
+
+func (h *TemplateHandler) Get(c *gin.Context) {
+	id, ok := templateIDParam(c)
+	if !ok {
+		return
+	}
+	t, err := h.service.Get(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	h.respond(c, t, false)
+}
+
+func (h *TemplateHandler) Create(c *gin.Context) {
+	var req dto.CreateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	t, err := h.service.Create(c.Request.Context(), template.CreateInput{
+		Name:        req.Name,
+		Description: req.Description,
+		Root:        dto.ToTemplateNode(req.Root),
+		WorkspaceID: req.WorkspaceID,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	h.respond(c, t, true)
+}
+
+func (h *TemplateHandler) Update(c *gin.Context) {
+	id, ok := templateIDParam(c)
+	if !ok {
+		return
+	}
+	var req dto.UpdateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	input := template.UpdateInput{
+		Name:           req.Name,
+		Description:    req.Description.Value,
+		DescriptionSet: req.Description.Set,
+	}
+	if req.Root != nil {
+		root := dto.ToTemplateNode(*req.Root)
+		input.Root = &root
+	}
+	t, err := h.service.Update(c.Request.Context(), id, input)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	h.respond(c, t, false)
+}
+
+func (h *TemplateHandler) Delete(c *gin.Context) {
+	id, ok := templateIDParam(c)
+	if !ok {
+		return
+	}
+	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+func (h *TemplateHandler) Instantiate(c *gin.Context) {
+	id, ok := templateIDParam(c)
+	if !ok {
+		return
+	}
+	var req dto.InstantiateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	input := template.InstantiateInput{
+		Variables:   req.Variables,
+		ProjectUUID: req.ProjectUUID,
+		ParentUUID:  req.ParentUUID,
+		WorkspaceID: req.WorkspaceID,
+	}
+	if req.Status != nil {
+		input.Status = task.Status(*req.Status)
+	}
+	if req.AnchorDate != nil && *req.AnchorDate != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", *req.AnchorDate, time.Local)
+		if err != nil {
+			response.BadRequest(c, "invalid deadline format")
+			return
+		}
+		input.AnchorDate = parsed
+	}
+
+	created, undoToken, err := h.service.Instantiate(c.Request.Context(), id, input)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Created(c, dto.FromTask(*created), undoToken)
+}
+
+// SaveFromTask stores the task at :uuid and its subtasks as a new template.
+func (h *TemplateHandler) SaveFromTask(c *gin.Context) {
+	var req dto.SaveTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	t, err := h.service.SaveFromTask(c.Request.Context(), c.Param("uuid"), req.Name, req.Description)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	h.respond(c, t, true)
+}
+
+func (h *TemplateHandler) respond(c *gin.Context, t *template.Template, created bool) {
+	resp, err := dto.FromTemplate(*t)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if created {
+		response.Created(c, resp)
+		return
+	}
+	response.Success(c, resp)
+}
+
+func templateIDParam(c *gin.Context) (uint64, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "invalid template id")
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"todolist/backend/internal/app/dto"
+	"todolist/backend/internal/domain/task"
+	"todolist/backend/internal/pkg/response"
+)
+
+func (h *TaskHandler) StartTimer(c *gin.Context) {
+	var req dto.StartTimerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if !errors.Is(err, io.EOF) {
+			response.BadRequest(c, err.Error())
+			return
+		}
+	}
+
+	entry, err := h.service.StartTimer(c.Request.Context(), c.Param("uuid"), req.Note)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, dto.FromTimeEntry(*entry))
+}
+
+func (h *TaskHandler) StopTimer(c *gin.Context) {
+	entry, err := h.service.StopTimer(c.Request.Context(), c.Param("uuid"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto.FromTimeEntry(*entry))
+}
+
+func (h *TaskHandler) RunningTimer(c *gin.Context) {
+	entry, err := h.service.RunningTimer(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if entry == nil {
+		response.Success(c, nil)
+		return
+	}
+
+	response.Success(c, dto.FromTimeEntry(*entry))
+}
+
+func (h *TaskHandler) ListTimeEntries(c *gin.Context) {
+	entries, err := h.service.ListTimeEntries(c.Request.Context(), c.Param("uuid"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto.FromTimeEntries(entries))
+}
+
+func (h *TaskHandler) CreateTimeEntry(c *gin.Context) {
+	var req dto.CreateTimeEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	startedAt, err := time.Parse(time.RFC3339, req.StartedAt)
+	if err != nil {
+		response.BadRequest(c, "invalid time format")
+		return
+	}
+	endedAt, err := time.Parse(time.RFC3339, req.EndedAt)
+	if err != nil {
+		response.BadRequest(c, "invalid time format")
+		return
+	}
+
+	entry, err := h.service.CreateTimeEntry(c.Request.Context(), c.Param("uuid"), task.TimeEntryInput{
+		StartedAt: startedAt,
+		EndedAt:   endedAt,
+		Note:      req.Note,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, dto.FromTimeEntry(*entry))
+}
+
+func (h *TaskHandler) UpdateTimeEntry(c *gin.Context) {
+	entryID, ok := timeEntryIDParam(c)
+	if !ok {
+		return
+	}
+	var req dto.UpdateTimeEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	patch := task.TimeEntryPatch{
+		Note:    req.Note.Value,
+		NoteSet: req.Note.Set,
+	}
+	if req.StartedAt != nil {
+		parsed, err := time.Parse(time.RFC3339, *req.StartedAt)
+		if err != nil {
+			response.BadRequest(c, "invalid time format")
+			return
+		}
+		patch.StartedAt = &parsed
+	}
+	if req.EndedAt != nil {
+		parsed, err := time.Parse(time.RFC3339, *req.EndedAt)
+		if err != nil {
+			response.BadRequest(c, "invalid time format")
+			return
+		}
+		patch.EndedAt = &parsed
+	}
+
+	entry, err := h.service.UpdateTimeEntry(c.Request.Context(), c.Param("uuid"), entryID, patch)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto.FromTimeEntry(*entry))
+}
+
+func (h *TaskHandler) DeleteTimeEntry(c *gin.Context) {
+	entryID, ok := timeEntryIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteTimeEntry(c.Request.Context(), c.Param("uuid"), entryID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// Timesheet exports tracked time per day, as JSON or as a CSV download.
+func (h *TaskHandler) Timesheet(c *gin.Context) {
+	var query dto.TimesheetQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	from, err := time.ParseInLocation("2006-01-02", query.From, time.Local)
+	if err != nil {
+		response.BadRequest(c, "invalid time format")
+		return
+	}
+	to := from
+	if query.To != "" {
+		to, err = time.ParseInLocation("2006-01-02", query.To, time.Local)
+		if err != nil {
+			response.BadRequest(c, "invalid time format")
+			return
+		}
+	}
+
+	days, err := h.service.Timesheet(c.Request.Context(), from, to)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if query.Format != "csv" {
+		response.Success(c, dto.FromTimesheet(days))
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", "attachment; filename=timesheet-"+query.From+".csv")
+	c.Status(200)
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write([]string{"date", "task_uuid", "title", "seconds", "hours"})
+	for _, d := range days {
+		for _, row := range d.Tasks {
+			_ = w.Write([]string{
+				d.Date,
+				row.TaskUUID,
+				row.Title,
+				strconv.FormatInt(row.Seconds, 10),
+				strconv.FormatFloat(float64(row.Seconds)/3600, 'f', 2, 64),
+			})
+		}
+	}
+	w.Flush()
+}
+
+func timeEntryIDParam(c *gin.Context) (uint64, bool) {
+	id, err := strconv.ParseUint(c.Param("entryId"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "invalid time entry id")
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"todolist/backend/internal/app/dto"
+	"todolist/backend/internal/domain/user"
+	"todolist/backend/internal/pkg/auth"
+	"todolist/backend/internal/pkg/response"
+)
+
+func (h *AuthHandler) ListTokens(c *gin.Context) {
+	tokens, err := h.service.ListTokens(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, dto.FromTokens(tokens))
+}
+
+func (h *AuthHandler) CreateToken(c *gin.Context) {
+	var req dto.CreateTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	input := user.CreateTokenInput{Name: req.Name}
+	for _, s := range req.Scopes {
+		input.Scopes = append(input.Scopes, auth.Scope(s))
+	}
+	if req.ExpiresAt != nil {
+		expiresAt, err := time.Parse(time.RFC3339, *req.ExpiresAt)
+		if err != nil {
+			response.BadRequest(c, "invalid time format")
+			return
+		}
+		input.ExpiresAt = &expiresAt
+	}
+	token, t, err := h.service.CreateToken(c.Request.Context(), input)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Created(c, dto.CreatedTokenResponse{TokenResponse: dto.FromToken(*t), Token: token})
+}
+
+func (h *AuthHandler) RevokeToken(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "invalid token id")
+		return
+	}
+	if err := h.service.RevokeToken(c.Request.Context(), id); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, gin.H{"id": id})
+}
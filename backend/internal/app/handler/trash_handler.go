@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"todolist/backend/internal/app/dto"
+	"todolist/backend/internal/pkg/response"
+)
+
+func (h *TaskHandler) ListTrash(c *gin.Context) {
+	tasks, err := h.service.ListTrash(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, dto.FromTasks(tasks))
+}
+
+func (h *TaskHandler) Purge(c *gin.Context) {
+	uuid := c.Param("uuid")
+	if err := h.service.Purge(c.Request.Context(), uuid); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, gin.H{"uuid": uuid})
+}
@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"todolist/backend/internal/app/dto"
+	"todolist/backend/internal/domain/webhook"
+	"todolist/backend/internal/pkg/response"
+)
+
+type WebhookHandler struct {
+	service *webhook.Service
+}
+
+func NewWebhookHandler(service *webhook.Service) *WebhookHandler {
+	return &WebhookHandler{service: service}
+}
+
+func (h *WebhookHandler) List(c *gin.Context) {
+	workspaceID, ok := uintParam(c, "id", "invalid workspace id")
+	if !ok {
+		return
+	}
+	list, err := h.service.List(c.Request.Context(), workspaceID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, dto.FromWebhooks(list))
+}
+
+func (h *WebhookHandler) Get(c *gin.Context) {
+	workspaceID, id, ok := webhookParams(c)
+	if !ok {
+		return
+	}
+	sub, err := h.service.Get(c.Request.Context(), workspaceID, id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, dto.FromWebhook(*sub))
+}
+
+// Create adds a webhook. The response carries the signing secret, generated
+// unless one was given; later reads leave it out.
+func (h *WebhookHandler) Create(c *gin.Context) {
+	workspaceID, ok := uintParam(c, "id", "invalid workspace id")
+	if !ok {
+		return
+	}
+	var req dto.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	sub, err := h.service.Create(c.Request.Context(), workspaceID, webhook.CreateInput{
+		URL:    req.URL,
+		Secret: req.Secret,
+		Events: req.Events,
+		Active: req.Active,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Created(c, dto.FromWebhookWithSecret(*sub))
+}
+
+// Update changes a webhook. Sending an empty secret rotates it to a
+// generated one, which the response then carries.
+func (h *WebhookHandler) Update(c *gin.Context) {
+	workspaceID, id, ok := webhookParams(c)
+	if !ok {
+		return
+	}
+	var req dto.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	sub, err := h.service.Update(c.Request.Context(), workspaceID, id, webhook.UpdateInput{
+		URL:    req.URL,
+		Secret: req.Secret,
+		Events: req.Events,
+		Active: req.Active,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if req.Secret != nil {
+		response.Success(c, dto.FromWebhookWithSecret(*sub))
+		return
+	}
+	response.Success(c, dto.FromWebhook(*sub))
+}
+
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	workspaceID, id, ok := webhookParams(c)
+	if !ok {
+		return
+	}
+	if err := h.service.Delete(c.Request.Context(), workspaceID, id); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, gin.H{"id": id})
+}
+
+func (h *WebhookHandler) Deliveries(c *gin.Context) {
+	workspaceID, id, ok := webhookParams(c)
+	if !ok {
+		return
+	}
+	var query dto.DeliveryQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	list, err := h.service.Deliveries(c.Request.Context(), workspaceID, id, webhook.DeliveryStatus(query.Status), query.Limit)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, dto.FromDeliveries(list))
+}
+
+// Delivery returns a delivery with its payload and the log of its attempts.
+func (h *WebhookHandler) Delivery(c *gin.Context) {
+	workspaceID, id, ok := webhookParams(c)
+	if !ok {
+		return
+	}
+	deliveryID, ok := uintParam(c, "deliveryId", "invalid delivery id")
+	if !ok {
+		return
+	}
+	d, attempts, err := h.service.Delivery(c.Request.Context(), workspaceID, id, deliveryID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, dto.FromDeliveryDetail(*d, attempts))
+}
+
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	workspaceID, id, ok := webhookParams(c)
+	if !ok {
+		return
+	}
+	deliveryID, ok := uintParam(c, "deliveryId", "invalid delivery id")
+	if !ok {
+		return
+	}
+	d, err := h.service.Redeliver(c.Request.Context(), workspaceID, id, deliveryID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Created(c, dto.FromDelivery(*d))
+}
+
+func webhookParams(c *gin.Context) (uint64, uint64, bool) {
+	workspaceID, ok := uintParam(c, "id", "invalid workspace id")
+	if !ok {
+		return 0, 0, false
+	}
+	id, ok := uintParam(c, "webhookId", "invalid webhook id")
+	if !ok {
+		return 0, 0, false
+	}
+	return workspaceID, id, true
+}
@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"todolist/backend/internal/app/dto"
+	"todolist/backend/internal/domain/workspace"
+	"todolist/backend/internal/pkg/response"
+)
+
+type WorkspaceHandler struct {
+	service *workspace.Service
+}
+
+func NewWorkspaceHandler(service *workspace.Service) *WorkspaceHandler {
+	return &WorkspaceHandler{service: service}
+}
+
+func (h *WorkspaceHandler) List(c *gin.Context) {
+	list, err := h.service.List(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, dto.FromWorkspaces(list))
+}
+
+func (h *WorkspaceHandler) Get(c *gin.Context) {
+	id, ok := uintParam(c, "id", "invalid workspace id")
+	if !ok {
+		return
+	}
+	w, err := h.service.Get(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, dto.FromWorkspace(*w))
+}
+
+func (h *WorkspaceHandler) Create(c *gin.Context) {
+	var req dto.CreateWorkspaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	w, err := h.service.Create(c.Request.Context(), req.Name)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Created(c, dto.FromWorkspace(*w))
+}
+
+func (h *WorkspaceHandler) Rename(c *gin.Context) {
+	id, ok := uintParam(c, "id", "invalid workspace id")
+	if !ok {
+		return
+	}
+	var req dto.RenameWorkspaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	w, err := h.service.Rename(c.Request.Context(), id, req.Name)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, dto.FromWorkspace(*w))
+}
+
+func (h *WorkspaceHandler) Delete(c *gin.Context) {
+	id, ok := uintParam(c, "id", "invalid workspace id")
+	if !ok {
+		return
+	}
+	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, gin.H{"id": id})
+}
+
+func (h *WorkspaceHandler) Members(c *gin.Context) {
+	id, ok := uintParam(c, "id", "invalid workspace id")
+	if !ok {
+		return
+	}
+	members, err := h.service.Members(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, dto.FromMembers(members))
+}
+
+func (h *WorkspaceHandler) UpdateMember(c *gin.Context) {
+	id, ok := uintParam(c, "id", "invalid workspace id")
+	if !ok {
+		return
+	}
+	userID, ok := uintParam(c, "userId", "invalid user id")
+	if !ok {
+		return
+	}
+	var req dto.UpdateMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	m, err := h.service.SetRole(c.Request.Context(), id, userID, workspace.Role(req.Role))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, dto.FromMember(*m))
+}
+
+// RemoveMember removes a member; members may also remove themselves to
+// leave a workspace.
+func (h *WorkspaceHandler) RemoveMember(c *gin.Context) {
+	id, ok := uintParam(c, "id", "invalid workspace id")
+	if !ok {
+		return
+	}
+	userID, ok := uintParam(c, "userId", "invalid user id")
+	if !ok {
+		return
+	}
+	if err := h.service.RemoveMember(c.Request.Context(), id, userID); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, gin.H{"userId": userID})
+}
+
+func (h *WorkspaceHandler) Invitations(c *gin.Context) {
+	id, ok := uintParam(c, "id", "invalid workspace id")
+	if !ok {
+		return
+	}
+	invitations, err := h.service.Invitations(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, dto.FromInvitations(invitations))
+}
+
+func (h *WorkspaceHandler) Invite(c *gin.Context) {
+	id, ok := uintParam(c, "id", "invalid workspace id")
+	if !ok {
+		return
+	}
+	var req dto.InviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	inv, err := h.service.Invite(c.Request.Context(), id, req.Username, workspace.Role(req.Role))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Created(c, dto.FromInvitation(*inv))
+}
+
+func (h *WorkspaceHandler) RevokeInvitation(c *gin.Context) {
+	id, ok := uintParam(c, "id", "invalid workspace id")
+	if !ok {
+		return
+	}
+	invitationID, ok := uintParam(c, "invitationId", "invalid invitation id")
+	if !ok {
+		return
+	}
+	if err := h.service.RevokeInvitation(c.Request.Context(), id, invitationID); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, gin.H{"id": invitationID})
+}
+
+// PendingInvitations lists the invitations addressed to the caller.
+func (h *WorkspaceHandler) PendingInvitations(c *gin.Context) {
+	invitations, err := h.service.PendingInvitations(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, dto.FromInvitations(invitations))
+}
+
+func (h *WorkspaceHandler) AcceptInvitation(c *gin.Context) {
+	id, ok := uintParam(c, "id", "invalid invitation id")
+	if !ok {
+		return
+	}
+	w, err := h.service.AcceptInvitation(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, dto.FromWorkspace(*w))
+}
+
+func (h *WorkspaceHandler) DeclineInvitation(c *gin.Context) {
+	id, ok := uintParam(c, "id", "invalid invitation id")
+	if !ok {
+		return
+	}
+	if err := h.service.DeclineInvitation(c.Request.Context(), id); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, gin.H{"id": id})
+}
+
+func uintParam(c *gin.Context, name, message string) (uint64, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil {
+		response.BadRequest(c, message)
+		return 0, false
+	}
+	return id, true
+}
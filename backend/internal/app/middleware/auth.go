@@ -0,0 +1,85 @@
+package middleware
+
+import (
+    "context"
+    "net/http"
+    "strings"
+
+    "github.com/gin-gonic/gin"
+
+    "todolist/backend/internal/pkg/auth"
+    "todolist/backend/internal/pkg/response"
+)
+
+// SessionCookie carries the session token for browser clients.
+const SessionCookie = "session"
+
+// Authenticator resolves a credential to the user it belongs to.
+type Authenticator interface {
+    Authenticate(ctx context.Context, token string) (auth.Principal, error)
+}
+
+// Auth rejects requests without a valid credential and stores the caller in
+// the request context for the services below.
+func Auth(a Authenticator) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        token := Credential(c)
+        if token == "" {
+            response.Unauthorized(c, "authentication required")
+            c.Abort()
+            return
+        }
+        p, err := a.Authenticate(c.Request.Context(), token)
+        if err != nil {
+            response.Error(c, err)
+            c.Abort()
+            return
+        }
+        c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), p))
+        c.Next()
+    }
+}
+
+// Credential returns the bearer token of the request, falling back to the
+// session cookie.
+func Credential(c *gin.Context) string {
+    if header := c.GetHeader("Authorization"); header != "" {
+        scheme, token, ok := strings.Cut(header, " ")
+        if !ok || !strings.EqualFold(scheme, "Bearer") {
+            return ""
+        }
+        return strings.TrimSpace(token)
+    }
+    token, err := c.Cookie(SessionCookie)
+    if err != nil {
+        return ""
+    }
+    return token
+}
+
+// RequireScope rejects callers whose credential was not granted scope.
+func RequireScope(scope auth.Scope) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        p, _ := auth.FromContext(c.Request.Context())
+        if !p.Has(scope) {
+            response.Forbidden(c, "insufficient scope")
+            c.Abort()
+            return
+        }
+        c.Next()
+    }
+}
+
+// ScopeByMethod requires the read scope for safe methods and the write scope
+// for everything else.
+func ScopeByMethod() gin.HandlerFunc {
+    read, write := RequireScope(auth.ScopeRead), RequireScope(auth.ScopeWrite)
+    return func(c *gin.Context) {
+        switch c.Request.Method {
+        case http.MethodGet, http.MethodHead, http.MethodOptions:
+            read(c)
+        default:
+            write(c)
+        }
+    }
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+    "net/http"
+
+    "github.com/gin-gonic/gin"
+
+    "todolist/backend/internal/pkg/response"
+)
+
+// BodyLimit caps the request body at max bytes. Routes listed in perRoute,
+// keyed by their full path, get their own cap instead, e.g. for uploads.
+// Requests declaring a larger Content-Length are rejected with 413 up front;
+// bodies of unknown length are cut off while they are read. A non-positive
+// cap turns the check off.
+func BodyLimit(max int64, perRoute map[string]int64) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        limit := max
+        if n, ok := perRoute[c.FullPath()]; ok {
+            limit = n
+        }
+        if limit <= 0 {
+            c.Next()
+            return
+        }
+        if c.Request.ContentLength > limit {
+            response.PayloadTooLarge(c, "request body too large")
+            c.Abort()
+            return
+        }
+        c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+        c.Next()
+    }
+}
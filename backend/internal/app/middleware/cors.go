@@ -14,7 +14,7 @@ func CORS(cfg config.CORSConfig) gin.HandlerFunc {
         AllowOrigins:     cfg.AllowOrigins,
         AllowMethods:     cfg.AllowMethods,
         AllowHeaders:     cfg.AllowHeaders,
-        ExposeHeaders:    []string{"X-Request-ID"},
+        ExposeHeaders:    []string{"X-Request-ID", "Idempotent-Replayed", "Retry-After"},
         AllowCredentials: true,
         MaxAge:           12 * time.Hour,
     })
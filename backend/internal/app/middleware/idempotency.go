@@ -0,0 +1,143 @@
+package middleware
+
+import (
+    "bytes"
+    "context"
+    "crypto/sha256"
+    "encoding/hex"
+    "errors"
+    "io"
+    "net/http"
+
+    "github.com/gin-gonic/gin"
+    "go.uber.org/zap"
+
+    "todolist/backend/internal/domain/idempotency"
+    "todolist/backend/internal/pkg/auth"
+    "todolist/backend/internal/pkg/response"
+)
+
+const (
+    IdempotencyKeyHeader = "Idempotency-Key"
+    // IdempotentReplayedHeader marks a response served from the store.
+    IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// IdempotencyStore keeps the responses of requests sent with an
+// Idempotency-Key.
+type IdempotencyStore interface {
+    Begin(ctx context.Context, userID uint64, key, requestHash string) (*idempotency.Record, error)
+    Complete(ctx context.Context, userID uint64, key string, status int, contentType string, body []byte) error
+    Release(ctx context.Context, userID uint64, key string) error
+}
+
+// Idempotency makes mutating requests carrying an Idempotency-Key safe to
+// retry: the first response is stored and replayed for retries of the same
+// request. Keys belong to the caller, so it must run after Auth. The body is
+// read into memory to fingerprint the request, so BodyLimit must cap it first.
+// Routes in skip, keyed by full path, are never stored, e.g. because their
+// responses hold secrets or their bodies are too large to buffer.
+func Idempotency(store IdempotencyStore, skip map[string]bool, log *zap.Logger) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        key := c.GetHeader(IdempotencyKeyHeader)
+        if key == "" || skip[c.FullPath()] || !mutating(c.Request.Method) {
+            c.Next()
+            return
+        }
+        p, ok := auth.FromContext(c.Request.Context())
+        if !ok {
+            c.Next()
+            return
+        }
+
+        body, err := io.ReadAll(c.Request.Body)
+        if err != nil {
+            var tooLarge *http.MaxBytesError
+            if errors.As(err, &tooLarge) {
+                response.PayloadTooLarge(c, "request body too large")
+            } else {
+                response.BadRequest(c, err.Error())
+            }
+            c.Abort()
+            return
+        }
+        c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+        // The outcome is stored even if the client has gone away meanwhile.
+        ctx := context.WithoutCancel(c.Request.Context())
+        stored, err := store.Begin(ctx, p.UserID, key, requestHash(c.Request, body))
+        if err != nil {
+            response.Error(c, err)
+            c.Abort()
+            return
+        }
+        if stored != nil {
+            c.Header(IdempotentReplayedHeader, "true")
+            c.Data(stored.StatusCode, stored.ContentType, stored.Body)
+            c.Abort()
+            return
+        }
+
+        recorder := &recordingWriter{ResponseWriter: c.Writer}
+        c.Writer = recorder
+        completed := false
+        defer func() {
+            if completed {
+                return
+            }
+            if err := store.Release(ctx, p.UserID, key); err != nil {
+                log.Error("release idempotency key failed", zap.Error(err))
+            }
+        }()
+
+        c.Next()
+
+        if !recorder.Written() {
+            return
+        }
+        err = store.Complete(ctx, p.UserID, key, recorder.Status(), recorder.Header().Get("Content-Type"), recorder.body.Bytes())
+        if err != nil {
+            log.Error("store idempotent response failed", zap.Error(err))
+            return
+        }
+        completed = true
+    }
+}
+
+func mutating(method string) bool {
+    switch method {
+    case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+        return true
+    }
+    return false
+}
+
+// requestHash fingerprints a request so a key reused for another one is
+// caught.
+func requestHash(r *http.Request, body []byte) string {
+    h := sha256.New()
+    h.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+    h.Write(body)
+    return hex.EncodeToString(h.Sum(nil))
+}
+
+// recordingWriter keeps a copy of the response body.
+type recordingWriter struct {
+    gin.ResponseWriter
+    body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+    w.body.Write(b)
+    return w.ResponseWriter.Write(b)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+    w.body.WriteString(s)
+    return w.ResponseWriter.WriteString(s)
+}
+
+// Unwrap lets http.ResponseController reach the connection underneath.
+func (w *recordingWriter) Unwrap() http.ResponseWriter {
+    return w.ResponseWriter
+}
@@ -0,0 +1,133 @@
+package middleware
+
+import (
+    "crypto/sha256"
+    "encoding/hex"
+    "math"
+    "strconv"
+    "sync"
+    "time"
+
+    "github.com/gin-gonic/gin"
+
+    "todolist/backend/internal/pkg/auth"
+    "todolist/backend/internal/pkg/response"
+)
+
+// sweepInterval is how often idle buckets are dropped.
+const sweepInterval = time.Minute
+
+// RateLimiter is an in-memory token bucket per client. Each bucket holds up to
+// burst tokens and refills at rate tokens per second.
+type RateLimiter struct {
+    rate  float64
+    burst float64
+
+    mu      sync.Mutex
+    buckets map[string]*bucket
+    swept   time.Time
+}
+
+type bucket struct {
+    tokens  float64
+    updated time.Time
+}
+
+// NewRateLimiter returns a limiter allowing rate requests per second with
+// bursts of up to burst requests. A non-positive rate disables limiting.
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+    if rate <= 0 {
+        return nil
+    }
+    if burst < 1 {
+        burst = 1
+    }
+    return &RateLimiter{
+        rate:    rate,
+        burst:   float64(burst),
+        buckets: make(map[string]*bucket),
+        swept:   time.Now(),
+    }
+}
+
+// Allow takes a token from the bucket of key. When the bucket is empty it
+// reports how long until the next token is available.
+func (l *RateLimiter) Allow(key string) (bool, time.Duration) {
+    now := time.Now()
+
+    l.mu.Lock()
+    defer l.mu.Unlock()
+
+    if now.Sub(l.swept) >= sweepInterval {
+        l.sweep(now)
+    }
+
+    b, ok := l.buckets[key]
+    if !ok {
+        b = &bucket{tokens: l.burst, updated: now}
+        l.buckets[key] = b
+    }
+    b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.updated).Seconds()*l.rate)
+    b.updated = now
+
+    if b.tokens < 1 {
+        wait := time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+        return false, wait
+    }
+    b.tokens--
+    return true, 0
+}
+
+// sweep drops buckets that have refilled completely; a fresh bucket would
+// behave the same.
+func (l *RateLimiter) sweep(now time.Time) {
+    full := time.Duration(l.burst / l.rate * float64(time.Second))
+    for key, b := range l.buckets {
+        if now.Sub(b.updated) >= full {
+            delete(l.buckets, key)
+        }
+    }
+    l.swept = now
+}
+
+// RateLimit rejects callers that have used up their bucket with 429 and a
+// Retry-After header. Authenticated callers are told apart by API token, then
+// user, once Auth has validated them; everyone else, including the public
+// group, by client IP. A nil limiter lets everything through.
+func RateLimit(l *RateLimiter) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        if l == nil {
+            c.Next()
+            return
+        }
+        ok, wait := l.Allow(clientKey(c))
+        if !ok {
+            seconds := int(math.Ceil(wait.Seconds()))
+            if seconds < 1 {
+                seconds = 1
+            }
+            c.Header("Retry-After", strconv.Itoa(seconds))
+            response.TooManyRequests(c, "rate limit exceeded")
+            c.Abort()
+            return
+        }
+        c.Next()
+    }
+}
+
+// clientKey identifies the caller for rate limiting. Credentials only count
+// once Auth has accepted them, so made-up tokens cannot mint fresh buckets.
+// Bearer tokens are hashed so the limiter never holds credentials.
+func clientKey(c *gin.Context) string {
+    p, ok := auth.FromContext(c.Request.Context())
+    if !ok {
+        return "ip:" + c.ClientIP()
+    }
+    if c.GetHeader("Authorization") != "" {
+        if token := Credential(c); token != "" {
+            sum := sha256.Sum256([]byte(token))
+            return "token:" + hex.EncodeToString(sum[:16])
+        }
+    }
+    return "user:" + strconv.FormatUint(p.UserID, 10)
+}
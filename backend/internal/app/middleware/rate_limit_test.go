@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newLimitedEngine(t *testing.T, trustedProxies []string) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	if err := engine.SetTrustedProxies(trustedProxies); err != nil {
+		t.Fatal(err)
+	}
+	engine.POST("/login", RateLimit(NewRateLimiter(0.001, 1)), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return engine
+}
+
+func login(engine *gin.Engine, remoteAddr, forwardedFor string) int {
+	req := httptest.NewRequest(http.MethodPost, "/login", nil)
+	req.RemoteAddr = remoteAddr
+	if forwardedFor != "" {
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestRateLimitIgnoresSpoofedForwardedFor(t *testing.T) {
+	engine := newLimitedEngine(t, nil)
+	if code := login(engine, "203.0.113.7:4000", "198.51.100.1"); code != http.StatusOK {
+		t.Fatalf("first request: status %d", code)
+	}
+	if code := login(engine, "203.0.113.7:4001", "198.51.100.2"); code != http.StatusTooManyRequests {
+		t.Fatalf("request with a rotated X-Forwarded-For: status %d, want 429", code)
+	}
+}
+
+func TestRateLimitBelievesTrustedProxies(t *testing.T) {
+	engine := newLimitedEngine(t, []string{"10.0.0.0/8"})
+	if code := login(engine, "10.0.0.2:4000", "198.51.100.1"); code != http.StatusOK {
+		t.Fatalf("first client: status %d", code)
+	}
+	if code := login(engine, "10.0.0.2:4001", "198.51.100.2"); code != http.StatusOK {
+		t.Fatalf("second client behind the proxy: status %d", code)
+	}
+	if code := login(engine, "10.0.0.2:4002", "198.51.100.1"); code != http.StatusTooManyRequests {
+		t.Fatalf("first client again: status %d, want 429", code)
+	}
+}
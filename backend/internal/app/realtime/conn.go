@@ -0,0 +1,262 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"todolist/backend/internal/domain/task"
+	"todolist/backend/internal/pkg/auth"
+)
+
+const (
+	// maxMessageBytes bounds a single client message.
+	maxMessageBytes = 64 << 10
+	// sendBuffer is how many messages may wait for a slow client before it
+	// is disconnected.
+	sendBuffer = 64
+	writeWait  = 10 * time.Second
+)
+
+var (
+	errInvalidChannel  = errors.New("invalid channel")
+	errNotSubscribed   = errors.New("not subscribed to channel")
+	errTooManyChannels = errors.New("too many subscriptions")
+	errInvalidMessage  = errors.New("invalid message")
+	errInvalidPresence = errors.New("invalid presence state")
+	errInvalidIntent   = errors.New("invalid intent")
+	errUnauthenticated = errors.New("authentication required")
+)
+
+// conn is one client. Its subscriptions, visibility and presence are
+// guarded by the hub's mutex.
+type conn struct {
+	hub  *Hub
+	ws   *websocket.Conn
+	ctx  context.Context
+	user auth.Principal
+
+	send      chan interface{}
+	done      chan struct{}
+	closeOnce sync.Once
+
+	channels  map[string]channel
+	visible   map[uint64]bool
+	visibleAt time.Time
+	presence  map[presenceKey]presence
+	lastSeen  time.Time
+}
+
+// Serve runs one WebSocket connection of an authenticated request until the
+// client leaves, stops sending or the hub shuts down.
+func (h *Hub) Serve(ws *websocket.Conn) {
+	defer ws.Close()
+	ctx := ws.Request().Context()
+	p, ok := auth.FromContext(ctx)
+	if !ok {
+		_ = websocket.JSON.Send(ws, ServerMessage{Type: TypeError, Message: errUnauthenticated.Error()})
+		return
+	}
+	visible, err := h.visibleWorkspaces(ctx)
+	if err != nil {
+		_ = websocket.JSON.Send(ws, ServerMessage{Type: TypeError, Message: err.Error()})
+		return
+	}
+
+	// The connection outlives the server's request timeouts.
+	_ = ws.SetDeadline(time.Time{})
+	ws.MaxPayloadBytes = maxMessageBytes
+
+	now := time.Now()
+	c := &conn{
+		hub:       h,
+		ws:        ws,
+		ctx:       ctx,
+		user:      p,
+		send:      make(chan interface{}, sendBuffer),
+		done:      make(chan struct{}),
+		channels:  make(map[string]channel),
+		visible:   visible,
+		visibleAt: now,
+		presence:  make(map[presenceKey]presence),
+		lastSeen:  now,
+	}
+	if !h.add(c) {
+		return
+	}
+	defer h.remove(c)
+	defer c.close()
+
+	go c.writeLoop()
+	c.readLoop()
+}
+
+func (c *conn) readLoop() {
+	for {
+		_ = c.ws.SetReadDeadline(time.Now().Add(2 * c.hub.ttl))
+		var raw []byte
+		if err := websocket.Message.Receive(c.ws, &raw); err != nil {
+			return
+		}
+		var msg ClientMessage
+		if err := json.Unmarshal(raw, &msg); err != nil {
+			c.reply(msg.Ref, errInvalidMessage)
+			continue
+		}
+		c.hub.mu.Lock()
+		c.lastSeen = time.Now()
+		c.hub.mu.Unlock()
+		c.handle(msg)
+	}
+}
+
+func (c *conn) writeLoop() {
+	for {
+		select {
+		case msg := <-c.send:
+			_ = c.ws.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := websocket.JSON.Send(c.ws, msg); err != nil {
+				c.close()
+				return
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// queue hands msg to the writer, dropping the client if it cannot keep up.
+func (c *conn) queue(msg interface{}) {
+	select {
+	case <-c.done:
+	case c.send <- msg:
+	default:
+		c.close()
+	}
+}
+
+// close stops the connection. It runs under the hub's mutex, so the socket
+// is closed in the background rather than waiting on a stuck write.
+func (c *conn) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		go c.ws.Close()
+	})
+}
+
+// reply acknowledges the message with ref, or reports err.
+func (c *conn) reply(ref string, err error) {
+	if err != nil {
+		c.queue(ServerMessage{Type: TypeError, Ref: ref, Message: err.Error()})
+		return
+	}
+	c.queue(ServerMessage{Type: TypeAck, Ref: ref})
+}
+
+func (c *conn) handle(msg ClientMessage) {
+	switch msg.Type {
+	case TypeSubscribe:
+		if err := c.subscribe(msg); err != nil {
+			c.reply(msg.Ref, err)
+		}
+	case TypeUnsubscribe:
+		c.hub.mu.Lock()
+		delete(c.channels, msg.Channel)
+		c.hub.clearChannelPresence(c, msg.Channel)
+		c.queue(ServerMessage{Type: TypeUnsubscribed, Ref: msg.Ref, Channel: msg.Channel})
+		c.hub.mu.Unlock()
+	case TypeHeartbeat:
+		c.refreshVisible()
+	case TypePresence:
+		if err := c.setPresence(msg); err != nil {
+			c.reply(msg.Ref, err)
+		}
+	case TypeIntent:
+		undoToken, err := c.applyIntent(msg)
+		if err != nil {
+			c.reply(msg.Ref, err)
+			return
+		}
+		c.queue(ServerMessage{Type: TypeAck, Ref: msg.Ref, UndoToken: undoToken})
+	default:
+		c.reply(msg.Ref, errInvalidMessage)
+	}
+}
+
+func (c *conn) subscribe(msg ClientMessage) error {
+	ch, err := c.hub.parseChannel(c.ctx, msg.Channel)
+	if err != nil {
+		return err
+	}
+	c.hub.mu.Lock()
+	defer c.hub.mu.Unlock()
+	if _, ok := c.channels[msg.Channel]; !ok && len(c.channels) >= MaxSubscriptions {
+		return errTooManyChannels
+	}
+	c.channels[msg.Channel] = ch
+	c.queue(ServerMessage{Type: TypeSubscribed, Ref: msg.Ref, Channel: msg.Channel})
+	c.hub.sendPresence(c, msg.Channel)
+	return nil
+}
+
+// refreshVisible reloads the caller's workspaces once per presence TTL, so
+// joining or leaving a workspace reaches open connections.
+func (c *conn) refreshVisible() {
+	c.hub.mu.Lock()
+	stale := time.Since(c.visibleAt) > c.hub.ttl
+	c.hub.mu.Unlock()
+	if !stale {
+		return
+	}
+	visible, err := c.hub.visibleWorkspaces(c.ctx)
+	if err != nil {
+		return
+	}
+	c.hub.mu.Lock()
+	c.visible, c.visibleAt = visible, time.Now()
+	c.hub.mu.Unlock()
+}
+
+func (c *conn) setPresence(msg ClientMessage) error {
+	switch msg.State {
+	case StateViewing, StateEditing, StateIdle:
+	default:
+		return errInvalidPresence
+	}
+	c.hub.mu.Lock()
+	_, subscribed := c.channels[msg.Channel]
+	c.hub.mu.Unlock()
+	if !subscribed {
+		return errNotSubscribed
+	}
+	t, err := c.hub.tasks.Get(c.ctx, msg.TaskUUID, 0)
+	if err != nil {
+		return err
+	}
+	c.hub.setPresence(c, presenceKey{channel: msg.Channel, taskUUID: t.UUID}, t.WorkspaceID, msg.State)
+	return nil
+}
+
+// applyIntent runs an edit through task.Service; the resulting change
+// reaches every subscriber, the sender included, as an event.
+func (c *conn) applyIntent(msg ClientMessage) (string, error) {
+	tasks := c.hub.tasks
+	switch msg.Intent {
+	case IntentRename:
+		if msg.Title == nil {
+			return "", errInvalidIntent
+		}
+		_, token, err := tasks.Update(c.ctx, msg.TaskUUID, task.UpdatePayload{Title: msg.Title})
+		return token, err
+	case IntentMove:
+		_, token, err := tasks.UpdateStatus(c.ctx, msg.TaskUUID, task.UpdateStatusInput{Status: task.Status(msg.Status)})
+		return token, err
+	case IntentReorder:
+		return tasks.UpdateOrder(c.ctx, task.Status(msg.Status), msg.OrderedIDs, nil)
+	}
+	return "", errInvalidIntent
+}
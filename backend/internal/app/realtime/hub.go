@@ -0,0 +1,239 @@
+// Package realtime runs the WebSocket channel: clients subscribe to a status
+// column or project, receive its changes and who is on its tasks, and send
+// edit intents that go through task.Service like any other change.
+package realtime
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"todolist/backend/internal/app/dto"
+	"todolist/backend/internal/domain/project"
+	"todolist/backend/internal/domain/task"
+	"todolist/backend/internal/domain/workspace"
+	"todolist/backend/internal/pkg/events"
+)
+
+const (
+	DefaultPresenceTTL = 30 * time.Second
+
+	// MaxSubscriptions bounds the channels one connection may follow.
+	MaxSubscriptions = 32
+
+	channelStatus  = "status"
+	channelProject = "project"
+)
+
+type Options struct {
+	// PresenceTTL is how long presence lasts without a heartbeat. Connections
+	// silent for twice as long are closed.
+	PresenceTTL time.Duration
+}
+
+// Tasks is the part of task.Service the hub uses to check subscriptions and
+// presence and to apply edit intents.
+type Tasks interface {
+	Workflow() *task.Workflow
+	Get(ctx context.Context, uuid string, depth int) (*task.Task, error)
+	Update(ctx context.Context, uuid string, payload task.UpdatePayload) (*task.Task, string, error)
+	UpdateStatus(ctx context.Context, uuid string, input task.UpdateStatusInput) (*task.Task, string, error)
+	UpdateOrder(ctx context.Context, status task.Status, ordered []string, projectUUID *string) (string, error)
+}
+
+// Projects checks that the caller may follow a project.
+type Projects interface {
+	Get(ctx context.Context, uuid string) (*project.Project, error)
+}
+
+// Workspaces lists the workspaces the caller belongs to.
+type Workspaces interface {
+	List(ctx context.Context) ([]workspace.Workspace, error)
+}
+
+// Hub connects the WebSocket clients to the event bus.
+type Hub struct {
+	bus        *events.Bus
+	tasks      Tasks
+	projects   Projects
+	workspaces Workspaces
+	ttl        time.Duration
+	logger     *zap.Logger
+
+	mu     sync.Mutex
+	conns  map[*conn]struct{}
+	closed bool
+}
+
+func NewHub(bus *events.Bus, tasks Tasks, projects Projects, workspaces Workspaces, opts Options, logger *zap.Logger) *Hub {
+	if opts.PresenceTTL <= 0 {
+		opts.PresenceTTL = DefaultPresenceTTL
+	}
+	return &Hub{
+		bus:        bus,
+		tasks:      tasks,
+		projects:   projects,
+		workspaces: workspaces,
+		ttl:        opts.PresenceTTL,
+		logger:     logger,
+		conns:      make(map[*conn]struct{}),
+	}
+}
+
+// Run forwards bus events to subscribers and expires stale presence until
+// the bus is closed; it then closes every connection.
+func (h *Hub) Run() {
+	sweep := time.NewTicker(h.ttl / 3)
+	defer sweep.Stop()
+
+	for {
+		sub, _, _ := h.bus.Subscribe(0)
+	consume:
+		for {
+			select {
+			case e, ok := <-sub.C:
+				if !ok {
+					break consume
+				}
+				h.dispatch(e)
+			case <-sweep.C:
+				h.expirePresence(time.Now())
+			}
+		}
+		if h.bus.Closed() {
+			h.closeAll()
+			return
+		}
+		// The hub fell behind and lost events; clients have to reload.
+		h.logger.Warn("realtime hub dropped by event bus")
+		h.broadcast(ServerMessage{Type: TypeReset})
+	}
+}
+
+// channel is a parsed subscription such as "status:now" or "project:<uuid>".
+type channel struct {
+	kind  string
+	value string
+}
+
+func (ch channel) String() string {
+	return ch.kind + ":" + ch.value
+}
+
+func (ch channel) matches(snap task.Snapshot) bool {
+	switch ch.kind {
+	case channelStatus:
+		return string(snap.Status) == ch.value
+	case channelProject:
+		return snap.ProjectUUID != nil && *snap.ProjectUUID == ch.value
+	}
+	return false
+}
+
+// parseChannel checks that the caller may follow name.
+func (h *Hub) parseChannel(ctx context.Context, name string) (channel, error) {
+	kind, value, _ := strings.Cut(name, ":")
+	ch := channel{kind: kind, value: value}
+	switch kind {
+	case channelStatus:
+		if !h.tasks.Workflow().IsValid(task.Status(value)) {
+			return ch, errInvalidChannel
+		}
+		return ch, nil
+	case channelProject:
+		if _, err := h.projects.Get(ctx, value); err != nil {
+			return ch, err
+		}
+		return ch, nil
+	}
+	return ch, errInvalidChannel
+}
+
+// snapshotter is implemented by the payloads of task and undo events.
+type snapshotter interface {
+	Snapshots() []task.Snapshot
+}
+
+// dispatch hands e to every connection that follows a channel it touches
+// and may see its workspace.
+func (h *Hub) dispatch(e events.Event) {
+	data, ok := e.Data.(snapshotter)
+	if !ok {
+		return
+	}
+	snaps := data.Snapshots()
+	resp := dto.FromEvent(e)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for c := range h.conns {
+		if !c.visible[e.WorkspaceID] {
+			continue
+		}
+		for _, ch := range c.channels {
+			if matchesAny(ch, snaps) {
+				c.queue(ServerMessage{Type: TypeEvent, Channel: ch.String(), Event: &resp})
+				break
+			}
+		}
+	}
+}
+
+func matchesAny(ch channel, snaps []task.Snapshot) bool {
+	for _, snap := range snaps {
+		if ch.matches(snap) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *Hub) broadcast(msg interface{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for c := range h.conns {
+		c.queue(msg)
+	}
+}
+
+func (h *Hub) add(c *conn) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return false
+	}
+	h.conns[c] = struct{}{}
+	return true
+}
+
+// remove forgets a closed connection and withdraws its presence.
+func (h *Hub) remove(c *conn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.conns, c)
+	h.clearPresence(c)
+}
+
+func (h *Hub) closeAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for c := range h.conns {
+		c.close()
+	}
+}
+
+func (h *Hub) visibleWorkspaces(ctx context.Context) (map[uint64]bool, error) {
+	list, err := h.workspaces.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	visible := make(map[uint64]bool, len(list))
+	for _, w := range list {
+		visible[w.ID] = true
+	}
+	return visible, nil
+}
@@ -0,0 +1,78 @@
+package realtime
+
+import "todolist/backend/internal/app/dto"
+
+// Message types a client sends.
+const (
+	TypeSubscribe   = "subscribe"
+	TypeUnsubscribe = "unsubscribe"
+	TypeHeartbeat   = "heartbeat"
+	TypePresence    = "presence"
+	TypeIntent      = "intent"
+)
+
+// Message types the server sends. Presence updates reuse TypePresence.
+const (
+	TypeSubscribed   = "subscribed"
+	TypeUnsubscribed = "unsubscribed"
+	TypeEvent        = "event"
+	TypeAck          = "ack"
+	TypeError        = "error"
+	TypeReset        = "reset"
+)
+
+// Presence states. Idle withdraws the caller's presence on a task.
+const (
+	StateViewing = "viewing"
+	StateEditing = "editing"
+	StateIdle    = "idle"
+)
+
+// Edit intents a client may send.
+const (
+	IntentRename  = "rename"
+	IntentMove    = "move"
+	IntentReorder = "reorder"
+)
+
+// ClientMessage is anything a client sends. Type selects which fields apply:
+// Channel for (un)subscribe and presence, TaskUUID and State for presence,
+// and Intent with its fields for intents. Ref is echoed in the ack or error
+// answering the message.
+type ClientMessage struct {
+	Type       string   `json:"type"`
+	Ref        string   `json:"ref,omitempty"`
+	Channel    string   `json:"channel,omitempty"`
+	TaskUUID   string   `json:"taskUuid,omitempty"`
+	State      string   `json:"state,omitempty"`
+	Intent     string   `json:"intent,omitempty"`
+	Title      *string  `json:"title,omitempty"`
+	Status     string   `json:"status,omitempty"`
+	OrderedIDs []string `json:"orderedIds,omitempty"`
+}
+
+// ServerMessage is anything the server sends other than presence updates.
+type ServerMessage struct {
+	Type      string             `json:"type"`
+	Ref       string             `json:"ref,omitempty"`
+	Channel   string             `json:"channel,omitempty"`
+	Event     *dto.EventResponse `json:"event,omitempty"`
+	UndoToken string             `json:"undoToken,omitempty"`
+	Message   string             `json:"message,omitempty"`
+}
+
+// PresenceMessage lists who is on a task within a channel; an empty list
+// means nobody is left.
+type PresenceMessage struct {
+	Type     string   `json:"type"`
+	Channel  string   `json:"channel"`
+	TaskUUID string   `json:"taskUuid"`
+	Viewers  []Viewer `json:"viewers"`
+}
+
+// Viewer is one user present on a task.
+type Viewer struct {
+	UserID   uint64 `json:"userId"`
+	Username string `json:"username"`
+	State    string `json:"state"`
+}
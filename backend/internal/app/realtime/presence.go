@@ -0,0 +1,128 @@
+package realtime
+
+import (
+	"sort"
+	"time"
+)
+
+// presenceKey is a task as seen through one channel.
+type presenceKey struct {
+	channel  string
+	taskUUID string
+}
+
+type presence struct {
+	workspaceID uint64
+	state       string
+}
+
+// setPresence records or, for StateIdle, withdraws the presence of c on a
+// task and tells the channel.
+func (h *Hub) setPresence(c *conn, key presenceKey, workspaceID uint64, state string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if state == StateIdle {
+		if _, ok := c.presence[key]; !ok {
+			return
+		}
+		delete(c.presence, key)
+	} else {
+		if c.presence[key] == (presence{workspaceID, state}) {
+			return
+		}
+		c.presence[key] = presence{workspaceID: workspaceID, state: state}
+	}
+	h.announcePresence(key, workspaceID)
+}
+
+// clearPresence withdraws everything c is present on. h.mu must be held.
+func (h *Hub) clearPresence(c *conn) {
+	entries := c.presence
+	c.presence = make(map[presenceKey]presence)
+	for key, p := range entries {
+		h.announcePresence(key, p.workspaceID)
+	}
+}
+
+// clearChannelPresence withdraws what c is present on within one channel.
+// h.mu must be held.
+func (h *Hub) clearChannelPresence(c *conn, name string) {
+	for key, p := range c.presence {
+		if key.channel == name {
+			delete(c.presence, key)
+			h.announcePresence(key, p.workspaceID)
+		}
+	}
+}
+
+// expirePresence drops the presence of connections whose heartbeat stopped.
+func (h *Hub) expirePresence(now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for c := range h.conns {
+		if len(c.presence) > 0 && now.Sub(c.lastSeen) > h.ttl {
+			h.clearPresence(c)
+		}
+	}
+}
+
+// announcePresence sends who is on a task to the connections following its
+// channel that may see the workspace. h.mu must be held.
+func (h *Hub) announcePresence(key presenceKey, workspaceID uint64) {
+	msg := PresenceMessage{
+		Type:     TypePresence,
+		Channel:  key.channel,
+		TaskUUID: key.taskUUID,
+		Viewers:  h.viewers(key),
+	}
+	for c := range h.conns {
+		if _, ok := c.channels[key.channel]; ok && c.visible[workspaceID] {
+			c.queue(msg)
+		}
+	}
+}
+
+// sendPresence tells c who is on the tasks of a channel it just joined.
+// h.mu must be held.
+func (h *Hub) sendPresence(c *conn, name string) {
+	seen := make(map[presenceKey]bool)
+	for other := range h.conns {
+		for key, p := range other.presence {
+			if key.channel != name || seen[key] || !c.visible[p.workspaceID] {
+				continue
+			}
+			seen[key] = true
+			c.queue(PresenceMessage{
+				Type:     TypePresence,
+				Channel:  key.channel,
+				TaskUUID: key.taskUUID,
+				Viewers:  h.viewers(key),
+			})
+		}
+	}
+}
+
+// viewers lists each user on the task once, editing taking precedence over
+// viewing. h.mu must be held.
+func (h *Hub) viewers(key presenceKey) []Viewer {
+	byUser := make(map[uint64]Viewer)
+	for c := range h.conns {
+		p, ok := c.presence[key]
+		if !ok {
+			continue
+		}
+		v, seen := byUser[c.user.UserID]
+		if seen && v.State == StateEditing {
+			continue
+		}
+		byUser[c.user.UserID] = Viewer{UserID: c.user.UserID, Username: c.user.Username, State: p.state}
+	}
+	list := make([]Viewer, 0, len(byUser))
+	for _, v := range byUser {
+		list = append(list, v)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].UserID < list[j].UserID
+	})
+	return list
+}
@@ -0,0 +1,57 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"todolist/backend/internal/domain/outbox"
+	"todolist/backend/internal/domain/task"
+	"todolist/backend/internal/domain/undo"
+	"todolist/backend/internal/pkg/events"
+)
+
+// Relay returns the outbox consumer that publishes committed events on bus,
+// from where the WebSocket hub and the event stream pass them on. Going
+// through the outbox means only committed changes reach clients, in the
+// order they were written.
+func Relay(bus *events.Bus, logger *zap.Logger) outbox.Handler {
+	return outbox.HandlerFunc(func(ctx context.Context, msgs []outbox.Message) error {
+		for _, m := range msgs {
+			data, err := decodePayload(m)
+			if err != nil {
+				// Retrying would not help and would hold up every change
+				// after it.
+				logger.Warn("skip undecodable outbox message",
+					zap.Uint64("offset", m.ID), zap.String("type", m.Type), zap.Error(err))
+				continue
+			}
+			bus.Publish(events.Event{
+				Type:        m.Type,
+				WorkspaceID: m.WorkspaceID,
+				ActorID:     m.ActorID,
+				At:          m.OccurredAt,
+				Data:        data,
+			})
+		}
+		return nil
+	})
+}
+
+// decodePayload restores the payload types the hub routes by.
+func decodePayload(m outbox.Message) (interface{}, error) {
+	switch {
+	case strings.HasPrefix(m.Type, "task."):
+		var data task.ChangeData
+		err := json.Unmarshal([]byte(m.Payload), &data)
+		return data, err
+	case m.Type == undo.EventApplied:
+		var data undo.AppliedData
+		err := json.Unmarshal([]byte(m.Payload), &data)
+		return data, err
+	default:
+		return json.RawMessage(m.Payload), nil
+	}
+}
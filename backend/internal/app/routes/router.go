@@ -1,6 +1,8 @@
 package routes
 
 import (
+    "context"
+    "fmt"
     "net/http"
 
     "github.com/gin-gonic/gin"
@@ -9,19 +11,41 @@ import (
 
     "todolist/backend/internal/app/handler"
     "todolist/backend/internal/app/middleware"
+    "todolist/backend/internal/app/realtime"
+    "todolist/backend/internal/domain/attachment"
+    "todolist/backend/internal/domain/customfield"
+    "todolist/backend/internal/domain/idempotency"
+    "todolist/backend/internal/domain/outbox"
+    "todolist/backend/internal/domain/project"
+    "todolist/backend/internal/domain/tag"
     "todolist/backend/internal/domain/task"
+    "todolist/backend/internal/domain/template"
     "todolist/backend/internal/domain/undo"
+    "todolist/backend/internal/domain/user"
+    "todolist/backend/internal/domain/webhook"
+    "todolist/backend/internal/domain/workspace"
+    "todolist/backend/internal/infra/blob"
     "todolist/backend/internal/infra/config"
+    "todolist/backend/internal/infra/sink"
+    "todolist/backend/internal/pkg/auth"
+    "todolist/backend/internal/pkg/events"
     "todolist/backend/internal/pkg/response"
     "todolist/backend/internal/repository"
 )
 
-func SetupRouter(cfg *config.Config, log *zap.Logger, db *gorm.DB) *gin.Engine {
+// SetupRouter wires the services and routes. Background workers run until
+// ctx is cancelled.
+func SetupRouter(ctx context.Context, cfg *config.Config, log *zap.Logger, db *gorm.DB, bus *events.Bus) *gin.Engine {
     if cfg.App.Env == "production" {
         gin.SetMode(gin.ReleaseMode)
     }
 
     engine := gin.New()
+    // Client addresses key the public rate limits, so forwarding headers are
+    // only believed from the configured proxies.
+    if err := engine.SetTrustedProxies(cfg.Limits.TrustedProxies); err != nil {
+        log.Fatal("invalid trusted proxies", zap.Error(err))
+    }
     engine.Use(middleware.RequestID(), middleware.Logger(log), middleware.Recovery(log), middleware.CORS(cfg.CORS))
 
     engine.GET("/healthz", func(c *gin.Context) {
@@ -37,31 +61,265 @@ func SetupRouter(cfg *config.Config, log *zap.Logger, db *gorm.DB) *gin.Engine {
         c.JSON(http.StatusOK, gin.H{"status": "ok"})
     })
 
-    taskRepo := repository.NewTaskRepository(db)
+    workflow, err := buildWorkflow(cfg.Workflow)
+    if err != nil {
+        log.Fatal("invalid workflow config", zap.Error(err))
+    }
+
+    taskRepo := repository.NewTaskRepository(db, workflow)
     undoRepo := repository.NewUndoRepository(db)
+    tagRepo := repository.NewTagRepository(db)
+    projectRepo := repository.NewProjectRepository(db)
+    templateRepo := repository.NewTemplateRepository(db)
+    customFieldRepo := repository.NewCustomFieldRepository(db)
+    attachmentRepo := repository.NewAttachmentRepository(db)
+    userRepo := repository.NewUserRepository(db)
+    workspaceRepo := repository.NewWorkspaceRepository(db)
+    idempotencyRepo := repository.NewIdempotencyRepository(db)
+    webhookRepo := repository.NewWebhookRepository(db)
+    outboxRepo := repository.NewOutboxRepository(db)
+
+    blobStore, err := buildBlobStore(cfg.Attachment)
+    if err != nil {
+        log.Fatal("invalid attachment config", zap.Error(err))
+    }
 
     undoService := undo.NewService(undoRepo, taskRepo, cfg.Undo.TTL, log)
-    taskService := task.NewService(taskRepo, undoService, log)
+    taskService := task.NewService(taskRepo, undoService, log, task.Options{
+        AutoCompleteParent:   cfg.Task.AutoCompleteParent,
+        DailyCapacityMinutes: cfg.Task.DailyCapacityMinutes,
+        MinutesPerPoint:      cfg.Task.MinutesPerPoint,
+        WorkDaysPerWeek:      cfg.Task.WorkDaysPerWeek,
+        CommentEditWindow:    cfg.Task.CommentEditWindow,
+        MaxBulkIDs:           cfg.Limits.MaxBulkIDs,
+        Workflow:             workflow,
+    })
+    tagService := tag.NewService(tagRepo, log)
+    projectService := project.NewService(projectRepo, log)
+    templateService := template.NewService(templateRepo, taskService, log)
+    customFieldService := customfield.NewService(customFieldRepo, log)
+    attachmentService := attachment.NewService(attachmentRepo, blobStore, taskService, attachment.Limits{
+        MaxSize:      cfg.Attachment.MaxSize,
+        AllowedTypes: cfg.Attachment.AllowedTypes,
+    }, log)
+    taskService.AddPurgeListener(attachmentService)
+    taskService.AddPurgeListener(undoService)
+    userService := user.NewService(userRepo, user.Options{
+        SessionTTL:        cfg.Auth.SessionTTL,
+        AllowRegistration: cfg.Auth.AllowRegistration,
+    }, log)
+    workspaceService := workspace.NewService(workspaceRepo, workspace.Options{
+        InvitationTTL: cfg.Workspace.InvitationTTL,
+    }, log)
+    idempotencyService := idempotency.NewService(idempotencyRepo, idempotency.Options{
+        TTL: cfg.Idempotency.TTL,
+    }, log)
+    webhookService := webhook.NewService(webhookRepo, webhook.Options{
+        Timeout:              cfg.Webhook.Timeout,
+        MaxAttempts:          cfg.Webhook.MaxAttempts,
+        BackoffBase:          cfg.Webhook.BackoffBase,
+        BackoffMax:           cfg.Webhook.BackoffMax,
+        PollInterval:         cfg.Webhook.PollInterval,
+        AllowPrivateNetworks: cfg.Webhook.AllowPrivateNetworks,
+    }, log)
+    outboxService := outbox.NewService(outboxRepo, outbox.Options{
+        PollInterval: cfg.Outbox.PollInterval,
+        BatchSize:    cfg.Outbox.BatchSize,
+        GapTimeout:   cfg.Outbox.GapTimeout,
+        GapRecheck:   cfg.Outbox.GapRecheck,
+        RetryMax:     cfg.Outbox.RetryMax,
+        Retention:    cfg.Outbox.Retention,
+        Operators:    cfg.Outbox.Operators,
+    }, log)
+    userService.SetProvisioner(workspaceService)
+    taskService.SetAuthorizer(workspaceService)
+    undoService.SetAuthorizer(workspaceService)
+    projectService.SetAuthorizer(workspaceService)
+    webhookService.SetAuthorizer(workspaceService)
+    tagService.SetAuthorizer(workspaceService)
+    templateService.SetAuthorizer(workspaceService)
+    customFieldService.SetAuthorizer(workspaceService)
+    taskService.SetNotifier(outboxService)
+    undoService.SetNotifier(outboxService)
+    taskService.SetEventRecorder(outboxService)
+    undoService.SetEventRecorder(outboxService)
+    outboxService.Register("webhooks", webhookService)
+    outboxService.RegisterLive("realtime", realtime.Relay(bus, log))
+    for _, target := range cfg.Outbox.Sinks {
+        outboxService.Register("sink:"+target.Name, sink.NewHTTP(target.URL, target.Secret, target.Timeout))
+    }
+    go outboxService.Run(ctx)
+    go webhookService.Run(ctx)
 
     taskHandler := handler.NewTaskHandler(taskService)
     undoHandler := handler.NewUndoHandler(undoService)
+    tagHandler := handler.NewTagHandler(tagService)
+    projectHandler := handler.NewProjectHandler(projectService)
+    templateHandler := handler.NewTemplateHandler(templateService)
+    customFieldHandler := handler.NewCustomFieldHandler(customFieldService)
+    attachmentHandler := handler.NewAttachmentHandler(attachmentService, cfg.Attachment.TransferTimeout)
+    authHandler := handler.NewAuthHandler(userService, cfg.Auth.SecureCookie)
+    workspaceHandler := handler.NewWorkspaceHandler(workspaceService)
+    webhookHandler := handler.NewWebhookHandler(webhookService)
+    outboxHandler := handler.NewOutboxHandler(outboxService)
+    eventsHandler := handler.NewEventsHandler(bus, workspaceService, cfg.Events.Heartbeat)
+    hub := realtime.NewHub(bus, taskService, projectService, workspaceService, realtime.Options{
+        PresenceTTL: cfg.Realtime.PresenceTTL,
+    }, log)
+    go hub.Run()
+    realtimeHandler := handler.NewRealtimeHandler(hub, cfg.CORS.AllowOrigins)
+
+    limits := cfg.Limits
+    publicLimiter := middleware.NewRateLimiter(limits.RateLimit.Public.Rate, limits.RateLimit.Public.Burst)
+    apiLimiter := middleware.NewRateLimiter(limits.RateLimit.API.Rate, limits.RateLimit.API.Burst)
+    bulkLimiter := middleware.NewRateLimiter(limits.RateLimit.Bulk.Rate, limits.RateLimit.Bulk.Burst)
 
-    api := engine.Group("/api/v1")
+    uploads := map[string]int64{"/api/v1/tasks/:uuid/attachments": attachmentHandler.MaxUploadSize()}
+    // New API tokens and webhook secrets are shown once and must not be kept
+    // for replay. Uploads are streamed to the blob store; buffering them to
+    // fingerprint the request would hold whole files in memory.
+    unreplayable := map[string]bool{
+        "/api/v1/tokens":                             true,
+        "/api/v1/workspaces/:id/webhooks":            true,
+        "/api/v1/workspaces/:id/webhooks/:webhookId": true,
+    }
+    for path := range uploads {
+        unreplayable[path] = true
+    }
+
+    public := engine.Group("/api/v1", middleware.BodyLimit(limits.MaxBodyBytes, nil), middleware.RateLimit(publicLimiter))
     {
+        public.POST("/auth/register", authHandler.Register)
+        public.POST("/auth/login", authHandler.Login)
+    }
+
+    api := engine.Group("/api/v1", middleware.BodyLimit(limits.MaxBodyBytes, uploads), middleware.Auth(userService),
+        middleware.RateLimit(apiLimiter), middleware.ScopeByMethod(), middleware.Idempotency(idempotencyService, unreplayable, log))
+    {
+        api.POST("/auth/logout", authHandler.Logout)
+        api.GET("/auth/me", authHandler.Me)
+        api.GET("/events", eventsHandler.Stream)
+        api.GET("/ws", realtimeHandler.Connect)
+
+        tokens := api.Group("/tokens", middleware.RequireScope(auth.ScopeAdmin))
+        tokens.GET("", authHandler.ListTokens)
+        tokens.POST("", authHandler.CreateToken)
+        tokens.DELETE("/:id", authHandler.RevokeToken)
+
+        api.GET("/workspaces", workspaceHandler.List)
+        api.POST("/workspaces", workspaceHandler.Create)
+        api.GET("/workspaces/:id", workspaceHandler.Get)
+        api.PATCH("/workspaces/:id", workspaceHandler.Rename)
+        api.DELETE("/workspaces/:id", workspaceHandler.Delete)
+        api.GET("/workspaces/:id/members", workspaceHandler.Members)
+        api.PATCH("/workspaces/:id/members/:userId", workspaceHandler.UpdateMember)
+        api.DELETE("/workspaces/:id/members/:userId", workspaceHandler.RemoveMember)
+        api.GET("/workspaces/:id/invitations", workspaceHandler.Invitations)
+        api.POST("/workspaces/:id/invitations", workspaceHandler.Invite)
+        api.DELETE("/workspaces/:id/invitations/:invitationId", workspaceHandler.RevokeInvitation)
+
+        webhooks := api.Group("/workspaces/:id/webhooks", middleware.RequireScope(auth.ScopeAdmin))
+        webhooks.GET("", webhookHandler.List)
+        webhooks.POST("", webhookHandler.Create)
+        webhooks.GET("/:webhookId", webhookHandler.Get)
+        webhooks.PATCH("/:webhookId", webhookHandler.Update)
+        webhooks.DELETE("/:webhookId", webhookHandler.Delete)
+        webhooks.GET("/:webhookId/deliveries", webhookHandler.Deliveries)
+        webhooks.GET("/:webhookId/deliveries/:deliveryId", webhookHandler.Delivery)
+        webhooks.POST("/:webhookId/deliveries/:deliveryId/redeliver", webhookHandler.Redeliver)
+
+        api.GET("/invitations", workspaceHandler.PendingInvitations)
+        api.POST("/invitations/:id/accept", workspaceHandler.AcceptInvitation)
+        api.DELETE("/invitations/:id", workspaceHandler.DeclineInvitation)
+
+        api.GET("/workflow", taskHandler.Workflow)
         api.GET("/tasks", taskHandler.List)
+        api.GET("/plan/daily", taskHandler.DailyPlan)
         api.POST("/tasks", taskHandler.Create)
         api.GET("/tasks/:uuid", taskHandler.Get)
         api.PATCH("/tasks/:uuid", taskHandler.Update)
         api.PATCH("/tasks/:uuid/status", taskHandler.UpdateStatus)
         api.POST("/tasks/:uuid/complete", taskHandler.Complete)
         api.DELETE("/tasks/:uuid", taskHandler.Delete)
+        api.POST("/tasks/:uuid/duplicate", taskHandler.Duplicate)
+        api.POST("/tasks/:uuid/project", taskHandler.MoveToProject)
+        api.POST("/tasks/:uuid/parent", taskHandler.Reparent)
+        api.POST("/tasks/:uuid/dependencies", taskHandler.AddDependency)
+        api.DELETE("/tasks/:uuid/dependencies/:blockerUuid", taskHandler.RemoveDependency)
+        api.POST("/tasks/:uuid/checklist", taskHandler.AddChecklistItem)
+        api.POST("/tasks/:uuid/checklist/order", taskHandler.ReorderChecklist)
+        api.PATCH("/tasks/:uuid/checklist/:itemId", taskHandler.UpdateChecklistItem)
+        api.DELETE("/tasks/:uuid/checklist/:itemId", taskHandler.DeleteChecklistItem)
+        api.POST("/tasks/:uuid/checklist/:itemId/toggle", taskHandler.ToggleChecklistItem)
+        api.POST("/tasks/:uuid/checklist/:itemId/convert", taskHandler.ConvertChecklistItem)
+        api.POST("/tasks/:uuid/timer/start", taskHandler.StartTimer)
+        api.POST("/tasks/:uuid/timer/stop", taskHandler.StopTimer)
+        api.GET("/tasks/:uuid/time-entries", taskHandler.ListTimeEntries)
+        api.POST("/tasks/:uuid/time-entries", taskHandler.CreateTimeEntry)
+        api.PATCH("/tasks/:uuid/time-entries/:entryId", taskHandler.UpdateTimeEntry)
+        api.DELETE("/tasks/:uuid/time-entries/:entryId", taskHandler.DeleteTimeEntry)
+        api.GET("/timer", taskHandler.RunningTimer)
+        api.GET("/tasks/:uuid/comments", taskHandler.ListComments)
+        api.POST("/tasks/:uuid/comments", taskHandler.AddComment)
+        api.PATCH("/tasks/:uuid/comments/:commentId", taskHandler.EditComment)
+        api.DELETE("/tasks/:uuid/comments/:commentId", taskHandler.DeleteComment)
+        api.GET("/tasks/:uuid/activity", taskHandler.Activity)
+        api.GET("/tasks/:uuid/attachments", attachmentHandler.List)
+        api.POST("/tasks/:uuid/attachments", attachmentHandler.Upload)
+        api.GET("/tasks/:uuid/attachments/:attachmentId", attachmentHandler.Download)
+        api.DELETE("/tasks/:uuid/attachments/:attachmentId", attachmentHandler.Delete)
+        api.GET("/trash", taskHandler.ListTrash)
+        api.DELETE("/trash/:uuid", taskHandler.Purge)
+        api.GET("/timesheet", taskHandler.Timesheet)
+        api.GET("/reports/estimates", taskHandler.EstimateReport)
+        api.GET("/reports/capacity", taskHandler.Capacity)
 
-        api.POST("/tasks/bulk/move", taskHandler.BulkMove)
-        api.POST("/tasks/bulk/complete", taskHandler.BulkComplete)
-        api.POST("/tasks/bulk/delete", taskHandler.BulkDelete)
-        api.POST("/tasks/order", taskHandler.UpdateOrder)
+        bulk := api.Group("/tasks", middleware.RateLimit(bulkLimiter))
+        bulk.POST("/bulk/move", taskHandler.BulkMove)
+        bulk.POST("/bulk/complete", taskHandler.BulkComplete)
+        bulk.POST("/bulk/delete", taskHandler.BulkDelete)
+        bulk.POST("/bulk/tag", taskHandler.BulkTag)
+        bulk.POST("/bulk/untag", taskHandler.BulkUntag)
+        bulk.POST("/bulk/assign", taskHandler.BulkAssign)
+        bulk.POST("/bulk/unassign", taskHandler.BulkUnassign)
+        bulk.POST("/order", taskHandler.UpdateOrder)
+        bulk.POST("/order/assigned", taskHandler.ReorderAssigned)
+
+        api.GET("/tags", tagHandler.List)
+        api.POST("/tags", tagHandler.Create)
+        api.GET("/tags/:id", tagHandler.Get)
+        api.PATCH("/tags/:id", tagHandler.Rename)
+        api.DELETE("/tags/:id", tagHandler.Delete)
+        api.POST("/tags/:id/merge", tagHandler.Merge)
+
+        api.GET("/projects", projectHandler.List)
+        api.POST("/projects", projectHandler.Create)
+        api.GET("/projects/:uuid", projectHandler.Get)
+        api.PATCH("/projects/:uuid", projectHandler.Update)
+        api.DELETE("/projects/:uuid", projectHandler.Delete)
+        api.POST("/projects/:uuid/archive", projectHandler.Archive)
+        api.POST("/projects/:uuid/unarchive", projectHandler.Unarchive)
+
+        api.GET("/templates", templateHandler.List)
+        api.POST("/templates", templateHandler.Create)
+        api.GET("/templates/:id", templateHandler.Get)
+        api.PATCH("/templates/:id", templateHandler.Update)
+        api.DELETE("/templates/:id", templateHandler.Delete)
+        api.POST("/templates/:id/instantiate", templateHandler.Instantiate)
+        api.POST("/tasks/:uuid/template", templateHandler.SaveFromTask)
+
+        api.GET("/fields", customFieldHandler.List)
+        api.POST("/fields", customFieldHandler.Create)
+        api.GET("/fields/:id", customFieldHandler.Get)
+        api.PATCH("/fields/:id", customFieldHandler.Update)
+        api.DELETE("/fields/:id", customFieldHandler.Delete)
 
         api.POST("/undo", undoHandler.Undo)
+
+        admin := api.Group("/admin", middleware.RequireScope(auth.ScopeAdmin))
+        admin.GET("/outbox", outboxHandler.Status)
+        admin.GET("/outbox/messages", outboxHandler.Messages)
+        admin.POST("/outbox/consumers/:name/replay", outboxHandler.Replay)
     }
 
     engine.NoRoute(func(c *gin.Context) {
@@ -71,3 +329,68 @@ func SetupRouter(cfg *config.Config, log *zap.Logger, db *gorm.DB) *gin.Engine {
     return engine
 }
 
+
+// buildWorkflow turns the workflow section of the config into a task.Workflow,
+// falling back to the default board when no statuses are configured.
+func buildWorkflow(cfg config.WorkflowConfig) (*task.Workflow, error) {
+    if len(cfg.Statuses) == 0 {
+        return task.DefaultWorkflow(), nil
+    }
+    statuses := make([]task.StatusDef, 0, len(cfg.Statuses))
+    for _, s := range cfg.Statuses {
+        statuses = append(statuses, task.StatusDef{
+            Key:         task.Status(s.Key),
+            Name:        s.Name,
+            Terminal:    s.Terminal,
+            DefaultSort: task.SortKey(s.DefaultSort),
+        })
+    }
+    var transitions map[task.Status][]task.Status
+    if len(cfg.Transitions) > 0 {
+        transitions = make(map[task.Status][]task.Status, len(cfg.Transitions))
+        for from, targets := range cfg.Transitions {
+            allowed := make([]task.Status, 0, len(targets))
+            for _, to := range targets {
+                allowed = append(allowed, task.Status(to))
+            }
+            transitions[task.Status(from)] = allowed
+        }
+    }
+    initial := task.Status(cfg.Initial)
+    if initial == "" {
+        initial = statuses[0].Key
+    }
+    backlog := task.Status(cfg.Backlog)
+    if backlog == "" {
+        backlog = initial
+    }
+    active := task.Status(cfg.Active)
+    if active == "" {
+        for _, def := range statuses {
+            if !def.Terminal && def.Key != backlog {
+                active = def.Key
+                break
+            }
+        }
+    }
+    return task.NewWorkflow(statuses, initial, active, backlog, transitions)
+}
+
+// buildBlobStore opens the blob store selected in the attachment config.
+func buildBlobStore(cfg config.AttachmentConfig) (attachment.BlobStore, error) {
+    switch cfg.Store {
+    case "", "local":
+        return blob.NewLocalStore(cfg.LocalDir)
+    case "s3":
+        return blob.NewS3Store(blob.S3Config{
+            Endpoint:  cfg.S3.Endpoint,
+            Region:    cfg.S3.Region,
+            Bucket:    cfg.S3.Bucket,
+            AccessKey: cfg.S3.AccessKey,
+            SecretKey: cfg.S3.SecretKey,
+            PathStyle: cfg.S3.PathStyle,
+        })
+    default:
+        return nil, fmt.Errorf("unknown attachment store %q", cfg.Store)
+    }
+}
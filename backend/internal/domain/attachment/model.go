@@ -0,0 +1,21 @@
+package attachment
+
+import "time"
+
+// Attachment is a file stored in the blob store and linked to a task. Like
+// comments, attachments survive task soft-deletion and are only removed with
+// their blob when the task is purged or the attachment is deleted.
+type Attachment struct {
+	ID          uint64    `gorm:"primaryKey;autoIncrement"`
+	TaskUUID    string    `gorm:"type:char(36);index;not null"`
+	FileName    string    `gorm:"size:255;not null"`
+	ContentType string    `gorm:"size:128;not null"`
+	Size        int64     `gorm:"not null"`
+	Checksum    string    `gorm:"type:char(64);not null"`
+	BlobKey     string    `gorm:"size:255;not null;uniqueIndex"`
+	CreatedAt   time.Time `gorm:"not null;autoCreateTime"`
+}
+
+func (Attachment) TableName() string {
+	return "task_attachments"
+}
@@ -0,0 +1,33 @@
+package attachment
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"gorm.io/gorm"
+)
+
+// AttachmentRepository defines the interface for attachment repository operations
+type AttachmentRepository interface {
+	DB() *gorm.DB
+	Create(ctx context.Context, tx interface{}, a *Attachment) error
+	Get(ctx context.Context, tx interface{}, taskUUID string, id uint64) (*Attachment, error)
+	ListByTask(ctx context.Context, tx interface{}, taskUUID string) ([]Attachment, error)
+	ListByTasks(ctx context.Context, tx interface{}, taskUUIDs []string) ([]Attachment, error)
+	Delete(ctx context.Context, tx interface{}, id uint64) error
+	DeleteByTasks(ctx context.Context, tx interface{}, taskUUIDs []string) error
+}
+
+var ErrBlobNotFound = errors.New("blob not found")
+
+// BlobStore keeps attachment contents under opaque keys.
+type BlobStore interface {
+	// Put stores size bytes read from r under key.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// ReadRange streams length bytes of the blob starting at offset; a
+	// negative length reads to the end. Missing blobs yield ErrBlobNotFound.
+	ReadRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error)
+	// Delete removes the blob; deleting a missing blob is not an error.
+	Delete(ctx context.Context, key string) error
+}
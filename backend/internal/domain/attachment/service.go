@@ -0,0 +1,221 @@
+package attachment
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"todolist/backend/internal/domain/task"
+)
+
+// DefaultMaxSize is used when no size limit is configured: 25 MiB.
+const DefaultMaxSize int64 = 25 << 20
+
+var (
+	ErrAttachmentNotFound = errors.New("attachment not found")
+	ErrTooLarge           = errors.New("attachment too large")
+	ErrTypeNotAllowed     = errors.New("attachment type not allowed")
+	ErrInvalidFileName    = errors.New("invalid file name")
+)
+
+// Limits restricts uploads. AllowedTypes holds media types such as
+// "application/pdf" or wildcards such as "image/*"; empty allows any type.
+type Limits struct {
+	MaxSize      int64
+	AllowedTypes []string
+}
+
+type Service struct {
+	repo        AttachmentRepository
+	store       BlobStore
+	taskService *task.Service
+	limits      Limits
+	logger      *zap.Logger
+}
+
+func NewService(repo AttachmentRepository, store BlobStore, taskService *task.Service, limits Limits, logger *zap.Logger) *Service {
+	if limits.MaxSize <= 0 {
+		limits.MaxSize = DefaultMaxSize
+	}
+	return &Service{repo: repo, store: store, taskService: taskService, limits: limits, logger: logger}
+}
+
+// UploadInput is one uploaded file. ContentType is the type claimed by the
+// client; the stored type is sniffed from the content when that is more
+// specific.
+type UploadInput struct {
+	FileName    string
+	ContentType string
+	Size        int64
+	Body        io.Reader
+}
+
+func (s *Service) MaxSize() int64 {
+	return s.limits.MaxSize
+}
+
+func (s *Service) List(ctx context.Context, taskUUID string) ([]Attachment, error) {
+	if _, err := s.taskService.Get(ctx, taskUUID, 0); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByTask(ctx, nil, taskUUID)
+}
+
+func (s *Service) Get(ctx context.Context, taskUUID string, id uint64) (*Attachment, error) {
+	if _, err := s.taskService.Get(ctx, taskUUID, 0); err != nil {
+		return nil, err
+	}
+	a, err := s.repo.Get(ctx, nil, taskUUID, id)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil {
+		return nil, ErrAttachmentNotFound
+	}
+	return a, nil
+}
+
+// Upload streams the file into the blob store and records it on the task.
+// The blob is removed again if the record cannot be written.
+func (s *Service) Upload(ctx context.Context, taskUUID string, input UploadInput) (*Attachment, error) {
+	name := filepath.Base(strings.ReplaceAll(strings.TrimSpace(input.FileName), "\\", "/"))
+	if name == "" || name == "." || name == "/" || len(name) > 255 {
+		return nil, ErrInvalidFileName
+	}
+	if input.Size > s.limits.MaxSize {
+		return nil, ErrTooLarge
+	}
+	if _, err := s.taskService.Editable(ctx, taskUUID); err != nil {
+		return nil, err
+	}
+
+	body := bufio.NewReaderSize(input.Body, 512)
+	head, err := body.Peek(512)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, err
+	}
+	contentType := detectType(head, input.ContentType, name)
+	if !s.allowed(contentType) {
+		return nil, ErrTypeNotAllowed
+	}
+
+	key := taskUUID + "/" + uuid.NewString()
+	hash := sha256.New()
+	if err := s.store.Put(ctx, key, io.TeeReader(io.LimitReader(body, input.Size), hash), input.Size, contentType); err != nil {
+		return nil, err
+	}
+
+	a := &Attachment{
+		TaskUUID:    taskUUID,
+		FileName:    name,
+		ContentType: contentType,
+		Size:        input.Size,
+		Checksum:    hex.EncodeToString(hash.Sum(nil)),
+		BlobKey:     key,
+	}
+	if err := s.repo.Create(ctx, nil, a); err != nil {
+		s.removeBlobs(context.Background(), []string{key})
+		return nil, err
+	}
+	return a, nil
+}
+
+// Open returns a seekable reader over the attachment content for serving
+// range requests. The caller must close it.
+func (s *Service) Open(ctx context.Context, a *Attachment) io.ReadSeekCloser {
+	return &rangeReader{ctx: ctx, store: s.store, key: a.BlobKey, size: a.Size}
+}
+
+func (s *Service) Delete(ctx context.Context, taskUUID string, id uint64) error {
+	if _, err := s.taskService.Editable(ctx, taskUUID); err != nil {
+		return err
+	}
+	a, err := s.Get(ctx, taskUUID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, nil, a.ID); err != nil {
+		return err
+	}
+	s.removeBlobs(ctx, []string{a.BlobKey})
+	return nil
+}
+
+// TasksPurged drops the attachment records of purged tasks and removes their
+// blobs once the purge has committed.
+func (s *Service) TasksPurged(ctx context.Context, tx *gorm.DB, uuids []string) (func(), error) {
+	attachments, err := s.repo.ListByTasks(ctx, tx, uuids)
+	if err != nil {
+		return nil, err
+	}
+	if len(attachments) == 0 {
+		return nil, nil
+	}
+	if err := s.repo.DeleteByTasks(ctx, tx, uuids); err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(attachments))
+	for _, a := range attachments {
+		keys = append(keys, a.BlobKey)
+	}
+	return func() { s.removeBlobs(context.Background(), keys) }, nil
+}
+
+// removeBlobs deletes blobs whose records are already gone. Failures only
+// leave unreferenced blobs behind, so they are logged rather than returned.
+func (s *Service) removeBlobs(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := s.store.Delete(ctx, key); err != nil {
+			s.logger.Warn("remove blob failed", zap.String("key", key), zap.Error(err))
+		}
+	}
+}
+
+func (s *Service) allowed(contentType string) bool {
+	if len(s.limits.AllowedTypes) == 0 {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, pattern := range s.limits.AllowedTypes {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if pattern == mediaType || pattern == "*/*" {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "/*"); ok && strings.HasPrefix(mediaType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// detectType sniffs the content type. Generic results fall back to the type
+// claimed by the client and then to the file extension.
+func detectType(head []byte, claimed, name string) string {
+	sniffed := http.DetectContentType(head)
+	if sniffed != "application/octet-stream" && !strings.HasPrefix(sniffed, "text/plain") {
+		return sniffed
+	}
+	if claimed != "" {
+		if mediaType, params, err := mime.ParseMediaType(claimed); err == nil && mediaType != "application/octet-stream" {
+			return mime.FormatMediaType(mediaType, params)
+		}
+	}
+	if byExt := mime.TypeByExtension(filepath.Ext(name)); byExt != "" {
+		return byExt
+	}
+	return sniffed
+}
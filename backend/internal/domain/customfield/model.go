@@ -0,0 +1,81 @@
+package customfield
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type Type string
+
+const (
+	TypeText     Type = "text"
+	TypeNumber   Type = "number"
+	TypeDate     Type = "date"
+	TypeSelect   Type = "select"
+	TypeURL      Type = "url"
+	TypeCheckbox Type = "checkbox"
+)
+
+func IsValidType(t Type) bool {
+	switch t {
+	case TypeText, TypeNumber, TypeDate, TypeSelect, TypeURL, TypeCheckbox:
+		return true
+	}
+	return false
+}
+
+// Field defines a custom field available on every task of its workspace. Key
+// is the stable name used in requests and filters, unique within the
+// workspace; Options holds the JSON list of choices of a select field.
+type Field struct {
+	ID          uint64    `gorm:"primaryKey;autoIncrement"`
+	WorkspaceID uint64    `gorm:"not null;default:0;uniqueIndex:idx_custom_fields_workspace_key"`
+	Key         string    `gorm:"size:64;not null;uniqueIndex:idx_custom_fields_workspace_key"`
+	Name        string    `gorm:"size:128;not null"`
+	Type        Type      `gorm:"type:enum('text','number','date','select','url','checkbox');not null"`
+	Options     *string   `gorm:"type:json"`
+	CreatedAt   time.Time `gorm:"not null;autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"not null;autoUpdateTime"`
+}
+
+func (Field) TableName() string {
+	return "custom_fields"
+}
+
+// Value is the normalized value of a field on a task, keyed by task UUID so
+// that soft-deleted tasks keep their values when restored through undo.
+type Value struct {
+	TaskUUID string `gorm:"type:char(36);primaryKey" json:"taskUuid"`
+	FieldID  uint64 `gorm:"primaryKey;index" json:"fieldId"`
+	Value    string `gorm:"type:text;not null" json:"value"`
+	Field    *Field `gorm:"foreignKey:FieldID" json:"-"`
+}
+
+func (Value) TableName() string {
+	return "task_custom_values"
+}
+
+func (f *Field) Choices() []string {
+	if f.Options == nil {
+		return nil
+	}
+	var choices []string
+	if err := json.Unmarshal([]byte(*f.Options), &choices); err != nil {
+		return nil
+	}
+	return choices
+}
+
+func (f *Field) SetChoices(choices []string) error {
+	if f.Type != TypeSelect {
+		f.Options = nil
+		return nil
+	}
+	data, err := json.Marshal(choices)
+	if err != nil {
+		return err
+	}
+	options := string(data)
+	f.Options = &options
+	return nil
+}
@@ -0,0 +1,19 @@
+package customfield
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// FieldRepository defines the interface for custom field repository operations
+type FieldRepository interface {
+	DB() *gorm.DB
+	List(ctx context.Context, workspaceID *uint64) ([]Field, error)
+	GetByID(ctx context.Context, tx interface{}, id uint64) (*Field, error)
+	GetByKey(ctx context.Context, tx interface{}, workspaceID uint64, key string) (*Field, error)
+	Create(ctx context.Context, tx interface{}, f *Field) error
+	Update(ctx context.Context, tx interface{}, f *Field) error
+	Delete(ctx context.Context, tx interface{}, id uint64) error
+	CountValues(ctx context.Context, tx interface{}, fieldID uint64, values []string) (int64, error)
+}
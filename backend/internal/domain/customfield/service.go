@@ -0,0 +1,259 @@
+package customfield
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"todolist/backend/internal/domain/workspace"
+)
+
+// MaxChoices bounds the number of options of a select field.
+const MaxChoices = 100
+
+var (
+	ErrFieldNotFound  = errors.New("custom field not found")
+	ErrFieldExists    = errors.New("custom field already exists")
+	ErrInvalidKey     = errors.New("invalid custom field key")
+	ErrInvalidName    = errors.New("invalid custom field name")
+	ErrInvalidType    = errors.New("invalid custom field type")
+	ErrInvalidOptions = errors.New("invalid custom field options")
+	ErrOptionInUse    = errors.New("custom field option in use")
+)
+
+var keyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+type Service struct {
+	repo   FieldRepository
+	access Authorizer
+	logger *zap.Logger
+}
+
+// Authorizer checks what the caller may do in a workspace.
+type Authorizer interface {
+	Authorize(ctx context.Context, tx interface{}, workspaceID uint64, role workspace.Role) error
+	DefaultWorkspace(ctx context.Context, tx interface{}) (uint64, error)
+}
+
+func NewService(repo FieldRepository, logger *zap.Logger) *Service {
+	return &Service{repo: repo, logger: logger}
+}
+
+// SetAuthorizer enables role checks: every member of a workspace sees its
+// fields, changing them needs the editor role.
+func (s *Service) SetAuthorizer(a Authorizer) {
+	s.access = a
+}
+
+type CreateInput struct {
+	Key     string
+	Name    string
+	Type    Type
+	Options []string
+	// WorkspaceID defaults to the caller's default workspace.
+	WorkspaceID *uint64
+}
+
+// UpdateInput changes the display name or the choices of a field. The key and
+// type are fixed once created since stored values depend on them.
+type UpdateInput struct {
+	Name       *string
+	Options    []string
+	OptionsSet bool
+}
+
+// List returns the fields of every workspace the caller belongs to, or of
+// workspaceID when set.
+func (s *Service) List(ctx context.Context, workspaceID *uint64) ([]Field, error) {
+	if workspaceID != nil {
+		if err := s.authorize(ctx, nil, *workspaceID, workspace.RoleViewer); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.List(ctx, workspaceID)
+}
+
+func (s *Service) Get(ctx context.Context, id uint64) (*Field, error) {
+	f, err := s.repo.GetByID(ctx, nil, id)
+	if err != nil {
+		return nil, err
+	}
+	if f == nil {
+		return nil, ErrFieldNotFound
+	}
+	return f, nil
+}
+
+func (s *Service) Create(ctx context.Context, input CreateInput) (*Field, error) {
+	key := strings.TrimSpace(input.Key)
+	if !keyPattern.MatchString(key) {
+		return nil, ErrInvalidKey
+	}
+	name := strings.TrimSpace(input.Name)
+	if name == "" || len(name) > 128 {
+		return nil, ErrInvalidName
+	}
+	if !IsValidType(input.Type) {
+		return nil, ErrInvalidType
+	}
+	choices, err := normalizeChoices(input.Type, input.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	workspaceID, err := s.targetWorkspace(ctx, input.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, nil, workspaceID, workspace.RoleEditor); err != nil {
+		return nil, err
+	}
+
+	f := &Field{WorkspaceID: workspaceID, Key: key, Name: name, Type: input.Type}
+	if err := f.SetChoices(choices); err != nil {
+		return nil, err
+	}
+	err = s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		existing, err := s.repo.GetByKey(ctx, tx, workspaceID, key)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return ErrFieldExists
+		}
+		return s.repo.Create(ctx, tx, f)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (s *Service) Update(ctx context.Context, id uint64, input UpdateInput) (*Field, error) {
+	var updated *Field
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		existing, err := s.repo.GetByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return ErrFieldNotFound
+		}
+		if err := s.authorize(ctx, tx, existing.WorkspaceID, workspace.RoleEditor); err != nil {
+			return err
+		}
+		if input.Name != nil {
+			name := strings.TrimSpace(*input.Name)
+			if name == "" || len(name) > 128 {
+				return ErrInvalidName
+			}
+			existing.Name = name
+		}
+		if input.OptionsSet {
+			choices, err := normalizeChoices(existing.Type, input.Options)
+			if err != nil {
+				return err
+			}
+			// A choice still set on some task cannot be dropped.
+			if removed := missingChoices(existing.Choices(), choices); len(removed) > 0 {
+				inUse, err := s.repo.CountValues(ctx, tx, existing.ID, removed)
+				if err != nil {
+					return err
+				}
+				if inUse > 0 {
+					return ErrOptionInUse
+				}
+			}
+			if err := existing.SetChoices(choices); err != nil {
+				return err
+			}
+		}
+		if err := s.repo.Update(ctx, tx, existing); err != nil {
+			return err
+		}
+		updated = existing
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// Delete removes the field together with its values on every task of its
+// workspace.
+func (s *Service) Delete(ctx context.Context, id uint64) error {
+	return s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		existing, err := s.repo.GetByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return ErrFieldNotFound
+		}
+		if err := s.authorize(ctx, tx, existing.WorkspaceID, workspace.RoleEditor); err != nil {
+			return err
+		}
+		return s.repo.Delete(ctx, tx, id)
+	})
+}
+
+func (s *Service) targetWorkspace(ctx context.Context, workspaceID *uint64) (uint64, error) {
+	if workspaceID != nil {
+		return *workspaceID, nil
+	}
+	if s.access == nil {
+		return 0, nil
+	}
+	return s.access.DefaultWorkspace(ctx, nil)
+}
+
+func (s *Service) authorize(ctx context.Context, tx interface{}, workspaceID uint64, role workspace.Role) error {
+	if s.access == nil {
+		return nil
+	}
+	return s.access.Authorize(ctx, tx, workspaceID, role)
+}
+
+// normalizeChoices trims the choices of a select field and rejects empty,
+// duplicate or missing ones. Other field types take no choices.
+func normalizeChoices(t Type, choices []string) ([]string, error) {
+	if t != TypeSelect {
+		if len(choices) > 0 {
+			return nil, ErrInvalidOptions
+		}
+		return nil, nil
+	}
+	if len(choices) == 0 || len(choices) > MaxChoices {
+		return nil, ErrInvalidOptions
+	}
+	seen := make(map[string]bool, len(choices))
+	result := make([]string, 0, len(choices))
+	for _, c := range choices {
+		c = strings.TrimSpace(c)
+		if c == "" || len(c) > 128 || seen[c] {
+			return nil, ErrInvalidOptions
+		}
+		seen[c] = true
+		result = append(result, c)
+	}
+	return result, nil
+}
+
+func missingChoices(before, after []string) []string {
+	kept := make(map[string]bool, len(after))
+	for _, c := range after {
+		kept[c] = true
+	}
+	var removed []string
+	for _, c := range before {
+		if !kept[c] {
+			removed = append(removed, c)
+		}
+	}
+	return removed
+}
@@ -0,0 +1,113 @@
+package customfield
+
+import (
+	"errors"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxTextLength bounds text and url values, in characters.
+const MaxTextLength = 2000
+
+var ErrInvalidValue = errors.New("invalid custom field value")
+
+// Normalize validates a value decoded from JSON (string, number or bool)
+// against the field type and returns its stored form: numbers in shortest
+// decimal notation, dates as YYYY-MM-DD and checkboxes as "true" or "false".
+func (f *Field) Normalize(raw any) (string, error) {
+	switch f.Type {
+	case TypeText:
+		s, ok := raw.(string)
+		if !ok {
+			return "", ErrInvalidValue
+		}
+		s = strings.TrimSpace(s)
+		if utf8.RuneCountInString(s) > MaxTextLength {
+			return "", ErrInvalidValue
+		}
+		return s, nil
+	case TypeNumber:
+		var n float64
+		switch v := raw.(type) {
+		case float64:
+			n = v
+		case int:
+			n = float64(v)
+		case string:
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				return "", ErrInvalidValue
+			}
+			n = parsed
+		default:
+			return "", ErrInvalidValue
+		}
+		if math.IsNaN(n) || math.IsInf(n, 0) {
+			return "", ErrInvalidValue
+		}
+		return strconv.FormatFloat(n, 'f', -1, 64), nil
+	case TypeDate:
+		s, ok := raw.(string)
+		if !ok {
+			return "", ErrInvalidValue
+		}
+		d, err := time.Parse("2006-01-02", strings.TrimSpace(s))
+		if err != nil {
+			return "", ErrInvalidValue
+		}
+		return d.Format("2006-01-02"), nil
+	case TypeSelect:
+		s, ok := raw.(string)
+		if !ok {
+			return "", ErrInvalidValue
+		}
+		for _, choice := range f.Choices() {
+			if choice == s {
+				return s, nil
+			}
+		}
+		return "", ErrInvalidValue
+	case TypeURL:
+		s, ok := raw.(string)
+		if !ok {
+			return "", ErrInvalidValue
+		}
+		s = strings.TrimSpace(s)
+		u, err := url.ParseRequestURI(s)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || len(s) > MaxTextLength {
+			return "", ErrInvalidValue
+		}
+		return s, nil
+	case TypeCheckbox:
+		switch v := raw.(type) {
+		case bool:
+			return strconv.FormatBool(v), nil
+		case string:
+			b, err := strconv.ParseBool(strings.TrimSpace(v))
+			if err != nil {
+				return "", ErrInvalidValue
+			}
+			return strconv.FormatBool(b), nil
+		}
+		return "", ErrInvalidValue
+	}
+	return "", ErrInvalidValue
+}
+
+// Decode turns a stored value back into its JSON form: float64 for numbers,
+// bool for checkboxes and string otherwise.
+func (f *Field) Decode(stored string) any {
+	switch f.Type {
+	case TypeNumber:
+		if n, err := strconv.ParseFloat(stored, 64); err == nil {
+			return n
+		}
+	case TypeCheckbox:
+		return stored == "true"
+	}
+	return stored
+}
@@ -0,0 +1,28 @@
+package idempotency
+
+import "time"
+
+// Record remembers the response to a request sent with an Idempotency-Key so
+// a retry gets the same answer instead of repeating the change. RequestHash
+// fingerprints method, path and body; a record without a status code is
+// still being handled.
+type Record struct {
+	ID          uint64    `gorm:"primaryKey;autoIncrement"`
+	UserID      uint64    `gorm:"not null;uniqueIndex:idx_idempotency_user_key,priority:1"`
+	Key         string    `gorm:"column:idempotency_key;size:255;not null;uniqueIndex:idx_idempotency_user_key,priority:2"`
+	RequestHash string    `gorm:"type:char(64);not null"`
+	StatusCode  int       `gorm:"not null;default:0"`
+	ContentType string    `gorm:"size:128"`
+	Body        []byte    `gorm:"type:mediumblob"`
+	ExpiresAt   time.Time `gorm:"not null;index"`
+	CreatedAt   time.Time `gorm:"not null;autoCreateTime"`
+}
+
+func (Record) TableName() string {
+	return "idempotency_keys"
+}
+
+// Completed reports whether the response has been stored.
+func (r Record) Completed() bool {
+	return r.StatusCode != 0
+}
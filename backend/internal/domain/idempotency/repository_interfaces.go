@@ -0,0 +1,20 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// IdempotencyRepository defines the interface for idempotency key persistence
+type IdempotencyRepository interface {
+	DB() *gorm.DB
+	// Reserve inserts r unless the user already holds its key, reporting
+	// whether it did.
+	Reserve(ctx context.Context, tx interface{}, r *Record) (bool, error)
+	Get(ctx context.Context, tx interface{}, userID uint64, key string) (*Record, error)
+	SaveResponse(ctx context.Context, tx interface{}, userID uint64, key string, status int, contentType string, body []byte) error
+	Delete(ctx context.Context, tx interface{}, userID uint64, key string) error
+	DeleteExpired(ctx context.Context, tx interface{}, userID uint64, now time.Time) error
+}
@@ -0,0 +1,105 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxKeyLength is the longest Idempotency-Key accepted.
+	MaxKeyLength = 255
+
+	DefaultTTL = 24 * time.Hour
+)
+
+var (
+	ErrInvalidKey = errors.New("invalid idempotency key")
+	ErrKeyReused  = errors.New("idempotency key reused with a different request")
+	ErrInProgress = errors.New("request with this idempotency key is still in progress")
+)
+
+type Options struct {
+	// TTL is how long a key and its response are kept.
+	TTL time.Duration
+}
+
+type Service struct {
+	repo   IdempotencyRepository
+	opts   Options
+	logger *zap.Logger
+}
+
+func NewService(repo IdempotencyRepository, opts Options, logger *zap.Logger) *Service {
+	if opts.TTL <= 0 {
+		opts.TTL = DefaultTTL
+	}
+	return &Service{repo: repo, opts: opts, logger: logger}
+}
+
+// Begin claims key for a request of userID fingerprinted by requestHash. It
+// returns nil when the request should run, or the stored record when the
+// same request already completed and its response should be replayed.
+// Reusing a key for another request fails with ErrKeyReused, retrying one
+// that has not finished yet with ErrInProgress.
+func (s *Service) Begin(ctx context.Context, userID uint64, key, requestHash string) (*Record, error) {
+	key = strings.TrimSpace(key)
+	if key == "" || len(key) > MaxKeyLength {
+		return nil, ErrInvalidKey
+	}
+
+	var stored *Record
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := s.repo.DeleteExpired(ctx, tx, userID, now); err != nil {
+			return err
+		}
+		reserved, err := s.repo.Reserve(ctx, tx, &Record{
+			UserID:      userID,
+			Key:         key,
+			RequestHash: requestHash,
+			ExpiresAt:   now.Add(s.opts.TTL),
+		})
+		if err != nil || reserved {
+			return err
+		}
+
+		existing, err := s.repo.Get(ctx, tx, userID, key)
+		if err != nil {
+			return err
+		}
+		switch {
+		case existing == nil:
+			return ErrInProgress
+		case existing.RequestHash != requestHash:
+			return ErrKeyReused
+		case !existing.Completed():
+			return ErrInProgress
+		}
+		stored = existing
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stored, nil
+}
+
+// Complete stores the response to the request holding key. Server errors are
+// not stored; the key is released instead so the client can retry.
+func (s *Service) Complete(ctx context.Context, userID uint64, key string, status int, contentType string, body []byte) error {
+	key = strings.TrimSpace(key)
+	if status >= 500 {
+		return s.repo.Delete(ctx, nil, userID, key)
+	}
+	return s.repo.SaveResponse(ctx, nil, userID, key, status, contentType, body)
+}
+
+// Release drops a key whose request ended without a response to store.
+func (s *Service) Release(ctx context.Context, userID uint64, key string) error {
+	return s.repo.Delete(ctx, nil, userID, strings.TrimSpace(key))
+}
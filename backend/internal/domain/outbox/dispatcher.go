@@ -0,0 +1,322 @@
+package outbox
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	pruneInterval = time.Hour
+	pruneBatch    = 1000
+	// maxRecheckGap caps the skipped offsets recorded per batch. A wider
+	// gap is pruned history rather than transactions still running.
+	maxRecheckGap = 1000
+)
+
+// Run relays messages to every registered consumer until ctx is cancelled.
+// Each consumer has its own cursor and goroutine, so a failing one only
+// holds up itself: its batch is retried with a growing wait and nothing
+// after it is handed over meanwhile, which keeps the order intact. Old
+// messages are pruned once every consumer with a stored cursor is past
+// them. Live consumers start after the newest message.
+func (s *Service) Run(ctx context.Context) {
+	s.mu.Lock()
+	consumers := append([]consumer(nil), s.consumers...)
+	s.mu.Unlock()
+	for i := range consumers {
+		consumers[i].mem = newMemCursor(consumers[i].live)
+	}
+
+	var wg sync.WaitGroup
+	for _, c := range consumers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.consume(ctx, c)
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.pruneLoop(ctx)
+	}()
+	wg.Wait()
+}
+
+func (s *Service) consume(ctx context.Context, c consumer) {
+	var wait, retry time.Duration
+	for {
+		if wait > 0 {
+			// A failing consumer keeps its back-off; an idle one looks as
+			// soon as it is told of new messages.
+			wake := c.wake
+			if retry > 0 {
+				wake = nil
+			}
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			case <-wake:
+				timer.Stop()
+			}
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		n, err := s.dispatch(ctx, c)
+		switch {
+		case err != nil:
+			if ctx.Err() != nil {
+				return
+			}
+			retry = s.nextRetry(retry)
+			wait = retry
+			s.logger.Warn("outbox consumer failed",
+				zap.String("consumer", c.name), zap.Duration("retry_in", wait), zap.Error(err))
+		case n >= s.opts.BatchSize:
+			// A full batch suggests a backlog, so look again without waiting.
+			wait, retry = 0, 0
+		default:
+			wait, retry = s.opts.PollInterval, 0
+		}
+	}
+}
+
+// dispatch hands c the messages that turned up late for offsets it skipped,
+// then the next batch, and moves its cursor past that batch. It returns how
+// many messages were handed over.
+func (s *Service) dispatch(ctx context.Context, c consumer) (int, error) {
+	if c.live {
+		return s.dispatchLive(ctx, c)
+	}
+	cur, err := s.cursor(ctx, c)
+	if err != nil {
+		return 0, err
+	}
+	late, err := s.redeliver(ctx, c)
+	if err != nil {
+		return 0, err
+	}
+	ready, skipped, now, err := s.next(ctx, c, cur.LastOffset)
+	if err != nil || len(ready) == 0 {
+		return late, err
+	}
+	// A replay or another process may have moved the cursor meanwhile; the
+	// next round then starts from where it is now, and the gaps are left
+	// to whoever moved it.
+	err = s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		moved, err := s.repo.AdvanceCursor(ctx, tx, c.name, cur.LastOffset, ready[len(ready)-1].ID)
+		if err != nil || !moved {
+			return err
+		}
+		return s.repo.AddGaps(ctx, tx, c.name, skipped, now)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return late + len(ready), nil
+}
+
+// dispatchLive is dispatch for a live consumer, whose position and skipped
+// offsets are kept in memory.
+func (s *Service) dispatchLive(ctx context.Context, c consumer) (int, error) {
+	mem := c.mem
+	if !mem.started {
+		head, err := s.repo.Head(ctx, nil)
+		if err != nil {
+			return 0, err
+		}
+		mem.offset, mem.started = head, true
+	}
+	late, err := s.redeliverLive(ctx, c)
+	if err != nil {
+		return 0, err
+	}
+	ready, skipped, now, err := s.next(ctx, c, mem.offset)
+	if err != nil || len(ready) == 0 {
+		return late, err
+	}
+	mem.offset = ready[len(ready)-1].ID
+	for _, id := range skipped {
+		mem.gaps[id] = now
+	}
+	return late + len(ready), nil
+}
+
+// next hands c the settled messages after offset and returns them along
+// with the offsets skipped on the way.
+func (s *Service) next(ctx context.Context, c consumer, offset uint64) ([]Message, []uint64, time.Time, error) {
+	msgs, err := s.repo.ListAfter(ctx, nil, offset, s.opts.BatchSize)
+	if err != nil {
+		return nil, nil, time.Time{}, err
+	}
+	now := time.Now()
+	ready, skipped := s.settled(offset, msgs, now)
+	if len(ready) == 0 {
+		return nil, nil, now, nil
+	}
+	if err := c.handler.HandleMessages(ctx, ready); err != nil {
+		return nil, nil, now, err
+	}
+	return ready, skipped, now, nil
+}
+
+// cursor returns the stored position of c, creating it at the start on
+// first use.
+func (s *Service) cursor(ctx context.Context, c consumer) (*Cursor, error) {
+	cur, err := s.repo.GetCursor(ctx, nil, c.name)
+	if err != nil || cur != nil {
+		return cur, err
+	}
+	if err := s.repo.EnsureCursor(ctx, nil, c.name, 0); err != nil {
+		return nil, err
+	}
+	// Another process may have created it first.
+	cur, err = s.repo.GetCursor(ctx, nil, c.name)
+	if err == nil && cur == nil {
+		err = ErrConsumerNotFound
+	}
+	return cur, err
+}
+
+// redeliver hands c the messages that have turned up for offsets it was
+// moved past within GapRecheck, and forgets those offsets.
+func (s *Service) redeliver(ctx context.Context, c consumer) (int, error) {
+	since := time.Now().Add(-s.opts.GapRecheck)
+	msgs, err := s.repo.ListFilledGaps(ctx, nil, c.name, since, s.opts.BatchSize)
+	if err != nil || len(msgs) == 0 {
+		return 0, err
+	}
+	if err := c.handler.HandleMessages(ctx, msgs); err != nil {
+		return 0, err
+	}
+	offsets := make([]uint64, 0, len(msgs))
+	for _, m := range msgs {
+		offsets = append(offsets, m.ID)
+	}
+	if err := s.repo.DeleteGaps(ctx, nil, c.name, offsets); err != nil {
+		return 0, err
+	}
+	s.logger.Warn("outbox messages handed over after their gap timed out",
+		zap.String("consumer", c.name), zap.Int("count", len(msgs)), zap.Duration("gap_timeout", s.opts.GapTimeout))
+	return len(msgs), nil
+}
+
+// redeliverLive is redeliver for a live consumer.
+func (s *Service) redeliverLive(ctx context.Context, c consumer) (int, error) {
+	since := time.Now().Add(-s.opts.GapRecheck)
+	ids := make([]uint64, 0, len(c.mem.gaps))
+	for id, at := range c.mem.gaps {
+		if at.Before(since) {
+			delete(c.mem.gaps, id)
+			continue
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	msgs, err := s.repo.ListByIDs(ctx, nil, ids, s.opts.BatchSize)
+	if err != nil || len(msgs) == 0 {
+		return 0, err
+	}
+	if err := c.handler.HandleMessages(ctx, msgs); err != nil {
+		return 0, err
+	}
+	for _, m := range msgs {
+		delete(c.mem.gaps, m.ID)
+	}
+	s.logger.Warn("outbox messages handed over after their gap timed out",
+		zap.String("consumer", c.name), zap.Int("count", len(msgs)), zap.Duration("gap_timeout", s.opts.GapTimeout))
+	return len(msgs), nil
+}
+
+// settled returns the leading messages that may be handed over: those that
+// follow offset without a gap, and those after a gap once they are older
+// than GapTimeout, when the missing offsets are taken to have rolled back.
+// It also returns the offsets skipped that way, so they can be looked for
+// again in case their transaction was only slow.
+func (s *Service) settled(offset uint64, msgs []Message, now time.Time) ([]Message, []uint64) {
+	var skipped []uint64
+	next := offset + 1
+	for i, m := range msgs {
+		if m.ID != next && now.Sub(m.CreatedAt) < s.opts.GapTimeout {
+			return msgs[:i], skipped
+		}
+		if m.ID-next <= uint64(maxRecheckGap-len(skipped)) {
+			for id := next; id < m.ID; id++ {
+				skipped = append(skipped, id)
+			}
+		}
+		next = m.ID + 1
+	}
+	return msgs, skipped
+}
+
+func (s *Service) nextRetry(last time.Duration) time.Duration {
+	if last <= 0 {
+		return s.opts.PollInterval
+	}
+	if last *= 2; last > s.opts.RetryMax {
+		last = s.opts.RetryMax
+	}
+	return last
+}
+
+func (s *Service) pruneLoop(ctx context.Context) {
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := s.prune(ctx); err != nil && ctx.Err() == nil {
+			s.logger.Warn("prune outbox failed", zap.Error(err))
+		}
+	}
+}
+
+// prune deletes messages past Retention that every consumer with a stored
+// cursor has handled. Live consumers do not hold it back.
+func (s *Service) prune(ctx context.Context) error {
+	offset, err := s.repo.Head(ctx, nil)
+	if err != nil {
+		return err
+	}
+	names := s.consumerNames()
+	if len(names) > 0 {
+		cursors, err := s.repo.ListCursors(ctx, nil, names)
+		if err != nil {
+			return err
+		}
+		if len(cursors) < len(names) {
+			// A consumer has not started yet and still needs everything.
+			return nil
+		}
+		for _, c := range cursors {
+			if c.LastOffset < offset {
+				offset = c.LastOffset
+			}
+		}
+	}
+	if err := s.repo.PruneGaps(ctx, nil, time.Now().Add(-s.opts.GapRecheck)); err != nil {
+		return err
+	}
+	before := time.Now().Add(-s.opts.Retention)
+	for {
+		n, err := s.repo.Prune(ctx, nil, offset, before, pruneBatch)
+		if err != nil || n < pruneBatch {
+			return err
+		}
+	}
+}
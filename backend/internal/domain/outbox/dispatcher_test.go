@@ -0,0 +1,87 @@
+package outbox
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestSettledRecordsSkippedOffsets(t *testing.T) {
+	s := NewService(nil, Options{GapTimeout: 10 * time.Second}, zap.NewNop())
+	now := time.Now()
+	old, fresh := now.Add(-time.Minute), now
+
+	ready, skipped := s.settled(3, []Message{
+		{ID: 4, CreatedAt: old},
+		{ID: 7, CreatedAt: old},
+		{ID: 8, CreatedAt: fresh},
+		{ID: 10, CreatedAt: fresh},
+	}, now)
+	if len(ready) != 3 || ready[2].ID != 8 {
+		t.Fatalf("ready = %+v, want offsets 4 to 8", ready)
+	}
+	if want := []uint64{5, 6}; !reflect.DeepEqual(skipped, want) {
+		t.Fatalf("skipped = %v, want %v", skipped, want)
+	}
+
+	// A gap as wide as pruned history is passed without being recorded.
+	ready, skipped = s.settled(0, []Message{{ID: 5000, CreatedAt: old}}, now)
+	if len(ready) != 1 || len(skipped) != 0 {
+		t.Fatalf("ready = %+v, skipped %d offsets", ready, len(skipped))
+	}
+}
+
+// memRepo serves messages from memory; methods a live consumer does not use
+// are left to the embedded nil interface.
+type memRepo struct {
+	OutboxRepository
+	msgs []Message
+}
+
+func (r *memRepo) Head(ctx context.Context, tx interface{}) (uint64, error) {
+	return r.msgs[len(r.msgs)-1].ID, nil
+}
+
+func (r *memRepo) ListAfter(ctx context.Context, tx interface{}, offset uint64, limit int) ([]Message, error) {
+	var list []Message
+	for _, m := range r.msgs {
+		if m.ID > offset && len(list) < limit {
+			list = append(list, m)
+		}
+	}
+	return list, nil
+}
+
+func TestLiveConsumerStartsAtHeadInMemory(t *testing.T) {
+	now := time.Now()
+	repo := &memRepo{msgs: []Message{{ID: 1, CreatedAt: now}, {ID: 2, CreatedAt: now}}}
+	s := NewService(repo, Options{}, zap.NewNop())
+	var got []uint64
+	s.RegisterLive("realtime", HandlerFunc(func(ctx context.Context, msgs []Message) error {
+		for _, m := range msgs {
+			got = append(got, m.ID)
+		}
+		return nil
+	}))
+	s.Register("webhooks", HandlerFunc(func(ctx context.Context, msgs []Message) error { return nil }))
+
+	c := s.consumers[0]
+	c.mem = newMemCursor(true)
+	if _, err := s.dispatch(context.Background(), c); err != nil {
+		t.Fatal(err)
+	}
+	repo.msgs = append(repo.msgs, Message{ID: 3, CreatedAt: now})
+	if _, err := s.dispatch(context.Background(), c); err != nil {
+		t.Fatal(err)
+	}
+	if want := []uint64{3}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("handed over %v, want %v", got, want)
+	}
+	// Only stored cursors hold back pruning and show up in Status.
+	if names := s.consumerNames(); !reflect.DeepEqual(names, []string{"webhooks"}) {
+		t.Fatalf("consumer names = %v", names)
+	}
+}
@@ -0,0 +1,74 @@
+package outbox
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Message is a committed event as stored in the outbox. Its ID is the offset
+// consumers read from: offsets only grow, and a change holding a task's row
+// lock writes its messages before the next change of that task can, so
+// reading in offset order sees every task's changes in the order they
+// happened.
+type Message struct {
+	ID          uint64    `gorm:"primaryKey;autoIncrement"`
+	EventID     string    `gorm:"type:char(36);not null;uniqueIndex"`
+	Type        string    `gorm:"size:64;not null"`
+	WorkspaceID uint64    `gorm:"not null;index"`
+	ActorID     uint64    `gorm:"not null"`
+	Payload     string    `gorm:"type:mediumtext;not null"`
+	OccurredAt  time.Time `gorm:"not null"`
+	CreatedAt   time.Time `gorm:"not null;autoCreateTime;index"`
+}
+
+func (Message) TableName() string {
+	return "outbox_messages"
+}
+
+// Envelope is the JSON form of a message handed to external systems.
+type Envelope struct {
+	ID          string          `json:"id"`
+	Offset      uint64          `json:"offset"`
+	Type        string          `json:"type"`
+	WorkspaceID uint64          `json:"workspaceId"`
+	ActorID     uint64          `json:"actorId,omitempty"`
+	At          string          `json:"at"`
+	Data        json.RawMessage `json:"data"`
+}
+
+func (m Message) Envelope() Envelope {
+	return Envelope{
+		ID:          m.EventID,
+		Offset:      m.ID,
+		Type:        m.Type,
+		WorkspaceID: m.WorkspaceID,
+		ActorID:     m.ActorID,
+		At:          m.OccurredAt.UTC().Format(time.RFC3339Nano),
+		Data:        json.RawMessage(m.Payload),
+	}
+}
+
+// Cursor is how far a consumer has got: every message up to LastOffset has
+// been handed to it successfully.
+type Cursor struct {
+	Consumer   string    `gorm:"primaryKey;size:64"`
+	LastOffset uint64    `gorm:"not null"`
+	UpdatedAt  time.Time `gorm:"not null;autoUpdateTime"`
+}
+
+func (Cursor) TableName() string {
+	return "outbox_cursors"
+}
+
+// Gap is an offset a consumer was moved past without its message, which was
+// taken to have rolled back. Its transaction may only have been slow, so the
+// offset is looked for again until GapRecheck has passed.
+type Gap struct {
+	Consumer  string    `gorm:"primaryKey;size:64"`
+	MessageID uint64    `gorm:"primaryKey;autoIncrement:false"`
+	SkippedAt time.Time `gorm:"not null;index"`
+}
+
+func (Gap) TableName() string {
+	return "outbox_gaps"
+}
@@ -0,0 +1,46 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OutboxRepository defines the interface for outbox message and consumer
+// cursor persistence
+type OutboxRepository interface {
+	DB() *gorm.DB
+	Append(ctx context.Context, tx interface{}, list []Message) error
+	// ListAfter returns up to limit messages with an offset above offset, in
+	// offset order.
+	ListAfter(ctx context.Context, tx interface{}, offset uint64, limit int) ([]Message, error)
+	// ListByIDs returns up to limit of the messages with the given offsets,
+	// in offset order.
+	ListByIDs(ctx context.Context, tx interface{}, ids []uint64, limit int) ([]Message, error)
+	// Head returns the highest offset written so far.
+	Head(ctx context.Context, tx interface{}) (uint64, error)
+	// Prune deletes up to limit messages at or below offset written before
+	// before, and returns how many went.
+	Prune(ctx context.Context, tx interface{}, offset uint64, before time.Time, limit int) (int64, error)
+
+	// EnsureCursor creates a cursor at offset unless one exists.
+	EnsureCursor(ctx context.Context, tx interface{}, consumer string, offset uint64) error
+	GetCursor(ctx context.Context, tx interface{}, consumer string) (*Cursor, error)
+	ListCursors(ctx context.Context, tx interface{}, consumers []string) ([]Cursor, error)
+	// AdvanceCursor moves a cursor from from to to and reports whether it was
+	// still at from.
+	AdvanceCursor(ctx context.Context, tx interface{}, consumer string, from, to uint64) (bool, error)
+	SetCursor(ctx context.Context, tx interface{}, consumer string, offset uint64) error
+
+	// AddGaps records offsets a consumer was moved past at skippedAt.
+	AddGaps(ctx context.Context, tx interface{}, consumer string, offsets []uint64, skippedAt time.Time) error
+	// ListFilledGaps returns up to limit messages that have turned up for
+	// gaps of consumer skipped since since, in offset order.
+	ListFilledGaps(ctx context.Context, tx interface{}, consumer string, since time.Time, limit int) ([]Message, error)
+	DeleteGaps(ctx context.Context, tx interface{}, consumer string, offsets []uint64) error
+	// ClearGaps forgets the gaps of consumer at or above offset.
+	ClearGaps(ctx context.Context, tx interface{}, consumer string, offset uint64) error
+	// PruneGaps forgets every gap skipped before before.
+	PruneGaps(ctx context.Context, tx interface{}, before time.Time) error
+}
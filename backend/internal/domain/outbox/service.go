@@ -0,0 +1,343 @@
+// Package outbox stores the events of every change in the transaction that
+// makes the change and relays them to consumers once committed, so an event
+// is neither announced for a change that rolled back nor lost when the
+// process dies right after a commit.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"todolist/backend/internal/pkg/auth"
+	"todolist/backend/internal/pkg/events"
+)
+
+const (
+	DefaultPollInterval = time.Second
+	DefaultBatchSize    = 100
+	DefaultGapTimeout   = 10 * time.Second
+	DefaultGapRecheck   = 10 * time.Minute
+	DefaultRetryMax     = time.Minute
+	DefaultRetention    = 7 * 24 * time.Hour
+
+	maxMessageList = 500
+)
+
+var (
+	ErrConsumerNotFound = errors.New("outbox consumer not found")
+	ErrInvalidOffset    = errors.New("invalid outbox offset")
+	ErrNotOperator      = errors.New("operator access required")
+)
+
+// Handler consumes outbox messages. It gets them in offset order, one batch
+// at a time, and a batch is handed over again until HandleMessages returns
+// nil. After an error, a crash or a replay it may see messages it has
+// handled before, so it has to tolerate duplicates, e.g. by Message.EventID.
+// A message whose transaction outlasted GapTimeout comes late, after
+// messages with higher offsets.
+type Handler interface {
+	HandleMessages(ctx context.Context, msgs []Message) error
+}
+
+// HandlerFunc adapts a function to Handler.
+type HandlerFunc func(ctx context.Context, msgs []Message) error
+
+func (f HandlerFunc) HandleMessages(ctx context.Context, msgs []Message) error {
+	return f(ctx, msgs)
+}
+
+type Options struct {
+	// PollInterval is how often an idle consumer looks for new messages.
+	PollInterval time.Duration
+	// BatchSize caps the messages handed to a consumer at once.
+	BatchSize int
+	// GapTimeout is how long a missing offset is waited for. Offsets are
+	// taken when a message is written but become visible at commit, so a
+	// gap is usually a transaction still running; once the messages after
+	// it are older than this it is taken to have rolled back. It must
+	// exceed the longest transaction.
+	GapTimeout time.Duration
+	// GapRecheck is how long an offset skipped after GapTimeout is still
+	// looked for. A message that turns up for it within this window, from
+	// a transaction slower than GapTimeout, is handed over late rather
+	// than lost.
+	GapRecheck time.Duration
+	// RetryMax caps the wait between retries of a failing batch; the wait
+	// starts at PollInterval and doubles.
+	RetryMax time.Duration
+	// Retention is how long messages every consumer has handled are kept
+	// for replay.
+	Retention time.Duration
+	// Operators are the usernames allowed to inspect the outbox and replay
+	// it.
+	Operators []string
+}
+
+type Service struct {
+	repo      OutboxRepository
+	opts      Options
+	operators map[string]bool
+	logger    *zap.Logger
+
+	mu        sync.Mutex
+	consumers []consumer
+}
+
+type consumer struct {
+	name    string
+	handler Handler
+	// live consumers start at the newest message rather than the oldest
+	// and keep their position in mem, set up by Run, instead of a stored
+	// cursor.
+	live bool
+	mem  *memCursor
+	wake chan struct{}
+}
+
+// memCursor is the position of a live consumer. Only the consumer's own
+// goroutine touches it.
+type memCursor struct {
+	started bool
+	offset  uint64
+	gaps    map[uint64]time.Time
+}
+
+func NewService(repo OutboxRepository, opts Options, logger *zap.Logger) *Service {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultPollInterval
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+	if opts.GapTimeout <= 0 {
+		opts.GapTimeout = DefaultGapTimeout
+	}
+	if opts.GapRecheck <= 0 {
+		opts.GapRecheck = DefaultGapRecheck
+	}
+	if opts.RetryMax < opts.PollInterval {
+		opts.RetryMax = DefaultRetryMax
+	}
+	if opts.Retention <= 0 {
+		opts.Retention = DefaultRetention
+	}
+	operators := make(map[string]bool, len(opts.Operators))
+	for _, name := range opts.Operators {
+		operators[name] = true
+	}
+	return &Service{repo: repo, opts: opts, operators: operators, logger: logger}
+}
+
+// Register adds a consumer under name, which keys its cursor; a new name
+// starts from the oldest retained message. Consumers registered after Run
+// has started are not served.
+func (s *Service) Register(name string, h Handler) {
+	s.register(name, h, false)
+}
+
+// RegisterLive adds a consumer that only cares about what happens from now
+// on, such as one that pushes changes to connected clients. Its position is
+// kept in memory and starts after the newest message every time Run starts,
+// so each process relays on its own, nothing stale is pushed after a
+// restart, and it never holds back pruning. It is not listed by Status and
+// cannot be replayed.
+func (s *Service) RegisterLive(name string, h Handler) {
+	s.register(name, h, true)
+}
+
+func (s *Service) register(name string, h Handler, live bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.consumers {
+		if s.consumers[i].name == name {
+			s.consumers[i].handler = h
+			s.consumers[i].live = live
+			return
+		}
+	}
+	s.consumers = append(s.consumers, consumer{name: name, handler: h, live: live, wake: make(chan struct{}, 1)})
+}
+
+func newMemCursor(live bool) *memCursor {
+	if !live {
+		return nil
+	}
+	return &memCursor{gaps: make(map[uint64]time.Time)}
+}
+
+// Notify tells the consumers that new messages have been committed, so they
+// look at once instead of at their next poll. Messages written by other
+// processes are still picked up by polling.
+func (s *Service) Notify() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.consumers {
+		select {
+		case c.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// RecordEvents writes evs to the outbox inside tx, the transaction of the
+// change they describe.
+func (s *Service) RecordEvents(ctx context.Context, tx *gorm.DB, evs []events.Event) error {
+	if len(evs) == 0 {
+		return nil
+	}
+	now := time.Now()
+	list := make([]Message, 0, len(evs))
+	for _, e := range evs {
+		payload, err := json.Marshal(e.Data)
+		if err != nil {
+			return err
+		}
+		at := e.At
+		if at.IsZero() {
+			at = now
+		}
+		list = append(list, Message{
+			EventID:     uuid.NewString(),
+			Type:        e.Type,
+			WorkspaceID: e.WorkspaceID,
+			ActorID:     e.ActorID,
+			Payload:     string(payload),
+			OccurredAt:  at,
+		})
+	}
+	return s.repo.Append(ctx, tx, list)
+}
+
+// ConsumerStatus is how far a registered consumer has got.
+type ConsumerStatus struct {
+	Name       string
+	LastOffset uint64
+	UpdatedAt  time.Time
+}
+
+// Status returns the highest offset written and the position of every
+// registered consumer.
+func (s *Service) Status(ctx context.Context) (uint64, []ConsumerStatus, error) {
+	if err := s.operator(ctx); err != nil {
+		return 0, nil, err
+	}
+	head, err := s.repo.Head(ctx, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	names := s.consumerNames()
+	cursors, err := s.repo.ListCursors(ctx, nil, names)
+	if err != nil {
+		return 0, nil, err
+	}
+	byName := make(map[string]Cursor, len(cursors))
+	for _, c := range cursors {
+		byName[c.Consumer] = c
+	}
+	result := make([]ConsumerStatus, 0, len(names))
+	for _, name := range names {
+		c := byName[name]
+		result = append(result, ConsumerStatus{Name: name, LastOffset: c.LastOffset, UpdatedAt: c.UpdatedAt})
+	}
+	return head, result, nil
+}
+
+// Messages lists up to limit retained messages after offset.
+func (s *Service) Messages(ctx context.Context, after uint64, limit int) ([]Message, error) {
+	if err := s.operator(ctx); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > maxMessageList {
+		limit = maxMessageList
+	}
+	return s.repo.ListAfter(ctx, nil, after, limit)
+}
+
+// Replay moves a consumer back, or forward, so that offset is the next
+// message it gets, and returns the highest offset written along with the
+// consumer's new position. Messages already pruned cannot be replayed.
+func (s *Service) Replay(ctx context.Context, name string, offset uint64) (uint64, *ConsumerStatus, error) {
+	if err := s.operator(ctx); err != nil {
+		return 0, nil, err
+	}
+	if !s.registered(name) {
+		return 0, nil, ErrConsumerNotFound
+	}
+	var head uint64
+	var status *ConsumerStatus
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		var err error
+		head, err = s.repo.Head(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if offset == 0 || offset > head+1 {
+			return ErrInvalidOffset
+		}
+		if err := s.repo.EnsureCursor(ctx, tx, name, 0); err != nil {
+			return err
+		}
+		if err := s.repo.SetCursor(ctx, tx, name, offset-1); err != nil {
+			return err
+		}
+		// Offsets from the new position on are handed over in order again.
+		if err := s.repo.ClearGaps(ctx, tx, name, offset); err != nil {
+			return err
+		}
+		c, err := s.repo.GetCursor(ctx, tx, name)
+		if err != nil {
+			return err
+		}
+		status = &ConsumerStatus{Name: name, LastOffset: c.LastOffset, UpdatedAt: c.UpdatedAt}
+		return nil
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	s.logger.Info("outbox consumer replayed",
+		zap.String("consumer", name), zap.Uint64("offset", offset), zap.Uint64("user_id", auth.OwnerID(ctx)))
+	return head, status, nil
+}
+
+// consumerNames lists the consumers with a stored cursor; live ones are
+// left out.
+func (s *Service) consumerNames() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.consumers))
+	for _, c := range s.consumers {
+		if !c.live {
+			names = append(names, c.name)
+		}
+	}
+	return names
+}
+
+func (s *Service) registered(name string) bool {
+	for _, n := range s.consumerNames() {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// operator lets internal callers marked with auth.WithSystem through and
+// otherwise requires one of the configured operators.
+func (s *Service) operator(ctx context.Context) error {
+	if auth.IsSystem(ctx) {
+		return nil
+	}
+	p, ok := auth.FromContext(ctx)
+	if !ok || !s.operators[p.Username] {
+		return ErrNotOperator
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package project
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Project struct {
+	ID          uint64         `gorm:"primaryKey;autoIncrement"`
+	UUID        string         `gorm:"type:char(36);uniqueIndex"`
+	WorkspaceID uint64         `gorm:"not null;default:0;index"`
+	Name        string         `gorm:"size:128;not null"`
+	Color       *string        `gorm:"size:16"`
+	Archived    bool           `gorm:"not null;default:false;index"`
+	CreatedAt   time.Time      `gorm:"not null;autoCreateTime"`
+	UpdatedAt   time.Time      `gorm:"not null;autoUpdateTime"`
+	DeletedAt   gorm.DeletedAt `gorm:"index"`
+}
@@ -0,0 +1,18 @@
+package project
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// ProjectRepository defines the interface for project repository operations
+type ProjectRepository interface {
+	DB() *gorm.DB
+	List(ctx context.Context, workspaceID *uint64, includeArchived bool) ([]Project, error)
+	GetByUUID(ctx context.Context, tx interface{}, uuid string) (*Project, error)
+	Create(ctx context.Context, tx interface{}, p *Project) error
+	Update(ctx context.Context, tx interface{}, p *Project) error
+	DeleteByUUID(ctx context.Context, tx interface{}, uuid string) error
+	CountTasks(ctx context.Context, tx interface{}, uuid string) (int64, error)
+}
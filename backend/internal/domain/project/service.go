@@ -0,0 +1,200 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"todolist/backend/internal/domain/workspace"
+)
+
+type Service struct {
+	repo   ProjectRepository
+	access Authorizer
+	logger *zap.Logger
+}
+
+// Authorizer checks what the caller may do in a workspace.
+type Authorizer interface {
+	Authorize(ctx context.Context, tx interface{}, workspaceID uint64, role workspace.Role) error
+	DefaultWorkspace(ctx context.Context, tx interface{}) (uint64, error)
+}
+
+func NewService(repo ProjectRepository, logger *zap.Logger) *Service {
+	return &Service{repo: repo, logger: logger}
+}
+
+// SetAuthorizer enables role checks: every member of a workspace sees its
+// projects, changing them needs the editor role.
+func (s *Service) SetAuthorizer(a Authorizer) {
+	s.access = a
+}
+
+type CreateInput struct {
+	Name  string
+	Color *string
+	// WorkspaceID defaults to the caller's default workspace.
+	WorkspaceID *uint64
+}
+
+type UpdateInput struct {
+	Name     *string
+	Color    *string
+	ColorSet bool
+}
+
+var (
+	ErrProjectNotFound = errors.New("project not found")
+	ErrProjectArchived = errors.New("project is archived")
+	ErrProjectNotEmpty = errors.New("project is not empty")
+	ErrInvalidName     = errors.New("invalid project name")
+)
+
+// List returns the projects of every workspace the caller belongs to, or of
+// workspaceID when set.
+func (s *Service) List(ctx context.Context, workspaceID *uint64, includeArchived bool) ([]Project, error) {
+	if workspaceID != nil {
+		if err := s.authorize(ctx, nil, *workspaceID, workspace.RoleViewer); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.List(ctx, workspaceID, includeArchived)
+}
+
+func (s *Service) Get(ctx context.Context, uuid string) (*Project, error) {
+	p, err := s.repo.GetByUUID(ctx, nil, uuid)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, ErrProjectNotFound
+	}
+	return p, nil
+}
+
+func (s *Service) Create(ctx context.Context, input CreateInput) (*Project, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, ErrInvalidName
+	}
+	var workspaceID uint64
+	if input.WorkspaceID != nil {
+		workspaceID = *input.WorkspaceID
+	} else if s.access != nil {
+		id, err := s.access.DefaultWorkspace(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		workspaceID = id
+	}
+	if err := s.authorize(ctx, nil, workspaceID, workspace.RoleEditor); err != nil {
+		return nil, err
+	}
+	p := &Project{
+		UUID:        uuid.NewString(),
+		WorkspaceID: workspaceID,
+		Name:        name,
+		Color:       input.Color,
+	}
+	if err := s.repo.Create(ctx, nil, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (s *Service) Update(ctx context.Context, uuid string, input UpdateInput) (*Project, error) {
+	var updated *Project
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		existing, err := s.repo.GetByUUID(ctx, tx, uuid)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return ErrProjectNotFound
+		}
+		if err := s.authorize(ctx, tx, existing.WorkspaceID, workspace.RoleEditor); err != nil {
+			return err
+		}
+		if input.Name != nil {
+			name := strings.TrimSpace(*input.Name)
+			if name == "" {
+				return ErrInvalidName
+			}
+			existing.Name = name
+		}
+		if input.ColorSet {
+			existing.Color = input.Color
+		}
+		if err := s.repo.Update(ctx, tx, existing); err != nil {
+			return err
+		}
+		updated = existing
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (s *Service) SetArchived(ctx context.Context, uuid string, archived bool) (*Project, error) {
+	var updated *Project
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		existing, err := s.repo.GetByUUID(ctx, tx, uuid)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return ErrProjectNotFound
+		}
+		if err := s.authorize(ctx, tx, existing.WorkspaceID, workspace.RoleEditor); err != nil {
+			return err
+		}
+		existing.Archived = archived
+		if err := s.repo.Update(ctx, tx, existing); err != nil {
+			return err
+		}
+		updated = existing
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// Delete removes an empty project. Projects that still own tasks should be
+// archived instead, or emptied by moving their tasks elsewhere first.
+func (s *Service) Delete(ctx context.Context, uuid string) error {
+	return s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		existing, err := s.repo.GetByUUID(ctx, tx, uuid)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return ErrProjectNotFound
+		}
+		if err := s.authorize(ctx, tx, existing.WorkspaceID, workspace.RoleEditor); err != nil {
+			return err
+		}
+		count, err := s.repo.CountTasks(ctx, tx, uuid)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrProjectNotEmpty
+		}
+		return s.repo.DeleteByUUID(ctx, tx, uuid)
+	})
+}
+
+func (s *Service) authorize(ctx context.Context, tx interface{}, workspaceID uint64, role workspace.Role) error {
+	if s.access == nil {
+		return nil
+	}
+	return s.access.Authorize(ctx, tx, workspaceID, role)
+}
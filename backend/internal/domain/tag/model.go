@@ -0,0 +1,23 @@
+package tag
+
+import "time"
+
+// Tag is a label for the tasks of one workspace; names are unique within it.
+type Tag struct {
+	ID          uint64    `gorm:"primaryKey;autoIncrement"`
+	WorkspaceID uint64    `gorm:"not null;default:0;uniqueIndex:idx_tags_workspace_name"`
+	Name        string    `gorm:"size:64;not null;uniqueIndex:idx_tags_workspace_name"`
+	CreatedAt   time.Time `gorm:"not null;autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"not null;autoUpdateTime"`
+}
+
+// TaskTag is the join row between a task and a tag, keyed by task UUID so that
+// soft-deleted tasks keep their tags when restored through undo.
+type TaskTag struct {
+	TaskUUID string `gorm:"type:char(36);primaryKey"`
+	TagID    uint64 `gorm:"primaryKey;index"`
+}
+
+func (TaskTag) TableName() string {
+	return "task_tags"
+}
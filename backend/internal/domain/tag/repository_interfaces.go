@@ -0,0 +1,19 @@
+package tag
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// TagRepository defines the interface for tag repository operations
+type TagRepository interface {
+	DB() *gorm.DB
+	List(ctx context.Context, workspaceID *uint64) ([]Tag, error)
+	GetByID(ctx context.Context, tx interface{}, id uint64) (*Tag, error)
+	GetByName(ctx context.Context, tx interface{}, workspaceID uint64, name string) (*Tag, error)
+	Create(ctx context.Context, tx interface{}, t *Tag) error
+	Update(ctx context.Context, tx interface{}, t *Tag) error
+	Delete(ctx context.Context, tx interface{}, id uint64) error
+	Merge(ctx context.Context, tx interface{}, sourceID, targetID uint64) error
+}
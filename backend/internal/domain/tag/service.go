@@ -0,0 +1,200 @@
+package tag
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"todolist/backend/internal/domain/workspace"
+)
+
+type Service struct {
+	repo   TagRepository
+	access Authorizer
+	logger *zap.Logger
+}
+
+// Authorizer checks what the caller may do in a workspace.
+type Authorizer interface {
+	Authorize(ctx context.Context, tx interface{}, workspaceID uint64, role workspace.Role) error
+	DefaultWorkspace(ctx context.Context, tx interface{}) (uint64, error)
+}
+
+func NewService(repo TagRepository, logger *zap.Logger) *Service {
+	return &Service{repo: repo, logger: logger}
+}
+
+// SetAuthorizer enables role checks: every member of a workspace sees its
+// tags, changing them needs the editor role.
+func (s *Service) SetAuthorizer(a Authorizer) {
+	s.access = a
+}
+
+var (
+	ErrTagNotFound    = errors.New("tag not found")
+	ErrTagExists      = errors.New("tag already exists")
+	ErrInvalidName    = errors.New("invalid tag name")
+	ErrMergeIntoSelf  = errors.New("cannot merge tag into itself")
+	ErrCrossWorkspace = errors.New("cannot merge tags across workspaces")
+)
+
+// List returns the tags of every workspace the caller belongs to, or of
+// workspaceID when set.
+func (s *Service) List(ctx context.Context, workspaceID *uint64) ([]Tag, error) {
+	if workspaceID != nil {
+		if err := s.authorize(ctx, nil, *workspaceID, workspace.RoleViewer); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.List(ctx, workspaceID)
+}
+
+func (s *Service) Get(ctx context.Context, id uint64) (*Tag, error) {
+	t, err := s.repo.GetByID(ctx, nil, id)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, ErrTagNotFound
+	}
+	return t, nil
+}
+
+// Create adds a tag to workspaceID, or to the caller's default workspace
+// when nil.
+func (s *Service) Create(ctx context.Context, name string, workspaceID *uint64) (*Tag, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrInvalidName
+	}
+	id, err := s.targetWorkspace(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, nil, id, workspace.RoleEditor); err != nil {
+		return nil, err
+	}
+	existing, err := s.repo.GetByName(ctx, nil, id, name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrTagExists
+	}
+	t := &Tag{WorkspaceID: id, Name: name}
+	if err := s.repo.Create(ctx, nil, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (s *Service) Rename(ctx context.Context, id uint64, name string) (*Tag, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrInvalidName
+	}
+
+	var renamed *Tag
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		existing, err := s.repo.GetByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return ErrTagNotFound
+		}
+		if err := s.authorize(ctx, tx, existing.WorkspaceID, workspace.RoleEditor); err != nil {
+			return err
+		}
+		clash, err := s.repo.GetByName(ctx, tx, existing.WorkspaceID, name)
+		if err != nil {
+			return err
+		}
+		if clash != nil && clash.ID != id {
+			return ErrTagExists
+		}
+		existing.Name = name
+		if err := s.repo.Update(ctx, tx, existing); err != nil {
+			return err
+		}
+		renamed = existing
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return renamed, nil
+}
+
+func (s *Service) Delete(ctx context.Context, id uint64) error {
+	return s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		existing, err := s.repo.GetByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return ErrTagNotFound
+		}
+		if err := s.authorize(ctx, tx, existing.WorkspaceID, workspace.RoleEditor); err != nil {
+			return err
+		}
+		return s.repo.Delete(ctx, tx, id)
+	})
+}
+
+// Merge moves every assignment of sourceID onto targetID and removes the
+// source tag. Both tags must belong to the same workspace.
+func (s *Service) Merge(ctx context.Context, sourceID, targetID uint64) (*Tag, error) {
+	if sourceID == targetID {
+		return nil, ErrMergeIntoSelf
+	}
+
+	var target *Tag
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		source, err := s.repo.GetByID(ctx, tx, sourceID)
+		if err != nil {
+			return err
+		}
+		if source == nil {
+			return ErrTagNotFound
+		}
+		target, err = s.repo.GetByID(ctx, tx, targetID)
+		if err != nil {
+			return err
+		}
+		if target == nil {
+			return ErrTagNotFound
+		}
+		if source.WorkspaceID != target.WorkspaceID {
+			return ErrCrossWorkspace
+		}
+		if err := s.authorize(ctx, tx, source.WorkspaceID, workspace.RoleEditor); err != nil {
+			return err
+		}
+		return s.repo.Merge(ctx, tx, sourceID, targetID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+func (s *Service) targetWorkspace(ctx context.Context, workspaceID *uint64) (uint64, error) {
+	if workspaceID != nil {
+		return *workspaceID, nil
+	}
+	if s.access == nil {
+		return 0, nil
+	}
+	return s.access.DefaultWorkspace(ctx, nil)
+}
+
+func (s *Service) authorize(ctx context.Context, tx interface{}, workspaceID uint64, role workspace.Role) error {
+	if s.access == nil {
+		return nil
+	}
+	return s.access.Authorize(ctx, tx, workspaceID, role)
+}
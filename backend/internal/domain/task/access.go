@@ -0,0 +1,88 @@
+package task
+
+import (
+	"context"
+	"errors"
+
+	"todolist/backend/internal/domain/workspace"
+)
+
+var (
+	ErrMixedWorkspaces = errors.New("tasks belong to different workspaces")
+	ErrCrossWorkspace  = errors.New("cannot link items across workspaces")
+)
+
+// Authorizer checks what the caller may do in a workspace.
+type Authorizer interface {
+	// Authorize fails unless the caller holds at least role in the workspace.
+	Authorize(ctx context.Context, tx interface{}, workspaceID uint64, role workspace.Role) error
+	// DefaultWorkspace returns the workspace new tasks go to when none is named.
+	DefaultWorkspace(ctx context.Context, tx interface{}) (uint64, error)
+	// IsMember reports whether userID belongs to the workspace.
+	IsMember(ctx context.Context, tx interface{}, workspaceID, userID uint64) (bool, error)
+}
+
+// SetAuthorizer enables role checks. Reads are covered by the repository,
+// which only returns tasks from the caller's workspaces, so every member may
+// read; changes need the editor role. Without an authorizer every change is
+// allowed.
+func (s *Service) SetAuthorizer(a Authorizer) {
+	s.access = a
+}
+
+// authorize checks that the caller holds role in workspaceID.
+func (s *Service) authorize(ctx context.Context, tx interface{}, workspaceID uint64, role workspace.Role) error {
+	if s.access == nil {
+		return nil
+	}
+	return s.access.Authorize(ctx, tx, workspaceID, role)
+}
+
+// authorizeTasks checks that tasks all live in one workspace and that the
+// caller holds role in it.
+func (s *Service) authorizeTasks(ctx context.Context, tx interface{}, tasks []Task, role workspace.Role) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	for _, t := range tasks[1:] {
+		if t.WorkspaceID != tasks[0].WorkspaceID {
+			return ErrMixedWorkspaces
+		}
+	}
+	return s.authorize(ctx, tx, tasks[0].WorkspaceID, role)
+}
+
+// targetWorkspace resolves the workspace a new root task goes to and checks
+// that the caller may add tasks there.
+func (s *Service) targetWorkspace(ctx context.Context, tx interface{}, requested *uint64) (uint64, error) {
+	var id uint64
+	if requested != nil {
+		id = *requested
+	} else if s.access != nil {
+		def, err := s.access.DefaultWorkspace(ctx, tx)
+		if err != nil {
+			return 0, err
+		}
+		id = def
+	}
+	if err := s.authorize(ctx, tx, id, workspace.RoleEditor); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// Editable loads a task and checks that the caller may change it. Services
+// that hang data off tasks use it before writing.
+func (s *Service) Editable(ctx context.Context, uuid string) (*Task, error) {
+	t, err := s.repo.GetByUUID(ctx, nil, uuid)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, ErrTaskNotFound
+	}
+	if err := s.authorize(ctx, nil, t.WorkspaceID, workspace.RoleEditor); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
@@ -0,0 +1,140 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"todolist/backend/internal/domain/workspace"
+	"todolist/backend/internal/pkg/auth"
+	"todolist/backend/internal/pkg/events"
+)
+
+var (
+	ErrInvalidAssignee = errors.New("assignee is not a workspace member")
+	ErrNotAssigned     = errors.New("task is not assigned to you")
+)
+
+// BulkAssign assigns the tasks to assigneeID, who must be a member of their
+// workspace. It is recorded as one undoable operation.
+func (s *Service) BulkAssign(ctx context.Context, uuids []string, assigneeID uint64, projectUUID *string) ([]Task, string, error) {
+	return s.bulkAssign(ctx, uuids, &assigneeID, projectUUID)
+}
+
+// BulkUnassign clears the assignee of the tasks.
+func (s *Service) BulkUnassign(ctx context.Context, uuids []string, projectUUID *string) ([]Task, string, error) {
+	return s.bulkAssign(ctx, uuids, nil, projectUUID)
+}
+
+func (s *Service) bulkAssign(ctx context.Context, uuids []string, assigneeID *uint64, projectUUID *string) ([]Task, string, error) {
+	if len(uuids) == 0 {
+		return nil, "", errors.New("empty ids")
+	}
+	if s.tooManyIDs(len(uuids)) {
+		return nil, "", ErrTooManyIDs
+	}
+
+	var tasks []Task
+	var undoToken string
+	var pending []events.Event
+
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		beforeTasks, err := s.repo.GetByUUIDs(ctx, tx, uuids)
+		if err != nil {
+			return err
+		}
+		if len(beforeTasks) != len(uuids) {
+			return ErrTaskNotFound
+		}
+		if err := s.authorizeTasks(ctx, tx, beforeTasks, workspace.RoleEditor); err != nil {
+			return err
+		}
+		if err := checkProjectScope(beforeTasks, projectUUID); err != nil {
+			return err
+		}
+		if assigneeID != nil {
+			if err := s.checkAssignee(ctx, tx, beforeTasks[0].WorkspaceID, *assigneeID); err != nil {
+				return err
+			}
+		}
+		beforeSnaps := orderedSnapshots(beforeTasks, uuids)
+
+		if err := s.repo.Assign(ctx, tx, uuids, assigneeID); err != nil {
+			return err
+		}
+
+		afterTasks, err := s.repo.GetByUUIDs(ctx, tx, uuids)
+		if err != nil {
+			return err
+		}
+		afterSnaps := orderedSnapshots(afterTasks, uuids)
+
+		token, err := s.recordOperation(ctx, tx, &pending, ActionBulkAssign, ScopeBulk, uuids, beforeSnaps, afterSnaps)
+		if err != nil {
+			return err
+		}
+		undoToken = token
+		tasks = orderedTasks(afterTasks, uuids)
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	s.notify(pending)
+	return tasks, undoToken, nil
+}
+
+// ReorderAssigned stores the caller's own order for tasks assigned to them,
+// first to last. It only affects lists filtered by the caller as assignee,
+// leaves the shared sort weight alone and is not undoable.
+func (s *Service) ReorderAssigned(ctx context.Context, ordered []string) error {
+	if len(ordered) == 0 {
+		return errors.New("ordered list empty")
+	}
+	if s.tooManyIDs(len(ordered)) {
+		return ErrTooManyIDs
+	}
+	p, ok := auth.FromContext(ctx)
+	if !ok {
+		return workspace.ErrNotAuthenticated
+	}
+
+	return s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		tasks, err := s.repo.GetByUUIDs(ctx, tx, ordered)
+		if err != nil {
+			return err
+		}
+		if len(tasks) != len(ordered) {
+			return ErrTaskNotFound
+		}
+		for _, t := range tasks {
+			if t.AssigneeID == nil || *t.AssigneeID != p.UserID {
+				return ErrNotAssigned
+			}
+		}
+
+		base := time.Now().UnixNano()
+		orders := make([]AssigneeOrder, 0, len(ordered))
+		for idx, id := range ordered {
+			orders = append(orders, AssigneeOrder{AssigneeID: p.UserID, TaskUUID: id, Position: base + int64(idx)})
+		}
+		return s.repo.SaveAssigneeOrder(ctx, tx, orders)
+	})
+}
+
+// checkAssignee verifies that userID may be assigned tasks of the workspace.
+func (s *Service) checkAssignee(ctx context.Context, tx interface{}, workspaceID, userID uint64) error {
+	if s.access == nil {
+		return nil
+	}
+	ok, err := s.access.IsMember(ctx, tx, workspaceID, userID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidAssignee
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"todolist/backend/internal/domain/workspace"
+	"todolist/backend/internal/pkg/events"
+)
+
+type ChecklistItemPatch struct {
+	Text    *string
+	Checked *bool
+}
+
+var (
+	ErrChecklistItemNotFound = errors.New("checklist item not found")
+	ErrInvalidChecklistText  = errors.New("invalid checklist text")
+)
+
+func (s *Service) AddChecklistItem(ctx context.Context, uuid, text string) (*Task, string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, "", ErrInvalidChecklistText
+	}
+	return s.mutateChecklist(ctx, uuid, func(tx *gorm.DB, t *Task) error {
+		return s.repo.CreateChecklistItem(ctx, tx, &ChecklistItem{
+			TaskUUID: t.UUID,
+			Text:     text,
+			Position: s.defaultWeight(),
+		})
+	})
+}
+
+func (s *Service) UpdateChecklistItem(ctx context.Context, uuid string, itemID uint64, patch ChecklistItemPatch) (*Task, string, error) {
+	if patch.Text != nil && strings.TrimSpace(*patch.Text) == "" {
+		return nil, "", ErrInvalidChecklistText
+	}
+	return s.mutateChecklist(ctx, uuid, func(tx *gorm.DB, t *Task) error {
+		item := findChecklistItem(t, itemID)
+		if item == nil {
+			return ErrChecklistItemNotFound
+		}
+		if patch.Text != nil {
+			item.Text = strings.TrimSpace(*patch.Text)
+		}
+		if patch.Checked != nil {
+			item.Checked = *patch.Checked
+		}
+		return s.repo.UpdateChecklistItem(ctx, tx, item)
+	})
+}
+
+func (s *Service) ToggleChecklistItem(ctx context.Context, uuid string, itemID uint64) (*Task, string, error) {
+	return s.mutateChecklist(ctx, uuid, func(tx *gorm.DB, t *Task) error {
+		item := findChecklistItem(t, itemID)
+		if item == nil {
+			return ErrChecklistItemNotFound
+		}
+		item.Checked = !item.Checked
+		return s.repo.UpdateChecklistItem(ctx, tx, item)
+	})
+}
+
+func (s *Service) ReorderChecklist(ctx context.Context, uuid string, orderedIDs []uint64) (*Task, string, error) {
+	if len(orderedIDs) == 0 {
+		return nil, "", errors.New("ordered list empty")
+	}
+	return s.mutateChecklist(ctx, uuid, func(tx *gorm.DB, t *Task) error {
+		if len(orderedIDs) != len(t.Checklist) {
+			return ErrChecklistItemNotFound
+		}
+		base := s.defaultWeight()
+		for idx, id := range orderedIDs {
+			item := findChecklistItem(t, id)
+			if item == nil {
+				return ErrChecklistItemNotFound
+			}
+			item.Position = base + int64(idx)
+			if err := s.repo.UpdateChecklistItem(ctx, tx, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *Service) DeleteChecklistItem(ctx context.Context, uuid string, itemID uint64) (*Task, string, error) {
+	return s.mutateChecklist(ctx, uuid, func(tx *gorm.DB, t *Task) error {
+		if findChecklistItem(t, itemID) == nil {
+			return ErrChecklistItemNotFound
+		}
+		return s.repo.DeleteChecklistItem(ctx, tx, t.UUID, itemID)
+	})
+}
+
+// ConvertChecklistItem promotes a checklist item to a subtask of its task.
+// The item is removed and the new subtask returned; undo reverts both.
+func (s *Service) ConvertChecklistItem(ctx context.Context, taskUUID string, itemID uint64) (*Task, string, error) {
+	var created *Task
+	var undoToken string
+	var pending []events.Event
+
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		parent, err := s.repo.GetByUUID(ctx, tx, taskUUID)
+		if err != nil {
+			return err
+		}
+		if parent == nil {
+			return ErrTaskNotFound
+		}
+		if err := s.authorize(ctx, tx, parent.WorkspaceID, workspace.RoleEditor); err != nil {
+			return err
+		}
+		item := findChecklistItem(parent, itemID)
+		if item == nil {
+			return ErrChecklistItemNotFound
+		}
+		before := parent.ToSnapshot()
+
+		subtask := &Task{
+			UUID:        uuid.NewString(),
+			OwnerID:     parent.OwnerID,
+			WorkspaceID: parent.WorkspaceID,
+			ParentUUID:  &parent.UUID,
+			ProjectUUID: parent.ProjectUUID,
+			Title:       item.Text,
+			Status:      parent.Status,
+			Priority:    PriorityNone,
+			SortWeight:  s.defaultWeight(),
+		}
+		if item.Checked {
+			subtask.Status = s.workflow.CompletionStatus()
+		}
+		if s.workflow.IsTerminal(subtask.Status) {
+			now := time.Now()
+			subtask.CompletedAt = &now
+		}
+		if err := s.repo.Create(ctx, tx, subtask); err != nil {
+			return err
+		}
+		if err := s.repo.DeleteChecklistItem(ctx, tx, parent.UUID, itemID); err != nil {
+			return err
+		}
+
+		updatedParent, err := s.repo.GetByUUID(ctx, tx, parent.UUID)
+		if err != nil {
+			return err
+		}
+		ids := []string{parent.UUID, subtask.UUID}
+		after := []Snapshot{updatedParent.ToSnapshot(), subtask.ToSnapshot()}
+		token, err := s.recordOperation(ctx, tx, &pending, ActionConvertItem, ScopeBulk, ids, []Snapshot{before}, after)
+		if err != nil {
+			return err
+		}
+		undoToken = token
+		created = subtask
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	s.notify(pending)
+	return created, undoToken, nil
+}
+
+// mutateChecklist applies fn to a task's checklist inside a transaction and
+// records the before/after task snapshots as one undoable operation.
+func (s *Service) mutateChecklist(ctx context.Context, uuid string, fn func(tx *gorm.DB, t *Task) error) (*Task, string, error) {
+	var updated *Task
+	var undoToken string
+	var pending []events.Event
+
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		existing, err := s.repo.GetByUUID(ctx, tx, uuid)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return ErrTaskNotFound
+		}
+		if err := s.authorize(ctx, tx, existing.WorkspaceID, workspace.RoleEditor); err != nil {
+			return err
+		}
+		before := existing.ToSnapshot()
+
+		if err := fn(tx, existing); err != nil {
+			return err
+		}
+
+		updated, err = s.repo.GetByUUID(ctx, tx, uuid)
+		if err != nil {
+			return err
+		}
+		token, err := s.recordOperation(ctx, tx, &pending, ActionChecklist, ScopeSingle, []string{uuid}, []Snapshot{before}, []Snapshot{updated.ToSnapshot()})
+		if err != nil {
+			return err
+		}
+		undoToken = token
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	s.notify(pending)
+	return updated, undoToken, nil
+}
+
+func findChecklistItem(t *Task, id uint64) *ChecklistItem {
+	for i := range t.Checklist {
+		if t.Checklist[i].ID == id {
+			return &t.Checklist[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+
+	"todolist/backend/internal/domain/workspace"
+	"todolist/backend/internal/pkg/auth"
+)
+
+const (
+	// MaxCommentLength bounds a comment body, in characters.
+	MaxCommentLength = 10000
+	// DefaultActor is recorded for callers without an authenticated user.
+	DefaultActor = "anonymous"
+
+	DefaultActivityLimit = 50
+	MaxActivityLimit     = 200
+)
+
+// Activity actions for comment events. Task operations are logged under their
+// undo Action.
+const (
+	ActivityComment        = "comment"
+	ActivityCommentEdited  = "comment_edited"
+	ActivityCommentDeleted = "comment_deleted"
+)
+
+var (
+	ErrCommentNotFound    = errors.New("comment not found")
+	ErrInvalidCommentBody = errors.New("invalid comment body")
+	ErrNotCommentAuthor   = errors.New("only the author can change a comment")
+	ErrCommentEditExpired = errors.New("comment can no longer be edited")
+)
+
+type commentPayload struct {
+	CommentID uint64 `json:"commentId"`
+	Body      string `json:"body,omitempty"`
+}
+
+// ListComments returns the comments of a task, oldest first.
+func (s *Service) ListComments(ctx context.Context, uuid string) ([]Comment, error) {
+	if _, err := s.Get(ctx, uuid, 0); err != nil {
+		return nil, err
+	}
+	return s.repo.ListComments(ctx, nil, uuid)
+}
+
+func (s *Service) AddComment(ctx context.Context, uuid, body string) (*Comment, error) {
+	body, err := normalizeCommentBody(body)
+	if err != nil {
+		return nil, err
+	}
+	comment := &Comment{TaskUUID: uuid, AuthorID: auth.OwnerID(ctx), Author: Actor(ctx), Body: body}
+	err = s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		existing, err := s.repo.GetByUUID(ctx, tx, uuid)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return ErrTaskNotFound
+		}
+		if err := s.authorize(ctx, tx, existing.WorkspaceID, workspace.RoleEditor); err != nil {
+			return err
+		}
+		if err := s.repo.CreateComment(ctx, tx, comment); err != nil {
+			return err
+		}
+		return s.logComment(ctx, tx, ActivityComment, comment, true)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// EditComment replaces the body of a comment. Only its author may edit it,
+// and only within the configured edit window after posting.
+func (s *Service) EditComment(ctx context.Context, uuid string, id uint64, body string) (*Comment, error) {
+	body, err := normalizeCommentBody(body)
+	if err != nil {
+		return nil, err
+	}
+	var comment *Comment
+	err = s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		c, err := s.ownComment(ctx, tx, uuid, id)
+		if err != nil {
+			return err
+		}
+		if window := s.opts.CommentEditWindow; window > 0 && time.Since(c.CreatedAt) > window {
+			return ErrCommentEditExpired
+		}
+		if c.Body == body {
+			comment = c
+			return nil
+		}
+		now := time.Now()
+		c.Body = body
+		c.EditedAt = &now
+		if err := s.repo.UpdateComment(ctx, tx, c); err != nil {
+			return err
+		}
+		comment = c
+		return s.logComment(ctx, tx, ActivityCommentEdited, c, true)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// DeleteComment removes a comment; only its author may do so.
+func (s *Service) DeleteComment(ctx context.Context, uuid string, id uint64) error {
+	return s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		c, err := s.ownComment(ctx, tx, uuid, id)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.DeleteComment(ctx, tx, uuid, id); err != nil {
+			return err
+		}
+		return s.logComment(ctx, tx, ActivityCommentDeleted, c, false)
+	})
+}
+
+// Activity returns the activity feed of a task, newest first. beforeID pages
+// back through older entries.
+func (s *Service) Activity(ctx context.Context, uuid string, limit int, beforeID uint64) ([]ActivityLog, error) {
+	if _, err := s.Get(ctx, uuid, 0); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = DefaultActivityLimit
+	}
+	if limit > MaxActivityLimit {
+		limit = MaxActivityLimit
+	}
+	return s.repo.ListActivity(ctx, nil, uuid, limit, beforeID)
+}
+
+// ownComment loads a comment of a live task and checks that the caller wrote
+// it and may still edit the task.
+func (s *Service) ownComment(ctx context.Context, tx *gorm.DB, uuid string, id uint64) (*Comment, error) {
+	existing, err := s.repo.GetByUUID(ctx, tx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, ErrTaskNotFound
+	}
+	if err := s.authorize(ctx, tx, existing.WorkspaceID, workspace.RoleEditor); err != nil {
+		return nil, err
+	}
+	c, err := s.repo.GetComment(ctx, tx, uuid, id)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, ErrCommentNotFound
+	}
+	if c.AuthorID != auth.OwnerID(ctx) {
+		return nil, ErrNotCommentAuthor
+	}
+	return c, nil
+}
+
+func (s *Service) logComment(ctx context.Context, tx *gorm.DB, action string, c *Comment, withBody bool) error {
+	payload := commentPayload{CommentID: c.ID}
+	if withBody {
+		payload.Body = c.Body
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return s.repo.CreateActivity(ctx, tx, []ActivityLog{{
+		TaskUUID: c.TaskUUID,
+		OwnerID:  auth.OwnerID(ctx),
+		Action:   action,
+		Payload:  string(data),
+		Actor:    c.Author,
+	}})
+}
+
+func normalizeCommentBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" || utf8.RuneCountInString(body) > MaxCommentLength {
+		return "", ErrInvalidCommentBody
+	}
+	return body, nil
+}
+
+// Actor names the caller in ctx in comments and the activity feed.
+func Actor(ctx context.Context) string {
+	p, _ := auth.FromContext(ctx)
+	return actorOrDefault(p.Username)
+}
+
+func actorOrDefault(actor string) string {
+	actor = strings.TrimSpace(actor)
+	if actor == "" {
+		return DefaultActor
+	}
+	if utf8.RuneCountInString(actor) > 64 {
+		actor = string([]rune(actor)[:64])
+	}
+	return actor
+}
@@ -0,0 +1,84 @@
+package task
+
+import (
+	"context"
+	"sort"
+
+	"todolist/backend/internal/domain/customfield"
+)
+
+// resolveCustomValues merges input, keyed by field key, into the current
+// custom values of a task in workspaceID. Only that workspace's fields can be
+// set. A nil input value clears the field; anything else is validated and
+// normalized for the field type. The result is ordered by field ID and carries
+// the field definitions for rendering.
+func (s *Service) resolveCustomValues(ctx context.Context, tx interface{}, workspaceID uint64, taskUUID string, current []customfield.Value, input map[string]any) ([]customfield.Value, error) {
+	fields, err := s.repo.GetCustomFields(ctx, tx, &workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	byKey := make(map[string]*customfield.Field, len(fields))
+	for i := range fields {
+		byKey[fields[i].Key] = &fields[i]
+	}
+	byID := make(map[uint64]customfield.Value, len(current)+len(input))
+	for _, v := range current {
+		byID[v.FieldID] = v
+	}
+	for key, raw := range input {
+		field, ok := byKey[key]
+		if !ok {
+			return nil, customfield.ErrFieldNotFound
+		}
+		if raw == nil {
+			delete(byID, field.ID)
+			continue
+		}
+		value, err := field.Normalize(raw)
+		if err != nil {
+			return nil, err
+		}
+		byID[field.ID] = customfield.Value{TaskUUID: taskUUID, FieldID: field.ID, Value: value, Field: field}
+	}
+
+	values := make([]customfield.Value, 0, len(byID))
+	for _, v := range byID {
+		values = append(values, v)
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].FieldID < values[j].FieldID })
+	return values, nil
+}
+
+// normalizeFieldFilter rewrites the values of a custom field filter into
+// their stored form so that, for example, "1" matches a checkbox set to true.
+// A key may be defined in several of the caller's workspaces, or only in
+// workspaceID when set; the value is normalized for each definition, skipping
+// those it is not valid for.
+func (s *Service) normalizeFieldFilter(ctx context.Context, workspaceID *uint64, filter map[string]string) (map[string][]customfield.Value, error) {
+	if len(filter) == 0 {
+		return nil, nil
+	}
+	fields, err := s.repo.GetCustomFields(ctx, nil, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	normalized := make(map[string][]customfield.Value, len(filter))
+	for key, raw := range filter {
+		var invalid error = customfield.ErrFieldNotFound
+		for i := range fields {
+			if fields[i].Key != key {
+				continue
+			}
+			value, err := fields[i].Normalize(raw)
+			if err != nil {
+				invalid = err
+				continue
+			}
+			normalized[key] = append(normalized[key], customfield.Value{FieldID: fields[i].ID, Value: value})
+		}
+		if len(normalized[key]) == 0 {
+			return nil, invalid
+		}
+	}
+	return normalized, nil
+}
@@ -0,0 +1,219 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"todolist/backend/internal/domain/customfield"
+	"todolist/backend/internal/domain/workspace"
+	"todolist/backend/internal/pkg/auth"
+	"todolist/backend/internal/pkg/events"
+)
+
+// DuplicateInput controls how a task is copied. Status, when set, applies to
+// the copy and every copied subtask; otherwise each copy keeps the status of
+// its original.
+type DuplicateInput struct {
+	IncludeChildren bool
+	ResetDeadline   bool
+	Status          *Status
+	Title           *string
+}
+
+// Duplicate copies a task, optionally with its whole subtree, under fresh
+// UUIDs and owned by the caller. Tags, checklist, custom fields, priority and
+// estimate are copied; dependencies and tracked time are not. The copy is
+// placed right after the original when it stays in the same status, and the
+// whole clone, along with any siblings moved to make room, is one undoable
+// create.
+func (s *Service) Duplicate(ctx context.Context, uuidStr string, input DuplicateInput) (*Task, string, error) {
+	if input.Status != nil && !s.workflow.IsValid(*input.Status) {
+		return nil, "", errors.New("invalid status")
+	}
+	if input.Title != nil && strings.TrimSpace(*input.Title) == "" {
+		return nil, "", ErrInvalidTitle
+	}
+
+	var clone *Task
+	var undoToken string
+	var pending []events.Event
+
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		var sources []Task
+		if input.IncludeChildren {
+			subtree, err := s.collectSubtree(ctx, tx, []string{uuidStr})
+			if err != nil {
+				return err
+			}
+			sources = subtree
+		} else {
+			original, err := s.repo.GetByUUID(ctx, tx, uuidStr)
+			if err != nil {
+				return err
+			}
+			if original != nil {
+				sources = []Task{*original}
+			}
+		}
+		if len(sources) == 0 {
+			return ErrTaskNotFound
+		}
+		original := sources[0]
+		if err := s.authorize(ctx, tx, original.WorkspaceID, workspace.RoleEditor); err != nil {
+			return err
+		}
+		if original.ProjectUUID != nil {
+			if err := s.checkProjectWritable(ctx, tx, *original.ProjectUUID, original.WorkspaceID); err != nil {
+				return err
+			}
+		}
+
+		rootStatus := original.Status
+		if input.Status != nil {
+			rootStatus = *input.Status
+		}
+		rootWeight, before, after, err := s.weightAfter(ctx, tx, &original, rootStatus)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		newUUIDs := make(map[string]string, len(sources))
+		ids := make([]string, 0, len(sources))
+		for i, src := range sources {
+			copyUUID := uuid.NewString()
+			newUUIDs[src.UUID] = copyUUID
+
+			t := &Task{
+				UUID:         copyUUID,
+				OwnerID:      auth.OwnerID(ctx),
+				WorkspaceID:  src.WorkspaceID,
+				AssigneeID:   src.AssigneeID,
+				ParentUUID:   src.ParentUUID,
+				ProjectUUID:  src.ProjectUUID,
+				Title:        src.Title,
+				Notes:        src.Notes,
+				Deadline:     src.Deadline,
+				Status:       src.Status,
+				Priority:     src.Priority,
+				Estimate:     src.Estimate,
+				EstimateUnit: src.EstimateUnit,
+				SortWeight:   src.SortWeight,
+				CompletedAt:  src.CompletedAt,
+				Tags:         src.Tags,
+			}
+			if i == 0 {
+				t.SortWeight = rootWeight
+				if input.Title != nil {
+					t.Title = strings.TrimSpace(*input.Title)
+				}
+			} else {
+				// Subtasks were collected parent-first, so the parent copy exists.
+				parentCopy := newUUIDs[*src.ParentUUID]
+				t.ParentUUID = &parentCopy
+			}
+			if input.Status != nil && t.Status != *input.Status {
+				t.Status = *input.Status
+				t.CompletedAt = nil
+				if s.workflow.IsTerminal(t.Status) {
+					t.CompletedAt = &now
+				}
+			}
+			if input.ResetDeadline {
+				t.Deadline = nil
+			}
+
+			if err := s.repo.Create(ctx, tx, t); err != nil {
+				return err
+			}
+			if err := s.repo.ReplaceTags(ctx, tx, t.UUID, t.TagIDs()); err != nil {
+				return err
+			}
+			for _, item := range src.Checklist {
+				copied := ChecklistItem{TaskUUID: t.UUID, Text: item.Text, Checked: item.Checked, Position: item.Position}
+				if err := s.repo.CreateChecklistItem(ctx, tx, &copied); err != nil {
+					return err
+				}
+				t.Checklist = append(t.Checklist, copied)
+			}
+			for _, v := range src.CustomValues {
+				t.CustomValues = append(t.CustomValues, customfield.Value{TaskUUID: t.UUID, FieldID: v.FieldID, Value: v.Value})
+			}
+			if err := s.repo.ReplaceCustomValues(ctx, tx, t.UUID, t.CustomValues); err != nil {
+				return err
+			}
+
+			ids = append(ids, t.UUID)
+			after = append(after, t.ToSnapshot())
+			if i == 0 {
+				clone = t
+			}
+		}
+
+		scope := ScopeSingle
+		if len(ids) > 1 {
+			scope = ScopeBulk
+		}
+		token, err := s.recordOperation(ctx, tx, &pending, ActionCreate, scope, ids, before, after)
+		if err != nil {
+			return err
+		}
+		undoToken = token
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	s.notify(pending)
+
+	depth := DefaultTreeDepth
+	if input.IncludeChildren {
+		depth = MaxTreeDepth
+	}
+	loaded, err := s.Get(ctx, clone.UUID, depth)
+	if err != nil {
+		return nil, "", err
+	}
+	return loaded, undoToken, nil
+}
+
+// weightAfter returns a sort weight that orders a new sibling of original in
+// status directly after it, making room when the next sibling is adjacent.
+// The siblings moved for that are returned as they were and as they are now,
+// so undo can put them back. A copy landing in another status goes to the
+// default position instead.
+func (s *Service) weightAfter(ctx context.Context, tx *gorm.DB, original *Task, status Status) (int64, []Snapshot, []Snapshot, error) {
+	if status != original.Status {
+		return s.defaultWeight(), nil, nil, nil
+	}
+	next, err := s.repo.NextSortWeight(ctx, tx, original.WorkspaceID, status, original.ParentUUID, original.SortWeight)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	if next == nil {
+		return original.SortWeight + 1, nil, nil, nil
+	}
+	if gap := *next - original.SortWeight; gap >= 2 {
+		return original.SortWeight + gap/2, nil, nil, nil
+	}
+	siblings, err := s.repo.ListSiblingsAfter(ctx, tx, original.WorkspaceID, status, original.ParentUUID, original.SortWeight)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	if err := s.repo.ShiftSortWeights(ctx, tx, original.WorkspaceID, status, original.ParentUUID, original.SortWeight, 1); err != nil {
+		return 0, nil, nil, err
+	}
+	before := make([]Snapshot, 0, len(siblings))
+	after := make([]Snapshot, 0, len(siblings))
+	for _, sibling := range siblings {
+		before = append(before, sibling.ToSnapshot())
+		sibling.SortWeight++
+		after = append(after, sibling.ToSnapshot())
+	}
+	return original.SortWeight + 1, before, after, nil
+}
@@ -0,0 +1,74 @@
+package task
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// siblingRepo holds one list of siblings ordered by sort weight.
+type siblingRepo struct {
+	TaskRepository
+	tasks []Task
+}
+
+func (r *siblingRepo) NextSortWeight(ctx context.Context, tx interface{}, workspaceID uint64, status Status, parentUUID *string, after int64) (*int64, error) {
+	for _, t := range r.tasks {
+		if t.SortWeight > after {
+			w := t.SortWeight
+			return &w, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *siblingRepo) ListSiblingsAfter(ctx context.Context, tx interface{}, workspaceID uint64, status Status, parentUUID *string, after int64) ([]Task, error) {
+	var list []Task
+	for _, t := range r.tasks {
+		if t.SortWeight > after {
+			list = append(list, t)
+		}
+	}
+	return list, nil
+}
+
+func (r *siblingRepo) ShiftSortWeights(ctx context.Context, tx interface{}, workspaceID uint64, status Status, parentUUID *string, after, delta int64) error {
+	for i := range r.tasks {
+		if r.tasks[i].SortWeight > after {
+			r.tasks[i].SortWeight += delta
+		}
+	}
+	return nil
+}
+
+func TestWeightAfterRecordsShiftedSiblings(t *testing.T) {
+	repo := &siblingRepo{tasks: []Task{
+		{UUID: "a", Status: "todo", SortWeight: 10},
+		{UUID: "b", Status: "todo", SortWeight: 11},
+		{UUID: "c", Status: "todo", SortWeight: 20},
+	}}
+	s := NewService(repo, nil, zap.NewNop(), Options{})
+
+	weight, before, after, err := s.weightAfter(context.Background(), nil, &repo.tasks[0], "todo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if weight != 11 {
+		t.Fatalf("weight = %d, want 11", weight)
+	}
+	if len(before) != 2 || len(after) != 2 {
+		t.Fatalf("snapshots before %d, after %d, want 2 each", len(before), len(after))
+	}
+	for i, uuid := range []string{"b", "c"} {
+		if before[i].UUID != uuid || after[i].SortWeight != before[i].SortWeight+1 || after[i].SortWeight != repo.tasks[i+1].SortWeight {
+			t.Fatalf("sibling %s: before %+v, after %+v", uuid, before[i], after[i])
+		}
+	}
+
+	// With room after the original nothing moves.
+	_, before, _, err = s.weightAfter(context.Background(), nil, &repo.tasks[1], "todo")
+	if err != nil || before != nil {
+		t.Fatalf("before = %v, err = %v", before, err)
+	}
+}
@@ -0,0 +1,258 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+type EstimateUnit string
+
+const (
+	EstimateMinutes EstimateUnit = "minutes"
+	EstimatePoints  EstimateUnit = "points"
+)
+
+const (
+	DefaultDailyCapacityMinutes = 480
+	DefaultMinutesPerPoint      = 60
+	DefaultWorkDaysPerWeek      = 5
+)
+
+var ErrInvalidEstimate = errors.New("invalid estimate")
+
+func IsValidEstimateUnit(u EstimateUnit) bool {
+	return u == EstimateMinutes || u == EstimatePoints
+}
+
+// EstimatedMinutes converts the estimate to minutes, valuing a point at
+// minutesPerPoint. ok is false when the task has no estimate.
+func (t *Task) EstimatedMinutes(minutesPerPoint int) (minutes int, ok bool) {
+	if t.Estimate == nil {
+		return 0, false
+	}
+	if t.EstimateUnit == EstimatePoints {
+		return *t.Estimate * minutesPerPoint, true
+	}
+	return *t.Estimate, true
+}
+
+// RemainingMinutes is the estimate minus the time already tracked, never
+// below zero.
+func (t *Task) RemainingMinutes(minutesPerPoint int, now time.Time) (int, bool) {
+	minutes, ok := t.EstimatedMinutes(minutesPerPoint)
+	if !ok {
+		return 0, false
+	}
+	remaining := minutes - int(t.TrackedTime(now)/60)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return remaining, true
+}
+
+type EstimateReportItem struct {
+	Task            Task
+	EstimateMinutes int
+	LeadTimeMinutes int
+	TrackedMinutes  int
+}
+
+type EstimateReport struct {
+	From  time.Time
+	To    time.Time
+	Items []EstimateReportItem
+	// Completed counts every task finished in the range; Items only holds
+	// those carrying an estimate.
+	Completed       int
+	EstimateMinutes int
+	LeadTimeMinutes int
+	TrackedMinutes  int
+}
+
+// EstimateReport compares estimates with the actual lead time (creation to
+// completion) and tracked time of tasks completed within [from, to).
+func (s *Service) EstimateReport(ctx context.Context, from, to time.Time, projectUUID *string) (*EstimateReport, error) {
+	if !to.After(from) {
+		return nil, ErrInvalidTimeRange
+	}
+	tasks, err := s.repo.ListCompleted(ctx, nil, from, to, projectUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	report := &EstimateReport{From: from, To: to, Completed: len(tasks), Items: make([]EstimateReportItem, 0)}
+	for _, t := range tasks {
+		estimate, ok := t.EstimatedMinutes(s.minutesPerPoint())
+		if !ok || t.CompletedAt == nil {
+			continue
+		}
+		item := EstimateReportItem{
+			Task:            t,
+			EstimateMinutes: estimate,
+			LeadTimeMinutes: int(t.CompletedAt.Sub(t.CreatedAt).Minutes()),
+			TrackedMinutes:  int(t.TrackedTime(now) / 60),
+		}
+		report.Items = append(report.Items, item)
+		report.EstimateMinutes += item.EstimateMinutes
+		report.LeadTimeMinutes += item.LeadTimeMinutes
+		report.TrackedMinutes += item.TrackedMinutes
+	}
+	return report, nil
+}
+
+type CapacityDay struct {
+	Date            string
+	CapacityMinutes int
+	DueMinutes      int
+}
+
+type CapacityReport struct {
+	WeekStart             time.Time
+	DailyCapacityMinutes  int
+	WeeklyCapacityMinutes int
+	// PlannedMinutes is the remaining estimate of every active task.
+	PlannedMinutes   int
+	UnestimatedTasks int
+	Days             []CapacityDay
+	Warnings         []string
+}
+
+// Capacity checks the remaining estimates of active tasks against the configured
+// capacity for the working week containing day. Work due on a given day (or
+// already overdue, for the first day) is compared with that day's capacity.
+func (s *Service) Capacity(ctx context.Context, day time.Time, projectUUID *string) (*CapacityReport, error) {
+	tasks, err := s.repo.ListByStatus(ctx, nil, s.workflow.Active, projectUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	daily := s.dailyCapacity()
+	workDays := s.opts.WorkDaysPerWeek
+	if workDays <= 0 || workDays > 7 {
+		workDays = DefaultWorkDaysPerWeek
+	}
+	start := civilDate(day).AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	report := &CapacityReport{
+		WeekStart:             start,
+		DailyCapacityMinutes:  daily,
+		WeeklyCapacityMinutes: daily * workDays,
+		Days:                  make([]CapacityDay, workDays),
+		Warnings:              make([]string, 0),
+	}
+	for i := range report.Days {
+		report.Days[i] = CapacityDay{
+			Date:            start.AddDate(0, 0, i).Format("2006-01-02"),
+			CapacityMinutes: daily,
+		}
+	}
+
+	now := time.Now()
+	for _, t := range tasks {
+		remaining, ok := t.RemainingMinutes(s.minutesPerPoint(), now)
+		if !ok {
+			report.UnestimatedTasks++
+			continue
+		}
+		report.PlannedMinutes += remaining
+		if t.Deadline == nil {
+			continue
+		}
+		offset := int(civilDate(*t.Deadline).Sub(start).Hours() / 24)
+		if offset < 0 {
+			offset = 0
+		}
+		if offset < workDays {
+			report.Days[offset].DueMinutes += remaining
+		}
+	}
+
+	if report.PlannedMinutes > daily {
+		report.Warnings = append(report.Warnings, fmt.Sprintf(
+			"%s tasks need %d minutes, more than the daily capacity of %d", s.workflow.Active, report.PlannedMinutes, daily))
+	}
+	if report.PlannedMinutes > report.WeeklyCapacityMinutes {
+		report.Warnings = append(report.Warnings, fmt.Sprintf(
+			"%s tasks need %d minutes, more than the weekly capacity of %d", s.workflow.Active, report.PlannedMinutes, report.WeeklyCapacityMinutes))
+	}
+	for _, d := range report.Days {
+		if d.DueMinutes > d.CapacityMinutes {
+			report.Warnings = append(report.Warnings, fmt.Sprintf(
+				"%s has %d minutes of work due, more than the daily capacity of %d", d.Date, d.DueMinutes, d.CapacityMinutes))
+		}
+	}
+	return report, nil
+}
+
+// fitToCapacity picks up to limit tasks from the ranked candidates whose
+// estimates fit into budget minutes, keeping the ranking order. Unestimated
+// tasks always fit, and the top candidate is kept even when it alone exceeds
+// the budget so the plan is never empty.
+func (s *Service) fitToCapacity(candidates []Task, budget, limit int) []Task {
+	plan := make([]Task, 0, limit)
+	now := time.Now()
+	for _, t := range candidates {
+		if len(plan) == limit {
+			break
+		}
+		if minutes, ok := t.RemainingMinutes(s.minutesPerPoint(), now); ok {
+			if minutes > budget && len(plan) > 0 {
+				continue
+			}
+			budget -= minutes
+		}
+		plan = append(plan, t)
+	}
+	return plan
+}
+
+// committedMinutes is the remaining estimate of the work already active.
+func (s *Service) committedMinutes(ctx context.Context, projectUUID *string) (int, error) {
+	tasks, err := s.repo.ListByStatus(ctx, nil, s.workflow.Active, projectUUID)
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	total := 0
+	for _, t := range tasks {
+		if minutes, ok := t.RemainingMinutes(s.minutesPerPoint(), now); ok {
+			total += minutes
+		}
+	}
+	return total, nil
+}
+
+func (s *Service) dailyCapacity() int {
+	if s.opts.DailyCapacityMinutes > 0 {
+		return s.opts.DailyCapacityMinutes
+	}
+	return DefaultDailyCapacityMinutes
+}
+
+func (s *Service) minutesPerPoint() int {
+	if s.opts.MinutesPerPoint > 0 {
+		return s.opts.MinutesPerPoint
+	}
+	return DefaultMinutesPerPoint
+}
+
+// sortByEstimate orders candidates with equal urgency so that smaller
+// estimates come first; unestimated tasks keep their place after them.
+func (s *Service) sortByEstimate(tasks []Task) {
+	now := time.Now()
+	sort.SliceStable(tasks, func(i, j int) bool {
+		ui, uj := tasks[i].Urgency(now), tasks[j].Urgency(now)
+		if ui != uj {
+			return ui > uj
+		}
+		mi, oki := tasks[i].EstimatedMinutes(s.minutesPerPoint())
+		mj, okj := tasks[j].EstimatedMinutes(s.minutesPerPoint())
+		if oki && okj {
+			return mi < mj
+		}
+		return oki && !okj
+	})
+}
@@ -0,0 +1,158 @@
+package task
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"todolist/backend/internal/pkg/auth"
+	"todolist/backend/internal/pkg/events"
+)
+
+// Event types published for task changes.
+const (
+	EventCreated   = "task.created"
+	EventUpdated   = "task.updated"
+	EventMoved     = "task.moved"
+	EventDeleted   = "task.deleted"
+	EventReordered = "task.reordered"
+)
+
+// Notifier is told once the events of a change have committed, so they can
+// be relayed without waiting for the next poll.
+type Notifier interface {
+	Notify()
+}
+
+// ChangeData is the payload of task events: the tasks that changed and,
+// except for deletions, their new state. Previous keeps their state before
+// the change, which routing needs, e.g. to reach the column a task left.
+type ChangeData struct {
+	TaskUUIDs []string   `json:"taskUuids"`
+	Tasks     []Snapshot `json:"tasks,omitempty"`
+	Previous  []Snapshot `json:"previous,omitempty"`
+}
+
+// Snapshots returns every state the change touched, before and after.
+func (d ChangeData) Snapshots() []Snapshot {
+	return append(append([]Snapshot(nil), d.Tasks...), d.Previous...)
+}
+
+// EventRecorder stores the events of a change inside its transaction, so
+// they are not lost if the process dies right after the commit.
+type EventRecorder interface {
+	RecordEvents(ctx context.Context, tx *gorm.DB, evs []events.Event) error
+}
+
+// SetNotifier makes the service announce that task changes have committed.
+func (s *Service) SetNotifier(n Notifier) {
+	s.notifier = n
+}
+
+// SetEventRecorder makes every change store its events transactionally.
+func (s *Service) SetEventRecorder(r EventRecorder) {
+	s.recorder = r
+}
+
+// recordOperation records an undoable operation and queues the events it
+// implies on pending. Callers notify pending once tx has committed.
+func (s *Service) recordOperation(ctx context.Context, tx *gorm.DB, pending *[]events.Event, action Action, scope Scope, ids []string, before, after []Snapshot) (string, error) {
+	token, err := s.undoService.RecordOperation(ctx, tx, action, scope, ids, before, after)
+	if err != nil {
+		return "", err
+	}
+	if err := s.queueEvents(ctx, tx, pending, changeEvents(ctx, action, before, after)); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// queueEvents records evs in tx and queues them on pending.
+func (s *Service) queueEvents(ctx context.Context, tx *gorm.DB, pending *[]events.Event, evs []events.Event) error {
+	if s.recorder != nil && len(evs) > 0 {
+		if err := s.recorder.RecordEvents(ctx, tx, evs); err != nil {
+			return err
+		}
+	}
+	*pending = append(*pending, evs...)
+	return nil
+}
+
+func (s *Service) notify(pending []events.Event) {
+	if s.notifier != nil && len(pending) > 0 {
+		s.notifier.Notify()
+	}
+}
+
+// changeEvents describes an operation going from before to after as task
+// events, one per event type and workspace.
+func changeEvents(ctx context.Context, action Action, before, after []Snapshot) []events.Event {
+	if len(after) == 0 {
+		return taskEvents(ctx, EventDeleted, nil, before)
+	}
+
+	previous := make(map[string]Snapshot, len(before))
+	for _, snap := range before {
+		previous[snap.UUID] = snap
+	}
+	var created, changed, changedBefore []Snapshot
+	for _, snap := range after {
+		if prev, ok := previous[snap.UUID]; ok {
+			changed = append(changed, snap)
+			changedBefore = append(changedBefore, prev)
+		} else {
+			created = append(created, snap)
+		}
+	}
+
+	typ := EventUpdated
+	switch action {
+	case ActionMove, ActionComplete, ActionBulkMove, ActionBulkComplete:
+		typ = EventMoved
+	case ActionResort:
+		typ = EventReordered
+	}
+	return append(taskEvents(ctx, EventCreated, created, nil), taskEvents(ctx, typ, changed, changedBefore)...)
+}
+
+// taskEvents builds one event of type typ per workspace the tasks belong to.
+// The event lists the tasks, or the previous ones when no task is left.
+func taskEvents(ctx context.Context, typ string, tasks, previous []Snapshot) []events.Event {
+	byWorkspace := make(map[uint64]*ChangeData)
+	var order []uint64
+	group := func(snap Snapshot) *ChangeData {
+		data, ok := byWorkspace[snap.WorkspaceID]
+		if !ok {
+			data = &ChangeData{}
+			byWorkspace[snap.WorkspaceID] = data
+			order = append(order, snap.WorkspaceID)
+		}
+		return data
+	}
+	for _, snap := range tasks {
+		data := group(snap)
+		data.TaskUUIDs = append(data.TaskUUIDs, snap.UUID)
+		data.Tasks = append(data.Tasks, snap)
+	}
+	for _, snap := range previous {
+		data := group(snap)
+		if len(tasks) == 0 {
+			data.TaskUUIDs = append(data.TaskUUIDs, snap.UUID)
+		}
+		data.Previous = append(data.Previous, snap)
+	}
+
+	now := time.Now()
+	result := make([]events.Event, 0, len(order))
+	for _, workspaceID := range order {
+		result = append(result, events.Event{
+			Type:        typ,
+			WorkspaceID: workspaceID,
+			ActorID:     auth.OwnerID(ctx),
+			At:          now,
+			Data:        *byWorkspace[workspaceID],
+		})
+	}
+	return result
+}
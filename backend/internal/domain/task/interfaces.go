@@ -16,6 +16,13 @@ const (
 	ActionBulkComplete Action = "bulk_complete"
 	ActionBulkDelete   Action = "bulk_delete"
 	ActionResort       Action = "resort"
+	ActionBulkTag      Action = "bulk_tag"
+	ActionBulkUntag    Action = "bulk_untag"
+	ActionMoveProject  Action = "move_project"
+	ActionReparent     Action = "reparent"
+	ActionChecklist    Action = "checklist"
+	ActionConvertItem  Action = "convert_item"
+	ActionBulkAssign   Action = "bulk_assign"
 )
 
 const (
@@ -4,6 +4,9 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+
+	"todolist/backend/internal/domain/customfield"
+	"todolist/backend/internal/domain/tag"
 )
 
 type Status string
@@ -15,45 +18,187 @@ const (
 )
 
 type Task struct {
-	ID          uint64         `gorm:"primaryKey;autoIncrement"`
-	UUID        string         `gorm:"type:char(36);uniqueIndex"`
-	ParentUUID  *string        `gorm:"type:char(36);index"`
-	Children    []Task         `gorm:"foreignKey:ParentUUID;references:UUID"`
-	Title       string         `gorm:"size:255;not null"`
-	Notes       *string        `gorm:"type:text"`
-	Deadline    *time.Time     `gorm:"type:date"`
-	Status      Status         `gorm:"type:enum('now','future','history');not null"`
-	SortWeight  int64          `gorm:"not null"`
-	CreatedAt   time.Time      `gorm:"not null;autoCreateTime"`
-	UpdatedAt   time.Time      `gorm:"not null;autoUpdateTime"`
-	CompletedAt *time.Time     `gorm:"type:datetime"`
-	DeletedAt   gorm.DeletedAt `gorm:"index"`
+	ID           uint64              `gorm:"primaryKey;autoIncrement"`
+	UUID         string              `gorm:"type:char(36);uniqueIndex"`
+	OwnerID      uint64              `gorm:"not null;default:0;index"`
+	WorkspaceID  uint64              `gorm:"not null;default:0;index"`
+	AssigneeID   *uint64             `gorm:"index"`
+	ParentUUID   *string             `gorm:"type:char(36);index"`
+	ProjectUUID  *string             `gorm:"type:char(36);index"`
+	Children     []Task              `gorm:"foreignKey:ParentUUID;references:UUID"`
+	Tags         []tag.Tag           `gorm:"many2many:task_tags;foreignKey:UUID;joinForeignKey:TaskUUID;references:ID;joinReferences:TagID"`
+	BlockedBy    []Dependency        `gorm:"foreignKey:TaskUUID;references:UUID"`
+	Blocks       []Dependency        `gorm:"foreignKey:BlockerUUID;references:UUID"`
+	Checklist    []ChecklistItem     `gorm:"foreignKey:TaskUUID;references:UUID"`
+	CustomValues []customfield.Value `gorm:"foreignKey:TaskUUID;references:UUID"`
+	Title        string              `gorm:"size:255;not null"`
+	Notes        *string             `gorm:"type:text"`
+	Deadline     *time.Time          `gorm:"type:date"`
+	Status       Status              `gorm:"size:32;not null;index"`
+	Priority     Priority            `gorm:"type:enum('none','low','medium','high','urgent');not null;default:'none'"`
+	Estimate     *int                `gorm:"default:null"`
+	EstimateUnit EstimateUnit        `gorm:"type:enum('minutes','points');not null;default:'minutes'"`
+	SortWeight   int64               `gorm:"not null"`
+	CreatedAt    time.Time           `gorm:"not null;autoCreateTime"`
+	UpdatedAt    time.Time           `gorm:"not null;autoUpdateTime"`
+	CompletedAt  *time.Time          `gorm:"type:datetime"`
+	DeletedAt    gorm.DeletedAt      `gorm:"index"`
+
+	// Rollups over direct subtasks, filled in by the repository on read.
+	ChildCount          int64 `gorm:"-"`
+	CompletedChildCount int64 `gorm:"-"`
+
+	// Tracked time, filled in by the repository on read. RunningSince is set
+	// while a timer is open on the task.
+	TrackedSeconds int64      `gorm:"-"`
+	RunningSince   *time.Time `gorm:"-"`
 }
 
 type Snapshot struct {
-	UUID        string     `json:"uuid"`
-	ParentUUID  *string    `json:"parentUuid"`
-	Title       string     `json:"title"`
-	Notes       *string    `json:"notes"`
-	Deadline    *time.Time `json:"deadline"`
-	Status      Status     `json:"status"`
-	SortWeight  int64      `json:"sortWeight"`
-	CreatedAt   time.Time  `json:"createdAt"`
-	UpdatedAt   time.Time  `json:"updatedAt"`
-	CompletedAt *time.Time `json:"completedAt"`
+	UUID         string              `json:"uuid"`
+	OwnerID      uint64              `json:"ownerId"`
+	WorkspaceID  uint64              `json:"workspaceId"`
+	AssigneeID   *uint64             `json:"assigneeId"`
+	ParentUUID   *string             `json:"parentUuid"`
+	ProjectUUID  *string             `json:"projectUuid"`
+	Title        string              `json:"title"`
+	Notes        *string             `json:"notes"`
+	Deadline     *time.Time          `json:"deadline"`
+	Status       Status              `json:"status"`
+	Priority     Priority            `json:"priority"`
+	Estimate     *int                `json:"estimate"`
+	EstimateUnit EstimateUnit        `json:"estimateUnit"`
+	SortWeight   int64               `json:"sortWeight"`
+	CreatedAt    time.Time           `json:"createdAt"`
+	UpdatedAt    time.Time           `json:"updatedAt"`
+	CompletedAt  *time.Time          `json:"completedAt"`
+	TagIDs       []uint64            `json:"tagIds"`
+	Checklist    []ChecklistItem     `json:"checklist"`
+	CustomValues []customfield.Value `json:"customValues"`
 }
 
 type ListFilter struct {
-	Status    *Status
-	Keyword   string
-	Page      int
-	PageSize  int
-	OrderDesc bool
+	// WorkspaceID limits the list to one workspace; nil lists every
+	// workspace the caller belongs to.
+	WorkspaceID *uint64
+	// AssigneeID limits the list to tasks assigned to that user and orders
+	// the manual sort by their own order; Unassigned lists tasks without one.
+	AssigneeID  *uint64
+	Unassigned  bool
+	Status      *Status
+	Priority    *Priority
+	ProjectUUID *string
+	NoProject   bool
+	Keyword     string
+	Tags        []string
+	Blocked     *bool
+	ProgressMin *int
+	ProgressMax *int
+	Page        int
+	PageSize    int
+	OrderDesc   bool
+	Sort        SortKey
+	Depth       int
+	// CustomFields matches tasks whose custom field, by key, has the value.
+	CustomFields map[string]string
+	// CustomValues is CustomFields resolved by the service: for each key, the
+	// stored value to match per field definition carrying that key.
+	CustomValues map[string][]customfield.Value
+}
+
+// ChecklistItem is a lightweight step inside a task, ordered by Position.
+type ChecklistItem struct {
+	ID        uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	TaskUUID  string    `gorm:"type:char(36);index;not null" json:"taskUuid"`
+	Text      string    `gorm:"size:500;not null" json:"text"`
+	Checked   bool      `gorm:"not null;default:false" json:"checked"`
+	Position  int64     `gorm:"not null" json:"position"`
+	CreatedAt time.Time `gorm:"not null;autoCreateTime" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"not null;autoUpdateTime" json:"updatedAt"`
+}
+
+func (ChecklistItem) TableName() string {
+	return "task_checklist_items"
+}
+
+// TimeEntry is a span of work logged against a task. EndedAt is nil while the
+// timer is running; DurationSeconds is filled in once the entry is closed.
+type TimeEntry struct {
+	ID              uint64     `gorm:"primaryKey;autoIncrement"`
+	TaskUUID        string     `gorm:"type:char(36);index;not null"`
+	UserID          uint64     `gorm:"not null;default:0;index"`
+	StartedAt       time.Time  `gorm:"type:datetime;not null;index"`
+	EndedAt         *time.Time `gorm:"type:datetime;index"`
+	DurationSeconds int64      `gorm:"not null;default:0"`
+	Note            *string    `gorm:"size:500"`
+	CreatedAt       time.Time  `gorm:"not null;autoCreateTime"`
+	UpdatedAt       time.Time  `gorm:"not null;autoUpdateTime"`
+}
+
+func (TimeEntry) TableName() string {
+	return "task_time_entries"
+}
+
+// Duration returns the logged time, counting a running entry up to now.
+func (e *TimeEntry) Duration(now time.Time) int64 {
+	if e.EndedAt == nil {
+		return int64(now.Sub(e.StartedAt).Seconds())
+	}
+	return e.DurationSeconds
+}
+
+// Comment is a note left on a task. Comments are keyed by task UUID and are
+// not touched by task deletion, so they come back when a task is restored.
+// AuthorID decides who may change a comment; Author is only the name shown.
+type Comment struct {
+	ID        uint64         `gorm:"primaryKey;autoIncrement"`
+	TaskUUID  string         `gorm:"type:char(36);index;not null"`
+	AuthorID  uint64         `gorm:"not null;default:0;index"`
+	Author    string         `gorm:"size:64;not null"`
+	Body      string         `gorm:"type:text;not null"`
+	EditedAt  *time.Time     `gorm:"type:datetime"`
+	CreatedAt time.Time      `gorm:"not null;autoCreateTime"`
+	UpdatedAt time.Time      `gorm:"not null;autoUpdateTime"`
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (Comment) TableName() string {
+	return "task_comments"
 }
 
+// AssigneeOrder is an assignee's own position for a task in their manual
+// ordering, used instead of the shared SortWeight when a list is filtered by
+// that assignee. Position uses the same scale as SortWeight so tasks the
+// assignee never reordered fall in between.
+type AssigneeOrder struct {
+	AssigneeID uint64    `gorm:"primaryKey"`
+	TaskUUID   string    `gorm:"type:char(36);primaryKey;index"`
+	Position   int64     `gorm:"not null"`
+	UpdatedAt  time.Time `gorm:"not null;autoUpdateTime"`
+}
+
+func (AssigneeOrder) TableName() string {
+	return "task_assignee_orders"
+}
+
+// Dependency records that TaskUUID is blocked by BlockerUUID.
+type Dependency struct {
+	TaskUUID    string    `gorm:"type:char(36);primaryKey"`
+	BlockerUUID string    `gorm:"type:char(36);primaryKey;index"`
+	CreatedAt   time.Time `gorm:"not null;autoCreateTime"`
+}
+
+func (Dependency) TableName() string {
+	return "task_dependencies"
+}
+
+// ActivityLog is one entry of a task's activity feed: every recorded task
+// operation plus comment events. Payload is a JSON object whose shape depends
+// on Action. OwnerID is the user who acted, Actor their name.
 type ActivityLog struct {
 	ID        uint64    `gorm:"primaryKey;autoIncrement"`
 	TaskUUID  string    `gorm:"type:char(36);index"`
+	OwnerID   uint64    `gorm:"not null;default:0;index"`
 	Action    string    `gorm:"size:32;not null"`
 	Payload   string    `gorm:"type:json"`
 	Actor     string    `gorm:"size:64;not null"`
@@ -61,40 +206,96 @@ type ActivityLog struct {
 }
 
 func FromSnapshot(s Snapshot) *Task {
+	priority := s.Priority
+	if priority == "" {
+		priority = PriorityNone
+	}
+	unit := s.EstimateUnit
+	if unit == "" {
+		unit = EstimateMinutes
+	}
 	return &Task{
-		UUID:        s.UUID,
-		ParentUUID:  s.ParentUUID,
-		Title:       s.Title,
-		Notes:       s.Notes,
-		Deadline:    s.Deadline,
-		Status:      s.Status,
-		SortWeight:  s.SortWeight,
-		CreatedAt:   s.CreatedAt,
-		UpdatedAt:   s.UpdatedAt,
-		CompletedAt: s.CompletedAt,
+		UUID:         s.UUID,
+		OwnerID:      s.OwnerID,
+		WorkspaceID:  s.WorkspaceID,
+		AssigneeID:   s.AssigneeID,
+		ParentUUID:   s.ParentUUID,
+		ProjectUUID:  s.ProjectUUID,
+		Title:        s.Title,
+		Notes:        s.Notes,
+		Deadline:     s.Deadline,
+		Status:       s.Status,
+		Priority:     priority,
+		Estimate:     s.Estimate,
+		EstimateUnit: unit,
+		SortWeight:   s.SortWeight,
+		CreatedAt:    s.CreatedAt,
+		UpdatedAt:    s.UpdatedAt,
+		CompletedAt:  s.CompletedAt,
 	}
 }
 
+// TrackedTime returns the total time logged on the task in seconds, including
+// the running timer if there is one.
+func (t *Task) TrackedTime(now time.Time) int64 {
+	total := t.TrackedSeconds
+	if t.RunningSince != nil {
+		total += int64(now.Sub(*t.RunningSince).Seconds())
+	}
+	return total
+}
+
+func (t *Task) TagIDs() []uint64 {
+	ids := make([]uint64, 0, len(t.Tags))
+	for _, tg := range t.Tags {
+		ids = append(ids, tg.ID)
+	}
+	return ids
+}
+
 func (t *Task) ToSnapshot() Snapshot {
+	var checklist []ChecklistItem
+	if t.Checklist != nil {
+		checklist = make([]ChecklistItem, len(t.Checklist))
+		copy(checklist, t.Checklist)
+	}
+	var customValues []customfield.Value
+	if t.CustomValues != nil {
+		customValues = make([]customfield.Value, len(t.CustomValues))
+		for i, v := range t.CustomValues {
+			customValues[i] = customfield.Value{TaskUUID: v.TaskUUID, FieldID: v.FieldID, Value: v.Value}
+		}
+	}
 	return Snapshot{
-		UUID:        t.UUID,
-		ParentUUID:  t.ParentUUID,
-		Title:       t.Title,
-		Notes:       t.Notes,
-		Deadline:    t.Deadline,
-		Status:      t.Status,
-		SortWeight:  t.SortWeight,
-		CreatedAt:   t.CreatedAt,
-		UpdatedAt:   t.UpdatedAt,
-		CompletedAt: t.CompletedAt,
+		UUID:         t.UUID,
+		OwnerID:      t.OwnerID,
+		WorkspaceID:  t.WorkspaceID,
+		AssigneeID:   t.AssigneeID,
+		ParentUUID:   t.ParentUUID,
+		ProjectUUID:  t.ProjectUUID,
+		Title:        t.Title,
+		Notes:        t.Notes,
+		Deadline:     t.Deadline,
+		Status:       t.Status,
+		Priority:     t.Priority,
+		Estimate:     t.Estimate,
+		EstimateUnit: t.EstimateUnit,
+		SortWeight:   t.SortWeight,
+		CreatedAt:    t.CreatedAt,
+		UpdatedAt:    t.UpdatedAt,
+		CompletedAt:  t.CompletedAt,
+		TagIDs:       t.TagIDs(),
+		Checklist:    checklist,
+		CustomValues: customValues,
 	}
 }
 
-func IsValidStatus(status Status) bool {
-	switch status {
-	case StatusNow, StatusFuture, StatusHistory:
-		return true
-	default:
-		return false
+// Progress is the percentage of completed direct subtasks, or nil for tasks
+// without subtasks.
+func (t *Task) Progress() *int {
+	if t.ChildCount == 0 {
+		return nil
 	}
+	pct := int(t.CompletedChildCount * 100 / t.ChildCount)
+	return &pct
 }
@@ -0,0 +1,97 @@
+package task
+
+import "time"
+
+type Priority string
+
+const (
+	PriorityNone   Priority = "none"
+	PriorityLow    Priority = "low"
+	PriorityMedium Priority = "medium"
+	PriorityHigh   Priority = "high"
+	PriorityUrgent Priority = "urgent"
+)
+
+type SortKey string
+
+const (
+	SortDefault  SortKey = ""
+	SortDeadline SortKey = "deadline"
+	SortUrgency  SortKey = "urgency"
+	SortProgress SortKey = "progress"
+	// SortCompleted lists the most recently completed tasks first.
+	SortCompleted SortKey = "completed"
+	// SortManual keeps the drag-and-drop order (sort weight).
+	SortManual SortKey = "manual"
+)
+
+func IsValidPriority(p Priority) bool {
+	switch p {
+	case PriorityNone, PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent:
+		return true
+	default:
+		return false
+	}
+}
+
+func IsValidSortKey(k SortKey) bool {
+	switch k {
+	case SortDefault, SortDeadline, SortUrgency, SortProgress, SortCompleted, SortManual:
+		return true
+	default:
+		return false
+	}
+}
+
+// Urgency combines importance (priority), urgency (deadline proximity) and age
+// into a single Eisenhower-style score; higher means "do it sooner".
+//
+//	priority: none 0, low 2, medium 4, high 6, urgent 8
+//	deadline: overdue 10, otherwise max(0, 8 - days left), none 0
+//	age:      one point per full week since creation, at most 3
+//
+// The repository mirrors this formula in SQL to sort by urgency, so both must
+// be changed together.
+func (t *Task) Urgency(now time.Time) int {
+	score := priorityScore(t.Priority)
+
+	today := civilDate(now)
+	if t.Deadline != nil {
+		days := int(civilDate(*t.Deadline).Sub(today).Hours() / 24)
+		switch {
+		case days < 0:
+			score += 10
+		case days < 8:
+			score += 8 - days
+		}
+	}
+
+	ageWeeks := int(today.Sub(civilDate(t.CreatedAt)).Hours()/24) / 7
+	if ageWeeks > 3 {
+		ageWeeks = 3
+	}
+	if ageWeeks > 0 {
+		score += ageWeeks
+	}
+	return score
+}
+
+func priorityScore(p Priority) int {
+	switch p {
+	case PriorityLow:
+		return 2
+	case PriorityMedium:
+		return 4
+	case PriorityHigh:
+		return 6
+	case PriorityUrgent:
+		return 8
+	default:
+		return 0
+	}
+}
+
+func civilDate(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
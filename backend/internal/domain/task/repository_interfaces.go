@@ -2,8 +2,13 @@ package task
 
 import (
 	"context"
+	"time"
 
 	"gorm.io/gorm"
+
+	"todolist/backend/internal/domain/customfield"
+	"todolist/backend/internal/domain/project"
+	"todolist/backend/internal/domain/tag"
 )
 
 // TaskRepository defines the interface for task repository operations
@@ -16,11 +21,57 @@ type TaskRepository interface {
 	DeleteByUUID(ctx context.Context, tx interface{}, uuid string) error
 	GetByUUID(ctx context.Context, tx interface{}, uuid string) (*Task, error)
 	GetByUUIDs(ctx context.Context, tx interface{}, uuids []string) ([]Task, error)
+	GetByParentUUIDs(ctx context.Context, tx interface{}, parentUUIDs []string) ([]Task, error)
 	List(ctx context.Context, filter ListFilter) ([]Task, int64, error)
+	ListByStatus(ctx context.Context, tx interface{}, status Status, projectUUID *string) ([]Task, error)
+	ListCompleted(ctx context.Context, tx interface{}, from, to time.Time, projectUUID *string) ([]Task, error)
+	NextSortWeight(ctx context.Context, tx interface{}, workspaceID uint64, status Status, parentUUID *string, after int64) (*int64, error)
+	// ListSiblingsAfter returns the siblings in status ordered after after.
+	ListSiblingsAfter(ctx context.Context, tx interface{}, workspaceID uint64, status Status, parentUUID *string, after int64) ([]Task, error)
+	ShiftSortWeights(ctx context.Context, tx interface{}, workspaceID uint64, status Status, parentUUID *string, after, delta int64) error
 	BulkUpdateStatus(ctx context.Context, tx interface{}, uuids []string, status Status, columns map[string]any) error
 	BulkDelete(ctx context.Context, tx interface{}, uuids []string) error
 	ReplaceSnapshots(ctx context.Context, tx interface{}, snapshots []Snapshot) error
 	DeleteBySnapshots(ctx context.Context, tx interface{}, snapshots []Snapshot) error
+	GetTagsByIDs(ctx context.Context, tx interface{}, ids []uint64) ([]tag.Tag, error)
+	ReplaceTags(ctx context.Context, tx interface{}, uuid string, tagIDs []uint64) error
+	AddTags(ctx context.Context, tx interface{}, uuids []string, tagIDs []uint64) error
+	RemoveTags(ctx context.Context, tx interface{}, uuids []string, tagIDs []uint64) error
+	Assign(ctx context.Context, tx interface{}, uuids []string, assigneeID *uint64) error
+	SaveAssigneeOrder(ctx context.Context, tx interface{}, orders []AssigneeOrder) error
+	GetProject(ctx context.Context, tx interface{}, uuid string) (*project.Project, error)
+	AddDependency(ctx context.Context, tx interface{}, taskUUID, blockerUUID string) error
+	RemoveDependency(ctx context.Context, tx interface{}, taskUUID, blockerUUID string) error
+	GetDependencies(ctx context.Context, tx interface{}, taskUUIDs []string) ([]Dependency, error)
+	GetOpenBlockers(ctx context.Context, tx interface{}, taskUUIDs []string) ([]Dependency, error)
+	CreateChecklistItem(ctx context.Context, tx interface{}, item *ChecklistItem) error
+	UpdateChecklistItem(ctx context.Context, tx interface{}, item *ChecklistItem) error
+	DeleteChecklistItem(ctx context.Context, tx interface{}, taskUUID string, id uint64) error
+	ReplaceChecklist(ctx context.Context, tx interface{}, taskUUID string, items []ChecklistItem) error
+	GetCustomFields(ctx context.Context, tx interface{}, workspaceID *uint64) ([]customfield.Field, error)
+	ReplaceCustomValues(ctx context.Context, tx interface{}, taskUUID string, values []customfield.Value) error
+	CreateTimeEntry(ctx context.Context, tx interface{}, entry *TimeEntry) error
+	UpdateTimeEntry(ctx context.Context, tx interface{}, entry *TimeEntry) error
+	DeleteTimeEntry(ctx context.Context, tx interface{}, taskUUID string, id uint64) error
+	GetTimeEntry(ctx context.Context, tx interface{}, taskUUID string, id uint64) (*TimeEntry, error)
+	ListTimeEntries(ctx context.Context, tx interface{}, taskUUID string) ([]TimeEntry, error)
+	ListTimeEntriesBetween(ctx context.Context, tx interface{}, from, to time.Time) ([]TimeEntry, error)
+	GetRunningTimeEntry(ctx context.Context, tx interface{}) (*TimeEntry, error)
+	// ListRunningTimeEntries returns the open timers of all users on the tasks.
+	ListRunningTimeEntries(ctx context.Context, tx interface{}, taskUUIDs []string) ([]TimeEntry, error)
+	// LockTimers serialises timer starts of one user within a transaction.
+	LockTimers(ctx context.Context, tx interface{}, userID uint64) error
+	CreateComment(ctx context.Context, tx interface{}, c *Comment) error
+	UpdateComment(ctx context.Context, tx interface{}, c *Comment) error
+	DeleteComment(ctx context.Context, tx interface{}, taskUUID string, id uint64) error
+	GetComment(ctx context.Context, tx interface{}, taskUUID string, id uint64) (*Comment, error)
+	ListComments(ctx context.Context, tx interface{}, taskUUID string) ([]Comment, error)
+	CreateActivity(ctx context.Context, tx interface{}, logs []ActivityLog) error
+	ListDeleted(ctx context.Context, tx interface{}, limit int) ([]Task, error)
+	GetWithDeleted(ctx context.Context, tx interface{}, uuid string) (*Task, error)
+	GetDeletedByParentUUIDs(ctx context.Context, tx interface{}, parentUUIDs []string) ([]Task, error)
+	Purge(ctx context.Context, tx interface{}, uuids []string) error
+	ListActivity(ctx context.Context, tx interface{}, taskUUID string, limit int, beforeID uint64) ([]ActivityLog, error)
 }
 
 // UndoRepository defines the interface for undo repository operations
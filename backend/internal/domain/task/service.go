@@ -8,20 +8,59 @@ import (
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+
+	"todolist/backend/internal/domain/project"
+	"todolist/backend/internal/domain/tag"
+	"todolist/backend/internal/domain/workspace"
+	"todolist/backend/internal/pkg/auth"
+	"todolist/backend/internal/pkg/events"
 )
 
 type Service struct {
 	repo          TaskRepository
 	undoService   UndoService
 	logger        *zap.Logger
+	opts          Options
+	workflow      *Workflow
 	defaultWeight func() int64
+
+	purgeListeners []PurgeListener
+	access         Authorizer
+	notifier       Notifier
+	recorder       EventRecorder
+}
+
+// Options toggles optional task behaviour.
+type Options struct {
+	// AutoCompleteParent completes a parent once all of its subtasks are done.
+	AutoCompleteParent bool
+	// DailyCapacityMinutes is how much estimated work fits into one day.
+	DailyCapacityMinutes int
+	// MinutesPerPoint converts point estimates into minutes.
+	MinutesPerPoint int
+	// WorkDaysPerWeek is the number of working days used for weekly capacity.
+	WorkDaysPerWeek int
+	// Workflow defines the statuses tasks move through; nil means DefaultWorkflow.
+	Workflow *Workflow
+	// CommentEditWindow is how long after posting a comment may be edited;
+	// zero means forever.
+	CommentEditWindow time.Duration
+	// MaxBulkIDs caps the tasks a single bulk or reorder call may touch;
+	// zero means no cap.
+	MaxBulkIDs int
 }
 
-func NewService(repo TaskRepository, undoSvc UndoService, logger *zap.Logger) *Service {
+func NewService(repo TaskRepository, undoSvc UndoService, logger *zap.Logger, opts Options) *Service {
+	workflow := opts.Workflow
+	if workflow == nil {
+		workflow = DefaultWorkflow()
+	}
 	return &Service{
 		repo:        repo,
 		undoService: undoSvc,
 		logger:      logger,
+		opts:        opts,
+		workflow:    workflow,
 		defaultWeight: func() int64 {
 			return time.Now().UnixNano()
 		},
@@ -29,20 +68,39 @@ func NewService(repo TaskRepository, undoSvc UndoService, logger *zap.Logger) *S
 }
 
 type CreateTaskInput struct {
-	Title      string
-	Notes      *string
-	Deadline   *time.Time
-	Status     Status
-	SortWeight *int64
-	ParentUUID *string
+	Title        string
+	Notes        *string
+	Deadline     *time.Time
+	Status       Status
+	Priority     Priority
+	Estimate     *int
+	EstimateUnit EstimateUnit
+	SortWeight   *int64
+	ParentUUID   *string
+	ProjectUUID  *string
+	// WorkspaceID picks the workspace of a root task; nil means the caller's
+	// default. Subtasks always live in their parent's workspace.
+	WorkspaceID *uint64
+	AssigneeID  *uint64
+	TagIDs      []uint64
+	// CustomFields sets custom field values by field key.
+	CustomFields map[string]any
 }
 
 type UpdatePayload struct {
-	Title       *string
-	Notes       *string
-	NotesSet    bool
-	Deadline    *time.Time
-	DeadlineSet bool
+	Title        *string
+	Notes        *string
+	NotesSet     bool
+	Deadline     *time.Time
+	DeadlineSet  bool
+	Priority     *Priority
+	Estimate     *int
+	EstimateSet  bool
+	EstimateUnit *EstimateUnit
+	TagIDs       []uint64
+	TagsSet      bool
+	// CustomFields changes only the listed fields; a nil value clears one.
+	CustomFields map[string]any
 }
 
 type UpdateStatusInput struct {
@@ -51,12 +109,38 @@ type UpdateStatusInput struct {
 	CompletedTime *time.Time
 }
 
+type DailyPlanInput struct {
+	Limit       int
+	Sort        SortKey
+	ProjectUUID *string
+}
+
 type ListTasksResult struct {
 	Tasks []Task
 	Total int64
 }
 
-var ErrTaskNotFound = errors.New("task not found")
+const (
+	DefaultDailyPlanSize = 5
+	MaxDailyPlanSize     = 50
+	// dailyPlanCandidateFactor widens the candidate pool so tasks skipped for
+	// capacity can be replaced by smaller ones further down the ranking.
+	dailyPlanCandidateFactor = 4
+
+	DefaultTreeDepth = 1
+	MaxTreeDepth     = 10
+)
+
+var (
+	ErrTaskNotFound   = errors.New("task not found")
+	ErrOutOfProject   = errors.New("task does not belong to project")
+	ErrSubtaskProject = errors.New("subtask follows its parent's project")
+	ErrTaskBlocked    = errors.New("task is blocked by unfinished tasks")
+	ErrSelfDependency = errors.New("task cannot block itself")
+	ErrDependencyLoop = errors.New("dependency would create a cycle")
+	ErrParentLoop     = errors.New("parent would create a cycle")
+	ErrTooManyIDs     = errors.New("too many ids")
+)
 
 func (s *Service) List(ctx context.Context, filter ListFilter) (ListTasksResult, error) {
 	// Modify repository to support filtering by ParentUUID IS NULL and preloading Children
@@ -74,14 +158,79 @@ func (s *Service) List(ctx context.Context, filter ListFilter) (ListTasksResult,
 	// Let's update the List method in the repository to handle "root only" if not specified otherwise.
 	// Or better, let's update the ListFilter struct in model.go (which I already did? No, I didn't touch ListFilter).
 
+	if filter.WorkspaceID != nil {
+		if err := s.authorize(ctx, nil, *filter.WorkspaceID, workspace.RoleViewer); err != nil {
+			return ListTasksResult{}, err
+		}
+	}
+	if filter.Status != nil {
+		if !s.workflow.IsValid(*filter.Status) {
+			return ListTasksResult{}, errors.New("invalid status")
+		}
+		if filter.Sort == SortDefault {
+			filter.Sort = s.workflow.DefaultSort(*filter.Status)
+		}
+	}
+	customValues, err := s.normalizeFieldFilter(ctx, filter.WorkspaceID, filter.CustomFields)
+	if err != nil {
+		return ListTasksResult{}, err
+	}
+	filter.CustomValues = customValues
+
 	tasks, total, err := s.repo.List(ctx, filter)
 	if err != nil {
 		return ListTasksResult{}, err
 	}
+	if err := s.loadSubtrees(ctx, nil, tasks, filter.Depth); err != nil {
+		return ListTasksResult{}, err
+	}
 	return ListTasksResult{Tasks: tasks, Total: total}, nil
 }
 
-func (s *Service) Get(ctx context.Context, uuid string) (*Task, error) {
+// DailyPlan suggests backlog tasks to pull into the active status, most urgent first unless
+// another sort key is requested. Among equally urgent tasks smaller estimates
+// rank first, and estimated tasks that no longer fit into what is left of the
+// daily capacity after the current active tasks are skipped.
+func (s *Service) DailyPlan(ctx context.Context, input DailyPlanInput) ([]Task, error) {
+	limit := input.Limit
+	if limit <= 0 {
+		limit = DefaultDailyPlanSize
+	}
+	if limit > MaxDailyPlanSize {
+		limit = MaxDailyPlanSize
+	}
+	sortKey := input.Sort
+	if sortKey == SortDefault {
+		sortKey = SortUrgency
+	}
+	if !IsValidSortKey(sortKey) {
+		return nil, errors.New("invalid sort key")
+	}
+
+	status := s.workflow.Backlog
+	candidates, _, err := s.repo.List(ctx, ListFilter{
+		Status:      &status,
+		ProjectUUID: input.ProjectUUID,
+		Page:        1,
+		PageSize:    limit * dailyPlanCandidateFactor,
+		Sort:        sortKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if sortKey == SortUrgency {
+		s.sortByEstimate(candidates)
+	}
+
+	committed, err := s.committedMinutes(ctx, input.ProjectUUID)
+	if err != nil {
+		return nil, err
+	}
+	return s.fitToCapacity(candidates, s.dailyCapacity()-committed, limit), nil
+}
+
+// Get loads a task with its subtasks down to depth levels (DefaultTreeDepth when depth <= 0).
+func (s *Service) Get(ctx context.Context, uuid string, depth int) (*Task, error) {
 	task, err := s.repo.GetByUUID(ctx, nil, uuid)
 	if err != nil {
 		return nil, err
@@ -89,18 +238,38 @@ func (s *Service) Get(ctx context.Context, uuid string) (*Task, error) {
 	if task == nil {
 		return nil, ErrTaskNotFound
 	}
-	return task, nil
+	roots := []Task{*task}
+	if err := s.loadSubtrees(ctx, nil, roots, depth); err != nil {
+		return nil, err
+	}
+	return &roots[0], nil
 }
 
 func (s *Service) Create(ctx context.Context, input CreateTaskInput) (*Task, string, error) {
 	status := input.Status
 	if status == "" {
-		status = StatusFuture
+		status = s.workflow.Initial
 	}
-	if !IsValidStatus(status) {
+	if !s.workflow.IsValid(status) {
 		return nil, "", errors.New("invalid status")
 	}
+	priority := input.Priority
+	if priority == "" {
+		priority = PriorityNone
+	}
+	if !IsValidPriority(priority) {
+		return nil, "", errors.New("invalid priority")
+	}
+	estimateUnit := input.EstimateUnit
+	if estimateUnit == "" {
+		estimateUnit = EstimateMinutes
+	}
+	if !IsValidEstimateUnit(estimateUnit) || (input.Estimate != nil && *input.Estimate < 0) {
+		return nil, "", ErrInvalidEstimate
+	}
 
+	projectUUID := input.ProjectUUID
+	var workspaceID uint64
 	if input.ParentUUID != nil {
 		// Verify parent exists
 		parent, err := s.repo.GetByUUID(ctx, nil, *input.ParentUUID)
@@ -110,6 +279,42 @@ func (s *Service) Create(ctx context.Context, input CreateTaskInput) (*Task, str
 		if parent == nil {
 			return nil, "", errors.New("parent task not found")
 		}
+		if input.WorkspaceID != nil && *input.WorkspaceID != parent.WorkspaceID {
+			return nil, "", ErrCrossWorkspace
+		}
+		if err := s.authorize(ctx, nil, parent.WorkspaceID, workspace.RoleEditor); err != nil {
+			return nil, "", err
+		}
+		// Subtasks always live in their parent's project
+		projectUUID = parent.ProjectUUID
+		workspaceID = parent.WorkspaceID
+	} else {
+		id, err := s.targetWorkspace(ctx, nil, input.WorkspaceID)
+		if err != nil {
+			return nil, "", err
+		}
+		workspaceID = id
+		if projectUUID != nil {
+			if err := s.checkProjectWritable(ctx, nil, *projectUUID, workspaceID); err != nil {
+				return nil, "", err
+			}
+		}
+	}
+	if input.AssigneeID != nil {
+		if err := s.checkAssignee(ctx, nil, workspaceID, *input.AssigneeID); err != nil {
+			return nil, "", err
+		}
+	}
+
+	tags, err := s.resolveTags(ctx, nil, workspaceID, input.TagIDs)
+	if err != nil {
+		return nil, "", err
+	}
+
+	taskUUID := uuid.NewString()
+	customValues, err := s.resolveCustomValues(ctx, nil, workspaceID, taskUUID, nil, input.CustomFields)
+	if err != nil {
+		return nil, "", err
 	}
 
 	sortWeight := s.defaultWeight()
@@ -118,26 +323,42 @@ func (s *Service) Create(ctx context.Context, input CreateTaskInput) (*Task, str
 	}
 
 	taskModel := &Task{
-		UUID:       uuid.NewString(),
-		ParentUUID: input.ParentUUID,
-		Title:      input.Title,
-		Notes:      input.Notes,
-		Deadline:   input.Deadline,
-		Status:     status,
-		SortWeight: sortWeight,
-	}
-	if status == StatusHistory {
+		UUID:         taskUUID,
+		OwnerID:      auth.OwnerID(ctx),
+		WorkspaceID:  workspaceID,
+		AssigneeID:   input.AssigneeID,
+		ParentUUID:   input.ParentUUID,
+		ProjectUUID:  projectUUID,
+		Title:        input.Title,
+		Notes:        input.Notes,
+		Deadline:     input.Deadline,
+		Status:       status,
+		Priority:     priority,
+		Estimate:     input.Estimate,
+		EstimateUnit: estimateUnit,
+		SortWeight:   sortWeight,
+		Tags:         tags,
+		CustomValues: customValues,
+	}
+	if s.workflow.IsTerminal(status) {
 		now := time.Now()
 		taskModel.CompletedAt = &now
 	}
 
 	var undoToken string
-	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+	var pending []events.Event
+	err = s.repo.DB().Transaction(func(tx *gorm.DB) error {
 		if err := s.repo.Create(ctx, tx, taskModel); err != nil {
 			return err
 		}
+		if err := s.repo.ReplaceTags(ctx, tx, taskModel.UUID, taskModel.TagIDs()); err != nil {
+			return err
+		}
+		if err := s.repo.ReplaceCustomValues(ctx, tx, taskModel.UUID, taskModel.CustomValues); err != nil {
+			return err
+		}
 		after := []Snapshot{taskModel.ToSnapshot()}
-		token, err := s.undoService.RecordOperation(ctx, tx, ActionCreate, ScopeSingle, []string{taskModel.UUID}, nil, after)
+		token, err := s.recordOperation(ctx, tx, &pending, ActionCreate, ScopeSingle, []string{taskModel.UUID}, nil, after)
 		if err != nil {
 			return err
 		}
@@ -147,12 +368,14 @@ func (s *Service) Create(ctx context.Context, input CreateTaskInput) (*Task, str
 	if err != nil {
 		return nil, "", err
 	}
+	s.notify(pending)
 	return taskModel, undoToken, nil
 }
 
 func (s *Service) Update(ctx context.Context, uuid string, payload UpdatePayload) (*Task, string, error) {
 	var beforeSnap Snapshot
 	var undoToken string
+	var pending []events.Event
 	var updatedTask *Task
 
 	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
@@ -163,6 +386,9 @@ func (s *Service) Update(ctx context.Context, uuid string, payload UpdatePayload
 		if existing == nil {
 			return ErrTaskNotFound
 		}
+		if err := s.authorize(ctx, tx, existing.WorkspaceID, workspace.RoleEditor); err != nil {
+			return err
+		}
 
 		beforeSnap = existing.ToSnapshot()
 
@@ -175,13 +401,53 @@ func (s *Service) Update(ctx context.Context, uuid string, payload UpdatePayload
 		if payload.DeadlineSet {
 			existing.Deadline = payload.Deadline
 		}
+		if payload.Priority != nil {
+			if !IsValidPriority(*payload.Priority) {
+				return errors.New("invalid priority")
+			}
+			existing.Priority = *payload.Priority
+		}
+		if payload.EstimateSet {
+			if payload.Estimate != nil && *payload.Estimate < 0 {
+				return ErrInvalidEstimate
+			}
+			existing.Estimate = payload.Estimate
+		}
+		if payload.EstimateUnit != nil {
+			if !IsValidEstimateUnit(*payload.EstimateUnit) {
+				return ErrInvalidEstimate
+			}
+			existing.EstimateUnit = *payload.EstimateUnit
+		}
 
 		if err := s.repo.Update(ctx, tx, existing); err != nil {
 			return err
 		}
 
+		if payload.TagsSet {
+			tags, err := s.resolveTags(ctx, tx, existing.WorkspaceID, payload.TagIDs)
+			if err != nil {
+				return err
+			}
+			existing.Tags = tags
+			if err := s.repo.ReplaceTags(ctx, tx, existing.UUID, existing.TagIDs()); err != nil {
+				return err
+			}
+		}
+
+		if len(payload.CustomFields) > 0 {
+			values, err := s.resolveCustomValues(ctx, tx, existing.WorkspaceID, existing.UUID, existing.CustomValues, payload.CustomFields)
+			if err != nil {
+				return err
+			}
+			existing.CustomValues = values
+			if err := s.repo.ReplaceCustomValues(ctx, tx, existing.UUID, existing.CustomValues); err != nil {
+				return err
+			}
+		}
+
 		after := existing.ToSnapshot()
-		token, err := s.undoService.RecordOperation(ctx, tx, ActionUpdate, ScopeSingle, []string{existing.UUID}, []Snapshot{beforeSnap}, []Snapshot{after})
+		token, err := s.recordOperation(ctx, tx, &pending, ActionUpdate, ScopeSingle, []string{existing.UUID}, []Snapshot{beforeSnap}, []Snapshot{after})
 		if err != nil {
 			return err
 		}
@@ -192,16 +458,18 @@ func (s *Service) Update(ctx context.Context, uuid string, payload UpdatePayload
 	if err != nil {
 		return nil, "", err
 	}
+	s.notify(pending)
 	return updatedTask, undoToken, nil
 }
 
 func (s *Service) UpdateStatus(ctx context.Context, uuid string, input UpdateStatusInput) (*Task, string, error) {
-	if !IsValidStatus(input.Status) {
+	if !s.workflow.IsValid(input.Status) {
 		return nil, "", errors.New("invalid status")
 	}
 
 	var updated *Task
 	var undoToken string
+	var pending []events.Event
 
 	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
 		existing, err := s.repo.GetByUUID(ctx, tx, uuid)
@@ -211,9 +479,21 @@ func (s *Service) UpdateStatus(ctx context.Context, uuid string, input UpdateSta
 		if existing == nil {
 			return ErrTaskNotFound
 		}
+		if err := s.authorize(ctx, tx, existing.WorkspaceID, workspace.RoleEditor); err != nil {
+			return err
+		}
 
 		before := existing.ToSnapshot()
 
+		if existing.Status != input.Status {
+			if !s.workflow.CanTransition(existing.Status, input.Status) {
+				return ErrInvalidTransition
+			}
+			if err := s.checkUnblocked(ctx, tx, []string{existing.UUID}, input.Status); err != nil {
+				return err
+			}
+		}
+
 		existing.Status = input.Status
 		if input.SortWeight != nil {
 			existing.SortWeight = *input.SortWeight
@@ -221,13 +501,13 @@ func (s *Service) UpdateStatus(ctx context.Context, uuid string, input UpdateSta
 			existing.SortWeight = s.defaultWeight()
 		}
 		action := ActionMove
-		if input.Status == StatusHistory {
+		if s.workflow.IsTerminal(input.Status) {
 			now := time.Now()
 			if input.CompletedTime != nil {
 				now = *input.CompletedTime
 			}
 			existing.CompletedAt = &now
-			if before.Status != StatusHistory {
+			if !s.workflow.IsTerminal(before.Status) {
 				action = ActionComplete
 			}
 		} else {
@@ -238,8 +518,29 @@ func (s *Service) UpdateStatus(ctx context.Context, uuid string, input UpdateSta
 			return err
 		}
 
-		after := existing.ToSnapshot()
-		token, err := s.undoService.RecordOperation(ctx, tx, action, ScopeSingle, []string{existing.UUID}, []Snapshot{before}, []Snapshot{after})
+		ids := []string{existing.UUID}
+		beforeSnaps := []Snapshot{before}
+		afterSnaps := []Snapshot{existing.ToSnapshot()}
+		scope := ScopeSingle
+		if action == ActionComplete {
+			if err := s.stopTimers(ctx, tx, ids, *existing.CompletedAt); err != nil {
+				return err
+			}
+			parentsBefore, parentsAfter, err := s.autoCompleteParents(ctx, tx, []*string{existing.ParentUUID}, *existing.CompletedAt)
+			if err != nil {
+				return err
+			}
+			for _, snap := range parentsBefore {
+				ids = append(ids, snap.UUID)
+			}
+			beforeSnaps = append(beforeSnaps, parentsBefore...)
+			afterSnaps = append(afterSnaps, parentsAfter...)
+			if len(ids) > 1 {
+				scope = ScopeBulk
+			}
+		}
+
+		token, err := s.recordOperation(ctx, tx, &pending, action, scope, ids, beforeSnaps, afterSnaps)
 		if err != nil {
 			return err
 		}
@@ -250,32 +551,51 @@ func (s *Service) UpdateStatus(ctx context.Context, uuid string, input UpdateSta
 	if err != nil {
 		return nil, "", err
 	}
+	s.notify(pending)
 	return updated, undoToken, nil
 }
 
 func (s *Service) Complete(ctx context.Context, uuid string, completedAt *time.Time) (*Task, string, error) {
-	return s.UpdateStatus(ctx, uuid, UpdateStatusInput{Status: StatusHistory, CompletedTime: completedAt})
+	return s.UpdateStatus(ctx, uuid, UpdateStatusInput{Status: s.workflow.CompletionStatus(), CompletedTime: completedAt})
 }
 
+// Delete removes a task together with all of its subtasks; undo restores the
+// whole subtree.
 func (s *Service) Delete(ctx context.Context, uuid string) (string, error) {
 	var undoToken string
+	var pending []events.Event
 
 	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
-		existing, err := s.repo.GetByUUID(ctx, tx, uuid)
+		subtree, err := s.collectSubtree(ctx, tx, []string{uuid})
 		if err != nil {
 			return err
 		}
-		if existing == nil {
+		if len(subtree) == 0 {
 			return ErrTaskNotFound
 		}
+		if err := s.authorizeTasks(ctx, tx, subtree[:1], workspace.RoleEditor); err != nil {
+			return err
+		}
 
-		before := existing.ToSnapshot()
+		ids := make([]string, 0, len(subtree))
+		before := make([]Snapshot, 0, len(subtree))
+		for _, t := range subtree {
+			ids = append(ids, t.UUID)
+			before = append(before, t.ToSnapshot())
+		}
 
-		if err := s.repo.DeleteByUUID(ctx, tx, uuid); err != nil {
+		if err := s.repo.BulkDelete(ctx, tx, ids); err != nil {
+			return err
+		}
+		if err := s.stopTimers(ctx, tx, ids, time.Now()); err != nil {
 			return err
 		}
 
-		token, err := s.undoService.RecordOperation(ctx, tx, ActionDelete, ScopeSingle, []string{uuid}, []Snapshot{before}, nil)
+		scope := ScopeSingle
+		if len(ids) > 1 {
+			scope = ScopeBulk
+		}
+		token, err := s.recordOperation(ctx, tx, &pending, ActionDelete, scope, ids, before, nil)
 		if err != nil {
 			return err
 		}
@@ -285,19 +605,30 @@ func (s *Service) Delete(ctx context.Context, uuid string) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	s.notify(pending)
 	return undoToken, nil
 }
 
-func (s *Service) BulkMove(ctx context.Context, uuids []string, status Status) ([]Task, string, error) {
+// tooManyIDs reports whether n tasks exceed the configured bulk cap.
+func (s *Service) tooManyIDs(n int) bool {
+	return s.opts.MaxBulkIDs > 0 && n > s.opts.MaxBulkIDs
+}
+
+func (s *Service) BulkMove(ctx context.Context, uuids []string, status Status, projectUUID *string) ([]Task, string, error) {
 	if len(uuids) == 0 {
 		return nil, "", errors.New("empty ids")
 	}
-	if !IsValidStatus(status) {
+	if s.tooManyIDs(len(uuids)) {
+		return nil, "", ErrTooManyIDs
+	}
+	if !s.workflow.IsValid(status) {
 		return nil, "", errors.New("invalid status")
 	}
+	terminal := s.workflow.IsTerminal(status)
 
 	var tasks []Task
 	var undoToken string
+	var pending []events.Event
 
 	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
 		beforeTasks, err := s.repo.GetByUUIDs(ctx, tx, uuids)
@@ -307,11 +638,29 @@ func (s *Service) BulkMove(ctx context.Context, uuids []string, status Status) (
 		if len(beforeTasks) == 0 {
 			return ErrTaskNotFound
 		}
+		if err := s.authorizeTasks(ctx, tx, beforeTasks, workspace.RoleEditor); err != nil {
+			return err
+		}
+		if err := checkProjectScope(beforeTasks, projectUUID); err != nil {
+			return err
+		}
+		moving := make([]string, 0, len(beforeTasks))
+		for _, t := range beforeTasks {
+			if t.Status != status {
+				if !s.workflow.CanTransition(t.Status, status) {
+					return ErrInvalidTransition
+				}
+				moving = append(moving, t.UUID)
+			}
+		}
+		if err := s.checkUnblocked(ctx, tx, moving, status); err != nil {
+			return err
+		}
 
 		beforeSnaps := make([]Snapshot, 0, len(beforeTasks))
 		now := time.Now()
 		action := ActionBulkMove
-		if status == StatusHistory {
+		if terminal {
 			action = ActionBulkComplete
 		}
 
@@ -331,7 +680,7 @@ func (s *Service) BulkMove(ctx context.Context, uuids []string, status Status) (
 				"status":      status,
 				"sort_weight": baseWeight + int64(idx),
 			}
-			if status == StatusHistory {
+			if terminal {
 				updates["completed_at"] = now
 			} else {
 				updates["completed_at"] = nil
@@ -367,7 +716,28 @@ func (s *Service) BulkMove(ctx context.Context, uuids []string, status Status) (
 			return ErrTaskNotFound
 		}
 
-		token, err := s.undoService.RecordOperation(ctx, tx, action, ScopeBulk, uuids, beforeSnaps, afterSnaps)
+		recordIDs := uuids
+		if terminal {
+			if err := s.stopTimers(ctx, tx, uuids, now); err != nil {
+				return err
+			}
+			parents := make([]*string, 0, len(afterTasks))
+			for _, t := range afterTasks {
+				parents = append(parents, t.ParentUUID)
+			}
+			parentsBefore, parentsAfter, err := s.autoCompleteParents(ctx, tx, parents, now)
+			if err != nil {
+				return err
+			}
+			recordIDs = append([]string{}, uuids...)
+			for _, snap := range parentsBefore {
+				recordIDs = append(recordIDs, snap.UUID)
+			}
+			beforeSnaps = append(beforeSnaps, parentsBefore...)
+			afterSnaps = append(afterSnaps, parentsAfter...)
+		}
+
+		token, err := s.recordOperation(ctx, tx, &pending, action, ScopeBulk, recordIDs, beforeSnaps, afterSnaps)
 		if err != nil {
 			return err
 		}
@@ -378,14 +748,19 @@ func (s *Service) BulkMove(ctx context.Context, uuids []string, status Status) (
 	if err != nil {
 		return nil, "", err
 	}
+	s.notify(pending)
 	return tasks, undoToken, nil
 }
 
-func (s *Service) BulkDelete(ctx context.Context, uuids []string) (string, error) {
+func (s *Service) BulkDelete(ctx context.Context, uuids []string, projectUUID *string) (string, error) {
 	if len(uuids) == 0 {
 		return "", errors.New("empty ids")
 	}
+	if s.tooManyIDs(len(uuids)) {
+		return "", ErrTooManyIDs
+	}
 	var undoToken string
+	var pending []events.Event
 
 	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
 		beforeTasks, err := s.repo.GetByUUIDs(ctx, tx, uuids)
@@ -395,17 +770,32 @@ func (s *Service) BulkDelete(ctx context.Context, uuids []string) (string, error
 		if len(beforeTasks) == 0 {
 			return ErrTaskNotFound
 		}
+		if err := s.authorizeTasks(ctx, tx, beforeTasks, workspace.RoleEditor); err != nil {
+			return err
+		}
+		if err := checkProjectScope(beforeTasks, projectUUID); err != nil {
+			return err
+		}
 
-		beforeSnaps := make([]Snapshot, 0, len(beforeTasks))
-		for _, t := range beforeTasks {
+		subtree, err := s.collectSubtree(ctx, tx, uuids)
+		if err != nil {
+			return err
+		}
+		ids := make([]string, 0, len(subtree))
+		beforeSnaps := make([]Snapshot, 0, len(subtree))
+		for _, t := range subtree {
+			ids = append(ids, t.UUID)
 			beforeSnaps = append(beforeSnaps, t.ToSnapshot())
 		}
 
-		if err := s.repo.BulkDelete(ctx, tx, uuids); err != nil {
+		if err := s.repo.BulkDelete(ctx, tx, ids); err != nil {
+			return err
+		}
+		if err := s.stopTimers(ctx, tx, ids, time.Now()); err != nil {
 			return err
 		}
 
-		token, err := s.undoService.RecordOperation(ctx, tx, ActionBulkDelete, ScopeBulk, uuids, beforeSnaps, nil)
+		token, err := s.recordOperation(ctx, tx, &pending, ActionBulkDelete, ScopeBulk, ids, beforeSnaps, nil)
 		if err != nil {
 			return err
 		}
@@ -415,18 +805,23 @@ func (s *Service) BulkDelete(ctx context.Context, uuids []string) (string, error
 	if err != nil {
 		return "", err
 	}
+	s.notify(pending)
 	return undoToken, nil
 }
 
-func (s *Service) UpdateOrder(ctx context.Context, status Status, ordered []string) (string, error) {
-	if !IsValidStatus(status) {
+func (s *Service) UpdateOrder(ctx context.Context, status Status, ordered []string, projectUUID *string) (string, error) {
+	if !s.workflow.IsValid(status) {
 		return "", errors.New("invalid status")
 	}
 	if len(ordered) == 0 {
 		return "", errors.New("ordered list empty")
 	}
+	if s.tooManyIDs(len(ordered)) {
+		return "", ErrTooManyIDs
+	}
 
 	var undoToken string
+	var pending []events.Event
 
 	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
 		tasks, err := s.repo.GetByUUIDs(ctx, tx, ordered)
@@ -436,6 +831,12 @@ func (s *Service) UpdateOrder(ctx context.Context, status Status, ordered []stri
 		if len(tasks) == 0 {
 			return ErrTaskNotFound
 		}
+		if err := s.authorizeTasks(ctx, tx, tasks, workspace.RoleEditor); err != nil {
+			return err
+		}
+		if err := checkProjectScope(tasks, projectUUID); err != nil {
+			return err
+		}
 
 		beforeMap := make(map[string]Snapshot, len(tasks))
 		for _, t := range tasks {
@@ -477,7 +878,7 @@ func (s *Service) UpdateOrder(ctx context.Context, status Status, ordered []stri
 			return ErrTaskNotFound
 		}
 
-		token, err := s.undoService.RecordOperation(ctx, tx, ActionResort, ScopeBulk, ordered, before, after)
+		token, err := s.recordOperation(ctx, tx, &pending, ActionResort, ScopeBulk, ordered, before, after)
 		if err != nil {
 			return err
 		}
@@ -487,5 +888,597 @@ func (s *Service) UpdateOrder(ctx context.Context, status Status, ordered []stri
 	if err != nil {
 		return "", err
 	}
+	s.notify(pending)
 	return undoToken, nil
 }
+
+func (s *Service) BulkTag(ctx context.Context, uuids []string, tagIDs []uint64, projectUUID *string) ([]Task, string, error) {
+	return s.bulkTags(ctx, uuids, tagIDs, projectUUID, ActionBulkTag)
+}
+
+func (s *Service) BulkUntag(ctx context.Context, uuids []string, tagIDs []uint64, projectUUID *string) ([]Task, string, error) {
+	return s.bulkTags(ctx, uuids, tagIDs, projectUUID, ActionBulkUntag)
+}
+
+func (s *Service) bulkTags(ctx context.Context, uuids []string, tagIDs []uint64, projectUUID *string, action Action) ([]Task, string, error) {
+	if len(uuids) == 0 {
+		return nil, "", errors.New("empty ids")
+	}
+	if s.tooManyIDs(len(uuids)) {
+		return nil, "", ErrTooManyIDs
+	}
+	if len(tagIDs) == 0 {
+		return nil, "", errors.New("empty tag ids")
+	}
+
+	var tasks []Task
+	var undoToken string
+	var pending []events.Event
+
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		beforeTasks, err := s.repo.GetByUUIDs(ctx, tx, uuids)
+		if err != nil {
+			return err
+		}
+		if len(beforeTasks) != len(uuids) {
+			return ErrTaskNotFound
+		}
+		if err := s.authorizeTasks(ctx, tx, beforeTasks, workspace.RoleEditor); err != nil {
+			return err
+		}
+		if err := checkProjectScope(beforeTasks, projectUUID); err != nil {
+			return err
+		}
+		for _, t := range beforeTasks[1:] {
+			if t.WorkspaceID != beforeTasks[0].WorkspaceID {
+				return ErrCrossWorkspace
+			}
+		}
+
+		tags, err := s.resolveTags(ctx, tx, beforeTasks[0].WorkspaceID, tagIDs)
+		if err != nil {
+			return err
+		}
+		ids := make([]uint64, 0, len(tags))
+		for _, t := range tags {
+			ids = append(ids, t.ID)
+		}
+		beforeSnaps := orderedSnapshots(beforeTasks, uuids)
+
+		if action == ActionBulkTag {
+			err = s.repo.AddTags(ctx, tx, uuids, ids)
+		} else {
+			err = s.repo.RemoveTags(ctx, tx, uuids, ids)
+		}
+		if err != nil {
+			return err
+		}
+
+		afterTasks, err := s.repo.GetByUUIDs(ctx, tx, uuids)
+		if err != nil {
+			return err
+		}
+		afterSnaps := orderedSnapshots(afterTasks, uuids)
+
+		token, err := s.recordOperation(ctx, tx, &pending, action, ScopeBulk, uuids, beforeSnaps, afterSnaps)
+		if err != nil {
+			return err
+		}
+		undoToken = token
+		tasks = orderedTasks(afterTasks, uuids)
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	s.notify(pending)
+	return tasks, undoToken, nil
+}
+
+// AddDependency records that uuid is blocked by blockerUUID, rejecting edges
+// that would close a cycle.
+func (s *Service) AddDependency(ctx context.Context, uuid, blockerUUID string) (*Task, error) {
+	if uuid == blockerUUID {
+		return nil, ErrSelfDependency
+	}
+
+	var updated *Task
+	var pending []events.Event
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		tasks, err := s.repo.GetByUUIDs(ctx, tx, []string{uuid, blockerUUID})
+		if err != nil {
+			return err
+		}
+		if len(tasks) != 2 {
+			return ErrTaskNotFound
+		}
+		if tasks[0].WorkspaceID != tasks[1].WorkspaceID {
+			return ErrCrossWorkspace
+		}
+		if err := s.authorize(ctx, tx, tasks[0].WorkspaceID, workspace.RoleEditor); err != nil {
+			return err
+		}
+
+		// The new edge closes a cycle if uuid already (transitively) blocks blockerUUID.
+		seen := map[string]struct{}{blockerUUID: {}}
+		frontier := []string{blockerUUID}
+		for len(frontier) > 0 {
+			deps, err := s.repo.GetDependencies(ctx, tx, frontier)
+			if err != nil {
+				return err
+			}
+			frontier = frontier[:0]
+			for _, d := range deps {
+				if d.BlockerUUID == uuid {
+					return ErrDependencyLoop
+				}
+				if _, ok := seen[d.BlockerUUID]; ok {
+					continue
+				}
+				seen[d.BlockerUUID] = struct{}{}
+				frontier = append(frontier, d.BlockerUUID)
+			}
+		}
+
+		if err := s.repo.AddDependency(ctx, tx, uuid, blockerUUID); err != nil {
+			return err
+		}
+		updated, err = s.repo.GetByUUID(ctx, tx, uuid)
+		if err != nil {
+			return err
+		}
+		return s.queueEvents(ctx, tx, &pending, taskEvents(ctx, EventUpdated, []Snapshot{updated.ToSnapshot()}, nil))
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.notify(pending)
+	return updated, nil
+}
+
+func (s *Service) RemoveDependency(ctx context.Context, uuid, blockerUUID string) (*Task, error) {
+	var updated *Task
+	var pending []events.Event
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		existing, err := s.repo.GetByUUID(ctx, tx, uuid)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return ErrTaskNotFound
+		}
+		if err := s.authorize(ctx, tx, existing.WorkspaceID, workspace.RoleEditor); err != nil {
+			return err
+		}
+		if err := s.repo.RemoveDependency(ctx, tx, uuid, blockerUUID); err != nil {
+			return err
+		}
+		updated, err = s.repo.GetByUUID(ctx, tx, uuid)
+		if err != nil {
+			return err
+		}
+		return s.queueEvents(ctx, tx, &pending, taskEvents(ctx, EventUpdated, []Snapshot{updated.ToSnapshot()}, nil))
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.notify(pending)
+	return updated, nil
+}
+
+// checkUnblocked refuses to start (move to the active status) or finish (move to a terminal
+// status) tasks that still wait on open blockers. When completing in bulk,
+// blockers completed in the same batch count as done.
+func (s *Service) checkUnblocked(ctx context.Context, tx interface{}, uuids []string, target Status) error {
+	terminal := s.workflow.IsTerminal(target)
+	if len(uuids) == 0 || (target != s.workflow.Active && !terminal) {
+		return nil
+	}
+	open, err := s.repo.GetOpenBlockers(ctx, tx, uuids)
+	if err != nil {
+		return err
+	}
+	batch := make(map[string]struct{}, len(uuids))
+	for _, id := range uuids {
+		batch[id] = struct{}{}
+	}
+	for _, d := range open {
+		if _, ok := batch[d.BlockerUUID]; ok && terminal {
+			continue
+		}
+		return ErrTaskBlocked
+	}
+	return nil
+}
+
+// Reparent moves a task (with its subtree) under parentUUID, or makes it a root
+// task when parentUUID is nil. The subtree adopts the new parent's project.
+func (s *Service) Reparent(ctx context.Context, uuid string, parentUUID *string) (*Task, string, error) {
+	var moved *Task
+	var undoToken string
+	var pending []events.Event
+
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		subtree, err := s.collectSubtree(ctx, tx, []string{uuid})
+		if err != nil {
+			return err
+		}
+		if len(subtree) == 0 {
+			return ErrTaskNotFound
+		}
+		root := subtree[0]
+		if err := s.authorize(ctx, tx, root.WorkspaceID, workspace.RoleEditor); err != nil {
+			return err
+		}
+
+		projectUUID := root.ProjectUUID
+		if parentUUID != nil {
+			for _, t := range subtree {
+				if t.UUID == *parentUUID {
+					return ErrParentLoop
+				}
+			}
+			parent, err := s.repo.GetByUUID(ctx, tx, *parentUUID)
+			if err != nil {
+				return err
+			}
+			if parent == nil {
+				return errors.New("parent task not found")
+			}
+			if parent.WorkspaceID != root.WorkspaceID {
+				return ErrCrossWorkspace
+			}
+			projectUUID = parent.ProjectUUID
+		}
+
+		ids := make([]string, 0, len(subtree))
+		for _, t := range subtree {
+			ids = append(ids, t.UUID)
+		}
+		before := orderedSnapshots(subtree, ids)
+
+		if err := s.repo.UpdateColumns(ctx, tx, root.UUID, map[string]any{
+			"parent_uuid": parentUUID,
+			"sort_weight": s.defaultWeight(),
+		}); err != nil {
+			return err
+		}
+		for _, t := range subtree {
+			if sameProject(t.ProjectUUID, projectUUID) {
+				continue
+			}
+			if err := s.repo.UpdateColumns(ctx, tx, t.UUID, map[string]any{"project_uuid": projectUUID}); err != nil {
+				return err
+			}
+		}
+
+		afterTasks, err := s.repo.GetByUUIDs(ctx, tx, ids)
+		if err != nil {
+			return err
+		}
+		after := orderedSnapshots(afterTasks, ids)
+
+		scope := ScopeSingle
+		if len(ids) > 1 {
+			scope = ScopeBulk
+		}
+		token, err := s.recordOperation(ctx, tx, &pending, ActionReparent, scope, ids, before, after)
+		if err != nil {
+			return err
+		}
+		undoToken = token
+
+		moved, err = s.repo.GetByUUID(ctx, tx, uuid)
+		return err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	s.notify(pending)
+	return moved, undoToken, nil
+}
+
+// autoCompleteParents walks up from the given parents and completes every
+// ancestor whose subtasks are now all done, when AutoCompleteParent is on.
+// It returns the before/after snapshots of the ancestors it changed so the
+// caller can record them in the same undo operation.
+func (s *Service) autoCompleteParents(ctx context.Context, tx *gorm.DB, parentUUIDs []*string, completedAt time.Time) ([]Snapshot, []Snapshot, error) {
+	if !s.opts.AutoCompleteParent {
+		return nil, nil, nil
+	}
+
+	var before, after []Snapshot
+	visited := make(map[string]struct{})
+	for _, start := range parentUUIDs {
+		for parentUUID := start; parentUUID != nil; {
+			if _, ok := visited[*parentUUID]; ok {
+				break
+			}
+			visited[*parentUUID] = struct{}{}
+
+			parent, err := s.repo.GetByUUID(ctx, tx, *parentUUID)
+			if err != nil {
+				return nil, nil, err
+			}
+			if parent == nil || s.workflow.IsTerminal(parent.Status) || len(parent.Children) == 0 {
+				break
+			}
+			done := true
+			for _, child := range parent.Children {
+				if !s.workflow.IsTerminal(child.Status) {
+					done = false
+					break
+				}
+			}
+			if !done {
+				break
+			}
+			completion := s.workflow.CompletionStatus()
+			if !s.workflow.CanTransition(parent.Status, completion) {
+				break
+			}
+			if err := s.checkUnblocked(ctx, tx, []string{parent.UUID}, completion); err != nil {
+				if errors.Is(err, ErrTaskBlocked) {
+					break
+				}
+				return nil, nil, err
+			}
+
+			before = append(before, parent.ToSnapshot())
+			if err := s.repo.UpdateColumns(ctx, tx, parent.UUID, map[string]any{
+				"status":       completion,
+				"completed_at": completedAt,
+				"sort_weight":  s.defaultWeight(),
+			}); err != nil {
+				return nil, nil, err
+			}
+			completed, err := s.repo.GetByUUID(ctx, tx, parent.UUID)
+			if err != nil {
+				return nil, nil, err
+			}
+			after = append(after, completed.ToSnapshot())
+			parentUUID = parent.ParentUUID
+		}
+	}
+	return before, after, nil
+}
+
+// loadSubtrees replaces the one-level Children preloaded by the repository
+// with subtrees depth levels deep.
+func (s *Service) loadSubtrees(ctx context.Context, tx interface{}, roots []Task, depth int) error {
+	if depth <= 0 {
+		depth = DefaultTreeDepth
+	}
+	if depth > MaxTreeDepth {
+		depth = MaxTreeDepth
+	}
+	if depth == DefaultTreeDepth {
+		return nil
+	}
+
+	level := make([]*Task, 0, len(roots))
+	for i := range roots {
+		level = append(level, &roots[i])
+	}
+	for d := 0; d < depth && len(level) > 0; d++ {
+		ids := make([]string, 0, len(level))
+		for _, t := range level {
+			ids = append(ids, t.UUID)
+		}
+		children, err := s.repo.GetByParentUUIDs(ctx, tx, ids)
+		if err != nil {
+			return err
+		}
+		byParent := make(map[string][]Task, len(level))
+		for _, child := range children {
+			byParent[*child.ParentUUID] = append(byParent[*child.ParentUUID], child)
+		}
+		next := make([]*Task, 0, len(children))
+		for _, t := range level {
+			t.Children = byParent[t.UUID]
+			for i := range t.Children {
+				next = append(next, &t.Children[i])
+			}
+		}
+		level = next
+	}
+	return nil
+}
+
+func sameProject(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// MoveToProject moves a root task and its whole subtree into projectUUID, or out
+// of any project when projectUUID is nil, as a single undoable operation.
+func (s *Service) MoveToProject(ctx context.Context, uuid string, projectUUID *string) (*Task, string, error) {
+	var moved *Task
+	var undoToken string
+	var pending []events.Event
+
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		root, err := s.repo.GetByUUID(ctx, tx, uuid)
+		if err != nil {
+			return err
+		}
+		if root == nil {
+			return ErrTaskNotFound
+		}
+		if root.ParentUUID != nil {
+			return ErrSubtaskProject
+		}
+		if err := s.authorize(ctx, tx, root.WorkspaceID, workspace.RoleEditor); err != nil {
+			return err
+		}
+		if projectUUID != nil {
+			if err := s.checkProjectWritable(ctx, tx, *projectUUID, root.WorkspaceID); err != nil {
+				return err
+			}
+		}
+
+		subtree, err := s.collectSubtree(ctx, tx, []string{root.UUID})
+		if err != nil {
+			return err
+		}
+		ids := make([]string, 0, len(subtree))
+		for _, t := range subtree {
+			ids = append(ids, t.UUID)
+		}
+		before := orderedSnapshots(subtree, ids)
+
+		for _, id := range ids {
+			if err := s.repo.UpdateColumns(ctx, tx, id, map[string]any{"project_uuid": projectUUID}); err != nil {
+				return err
+			}
+		}
+
+		afterTasks, err := s.repo.GetByUUIDs(ctx, tx, ids)
+		if err != nil {
+			return err
+		}
+		after := orderedSnapshots(afterTasks, ids)
+
+		scope := ScopeSingle
+		if len(ids) > 1 {
+			scope = ScopeBulk
+		}
+		token, err := s.recordOperation(ctx, tx, &pending, ActionMoveProject, scope, ids, before, after)
+		if err != nil {
+			return err
+		}
+		undoToken = token
+
+		moved, err = s.repo.GetByUUID(ctx, tx, uuid)
+		return err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	s.notify(pending)
+	return moved, undoToken, nil
+}
+
+// collectSubtree returns the tasks in rootUUIDs followed by all of their
+// descendants, breadth first.
+func (s *Service) collectSubtree(ctx context.Context, tx interface{}, rootUUIDs []string) ([]Task, error) {
+	roots, err := s.repo.GetByUUIDs(ctx, tx, rootUUIDs)
+	if err != nil {
+		return nil, err
+	}
+	result := orderedTasks(roots, rootUUIDs)
+	seen := make(map[string]struct{}, len(result))
+	frontier := make([]string, 0, len(result))
+	for _, t := range result {
+		seen[t.UUID] = struct{}{}
+		frontier = append(frontier, t.UUID)
+	}
+	for len(frontier) > 0 {
+		children, err := s.repo.GetByParentUUIDs(ctx, tx, frontier)
+		if err != nil {
+			return nil, err
+		}
+		frontier = frontier[:0]
+		for _, child := range children {
+			if _, ok := seen[child.UUID]; ok {
+				continue
+			}
+			seen[child.UUID] = struct{}{}
+			result = append(result, child)
+			frontier = append(frontier, child.UUID)
+		}
+	}
+	return result, nil
+}
+
+// checkProjectWritable verifies that tasks of workspaceID may be added to the project.
+func (s *Service) checkProjectWritable(ctx context.Context, tx interface{}, projectUUID string, workspaceID uint64) error {
+	p, err := s.repo.GetProject(ctx, tx, projectUUID)
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		return project.ErrProjectNotFound
+	}
+	if p.WorkspaceID != workspaceID {
+		return ErrCrossWorkspace
+	}
+	if p.Archived {
+		return project.ErrProjectArchived
+	}
+	return nil
+}
+
+// checkProjectScope verifies every task belongs to projectUUID when a scope is given.
+func checkProjectScope(tasks []Task, projectUUID *string) error {
+	if projectUUID == nil {
+		return nil
+	}
+	for _, t := range tasks {
+		if t.ProjectUUID == nil || *t.ProjectUUID != *projectUUID {
+			return ErrOutOfProject
+		}
+	}
+	return nil
+}
+
+// resolveTags loads the distinct tags referenced by ids and fails with
+// tag.ErrTagNotFound when any of them does not exist, or ErrCrossWorkspace
+// when one belongs to another workspace than workspaceID.
+func (s *Service) resolveTags(ctx context.Context, tx interface{}, workspaceID uint64, ids []uint64) ([]tag.Tag, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	seen := make(map[uint64]struct{}, len(ids))
+	unique := make([]uint64, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		unique = append(unique, id)
+	}
+	tags, err := s.repo.GetTagsByIDs(ctx, tx, unique)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) != len(unique) {
+		return nil, tag.ErrTagNotFound
+	}
+	for _, t := range tags {
+		if t.WorkspaceID != workspaceID {
+			return nil, ErrCrossWorkspace
+		}
+	}
+	return tags, nil
+}
+
+func orderedSnapshots(tasks []Task, uuids []string) []Snapshot {
+	byUUID := make(map[string]Snapshot, len(tasks))
+	for _, t := range tasks {
+		byUUID[t.UUID] = t.ToSnapshot()
+	}
+	result := make([]Snapshot, 0, len(uuids))
+	for _, id := range uuids {
+		if snap, ok := byUUID[id]; ok {
+			result = append(result, snap)
+		}
+	}
+	return result
+}
+
+func orderedTasks(tasks []Task, uuids []string) []Task {
+	byUUID := make(map[string]Task, len(tasks))
+	for _, t := range tasks {
+		byUUID[t.UUID] = t
+	}
+	result := make([]Task, 0, len(uuids))
+	for _, id := range uuids {
+		if t, ok := byUUID[id]; ok {
+			result = append(result, t)
+		}
+	}
+	return result
+}
@@ -0,0 +1,337 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"todolist/backend/internal/domain/workspace"
+	"todolist/backend/internal/pkg/auth"
+)
+
+// MaxTimesheetDays bounds the range of a single timesheet export.
+const MaxTimesheetDays = 366
+
+var (
+	ErrTimeEntryNotFound = errors.New("time entry not found")
+	ErrTimerRunning      = errors.New("another timer is already running")
+	ErrTimerNotRunning   = errors.New("no timer running on task")
+	ErrInvalidTimeRange  = errors.New("invalid time range")
+)
+
+type TimeEntryInput struct {
+	StartedAt time.Time
+	EndedAt   time.Time
+	Note      *string
+}
+
+type TimeEntryPatch struct {
+	StartedAt *time.Time
+	EndedAt   *time.Time
+	Note      *string
+	NoteSet   bool
+}
+
+// TimesheetDay is the tracked time of one calendar day, split by task.
+type TimesheetDay struct {
+	Date         string
+	TotalSeconds int64
+	Tasks        []TimesheetRow
+}
+
+type TimesheetRow struct {
+	TaskUUID string
+	Title    string
+	Seconds  int64
+}
+
+// StartTimer opens a timer on the task. Only one timer may run at a time;
+// the caller's user row is locked first so two concurrent starts cannot both
+// find no timer running.
+func (s *Service) StartTimer(ctx context.Context, uuid string, note *string) (*TimeEntry, error) {
+	var entry *TimeEntry
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		existing, err := s.repo.GetByUUID(ctx, tx, uuid)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return ErrTaskNotFound
+		}
+		if err := s.authorize(ctx, tx, existing.WorkspaceID, workspace.RoleEditor); err != nil {
+			return err
+		}
+		userID := auth.OwnerID(ctx)
+		if err := s.repo.LockTimers(ctx, tx, userID); err != nil {
+			return err
+		}
+		running, err := s.repo.GetRunningTimeEntry(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if running != nil {
+			return ErrTimerRunning
+		}
+		entry = &TimeEntry{
+			TaskUUID:  uuid,
+			UserID:    userID,
+			StartedAt: time.Now(),
+			Note:      trimNote(note),
+		}
+		return s.repo.CreateTimeEntry(ctx, tx, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// StopTimer closes the timer running on the task.
+func (s *Service) StopTimer(ctx context.Context, uuid string) (*TimeEntry, error) {
+	var entry *TimeEntry
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		running, err := s.repo.GetRunningTimeEntry(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if running == nil || running.TaskUUID != uuid {
+			return ErrTimerNotRunning
+		}
+		closeEntry(running, time.Now())
+		entry = running
+		return s.repo.UpdateTimeEntry(ctx, tx, running)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// RunningTimer returns the open timer, or nil when none is running.
+func (s *Service) RunningTimer(ctx context.Context) (*TimeEntry, error) {
+	return s.repo.GetRunningTimeEntry(ctx, nil)
+}
+
+func (s *Service) ListTimeEntries(ctx context.Context, uuid string) ([]TimeEntry, error) {
+	existing, err := s.repo.GetByUUID(ctx, nil, uuid)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, ErrTaskNotFound
+	}
+	return s.repo.ListTimeEntries(ctx, nil, uuid)
+}
+
+// CreateTimeEntry logs a finished span of work after the fact.
+func (s *Service) CreateTimeEntry(ctx context.Context, uuid string, input TimeEntryInput) (*TimeEntry, error) {
+	if !input.EndedAt.After(input.StartedAt) {
+		return nil, ErrInvalidTimeRange
+	}
+
+	entry := &TimeEntry{
+		TaskUUID:  uuid,
+		UserID:    auth.OwnerID(ctx),
+		StartedAt: input.StartedAt,
+		Note:      trimNote(input.Note),
+	}
+	closeEntry(entry, input.EndedAt)
+
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		existing, err := s.repo.GetByUUID(ctx, tx, uuid)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return ErrTaskNotFound
+		}
+		if err := s.authorize(ctx, tx, existing.WorkspaceID, workspace.RoleEditor); err != nil {
+			return err
+		}
+		return s.repo.CreateTimeEntry(ctx, tx, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// UpdateTimeEntry edits an entry. Setting EndedAt on a running entry stops it.
+func (s *Service) UpdateTimeEntry(ctx context.Context, uuid string, id uint64, patch TimeEntryPatch) (*TimeEntry, error) {
+	var entry *TimeEntry
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		existing, err := s.ownTimeEntry(ctx, tx, uuid, id)
+		if err != nil {
+			return err
+		}
+		if patch.StartedAt != nil {
+			existing.StartedAt = *patch.StartedAt
+		}
+		if patch.NoteSet {
+			existing.Note = trimNote(patch.Note)
+		}
+		end := existing.EndedAt
+		if patch.EndedAt != nil {
+			end = patch.EndedAt
+		}
+		if end != nil {
+			if !end.After(existing.StartedAt) {
+				return ErrInvalidTimeRange
+			}
+			closeEntry(existing, *end)
+		} else if existing.StartedAt.After(time.Now()) {
+			return ErrInvalidTimeRange
+		}
+		entry = existing
+		return s.repo.UpdateTimeEntry(ctx, tx, existing)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (s *Service) DeleteTimeEntry(ctx context.Context, uuid string, id uint64) error {
+	return s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		if _, err := s.ownTimeEntry(ctx, tx, uuid, id); err != nil {
+			return err
+		}
+		return s.repo.DeleteTimeEntry(ctx, tx, uuid, id)
+	})
+}
+
+// ownTimeEntry loads an entry of a task the caller may edit. Entries logged by
+// someone else may only be changed by the workspace owner.
+func (s *Service) ownTimeEntry(ctx context.Context, tx *gorm.DB, uuid string, id uint64) (*TimeEntry, error) {
+	t, err := s.repo.GetByUUID(ctx, tx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, ErrTaskNotFound
+	}
+	if err := s.authorize(ctx, tx, t.WorkspaceID, workspace.RoleEditor); err != nil {
+		return nil, err
+	}
+	entry, err := s.repo.GetTimeEntry(ctx, tx, uuid, id)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, ErrTimeEntryNotFound
+	}
+	if entry.UserID != auth.OwnerID(ctx) {
+		if err := s.authorize(ctx, tx, t.WorkspaceID, workspace.RoleOwner); err != nil {
+			return nil, err
+		}
+	}
+	return entry, nil
+}
+
+// Timesheet sums tracked time per day and task for the days from..to
+// inclusive. Entries count towards the day they started on; a running timer
+// counts up to now.
+func (s *Service) Timesheet(ctx context.Context, from, to time.Time) ([]TimesheetDay, error) {
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.Local)
+	end := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, 1)
+	if !end.After(from) || end.Sub(from) > MaxTimesheetDays*24*time.Hour {
+		return nil, ErrInvalidTimeRange
+	}
+
+	entries, err := s.repo.ListTimeEntriesBetween(ctx, nil, from, end)
+	if err != nil {
+		return nil, err
+	}
+
+	taskUUIDs := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		if !seen[e.TaskUUID] {
+			seen[e.TaskUUID] = true
+			taskUUIDs = append(taskUUIDs, e.TaskUUID)
+		}
+	}
+	titles := make(map[string]string, len(taskUUIDs))
+	if len(taskUUIDs) > 0 {
+		tasks, err := s.repo.GetByUUIDs(ctx, nil, taskUUIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range tasks {
+			titles[t.UUID] = t.Title
+		}
+	}
+
+	now := time.Now()
+	days := make([]TimesheetDay, 0)
+	dayIndex := make(map[string]int)
+	rowIndex := make(map[string]int)
+	for _, e := range entries {
+		date := e.StartedAt.In(time.Local).Format("2006-01-02")
+		di, ok := dayIndex[date]
+		if !ok {
+			di = len(days)
+			dayIndex[date] = di
+			days = append(days, TimesheetDay{Date: date})
+		}
+		seconds := e.Duration(now)
+		day := &days[di]
+		day.TotalSeconds += seconds
+
+		key := date + "/" + e.TaskUUID
+		ri, ok := rowIndex[key]
+		if !ok {
+			ri = len(day.Tasks)
+			rowIndex[key] = ri
+			day.Tasks = append(day.Tasks, TimesheetRow{TaskUUID: e.TaskUUID, Title: titles[e.TaskUUID]})
+		}
+		day.Tasks[ri].Seconds += seconds
+	}
+	for i := range days {
+		rows := days[i].Tasks
+		sort.SliceStable(rows, func(a, b int) bool {
+			return rows[a].Seconds > rows[b].Seconds
+		})
+	}
+	return days, nil
+}
+
+// stopTimers closes every timer running on the tasks, whoever started it.
+func (s *Service) stopTimers(ctx context.Context, tx *gorm.DB, uuids []string, at time.Time) error {
+	running, err := s.repo.ListRunningTimeEntries(ctx, tx, uuids)
+	if err != nil {
+		return err
+	}
+	for i := range running {
+		entry := &running[i]
+		end := at
+		if end.Before(entry.StartedAt) {
+			end = entry.StartedAt
+		}
+		closeEntry(entry, end)
+		if err := s.repo.UpdateTimeEntry(ctx, tx, entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func closeEntry(e *TimeEntry, at time.Time) {
+	e.EndedAt = &at
+	e.DurationSeconds = int64(at.Sub(e.StartedAt).Seconds())
+}
+
+func trimNote(note *string) *string {
+	if note == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*note)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
@@ -0,0 +1,122 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"todolist/backend/internal/domain/workspace"
+	"todolist/backend/internal/pkg/auth"
+)
+
+// timerRepo keeps time entries in memory; methods the tests do not reach are
+// left to the embedded nil interface.
+type timerRepo struct {
+	TaskRepository
+	task    Task
+	entries []TimeEntry
+}
+
+func (r *timerRepo) GetByUUID(ctx context.Context, tx interface{}, uuid string) (*Task, error) {
+	if uuid != r.task.UUID {
+		return nil, nil
+	}
+	t := r.task
+	return &t, nil
+}
+
+func (r *timerRepo) ListRunningTimeEntries(ctx context.Context, tx interface{}, taskUUIDs []string) ([]TimeEntry, error) {
+	running := make([]TimeEntry, 0)
+	for _, e := range r.entries {
+		for _, id := range taskUUIDs {
+			if e.TaskUUID == id && e.EndedAt == nil {
+				running = append(running, e)
+			}
+		}
+	}
+	return running, nil
+}
+
+func (r *timerRepo) GetTimeEntry(ctx context.Context, tx interface{}, taskUUID string, id uint64) (*TimeEntry, error) {
+	for _, e := range r.entries {
+		if e.TaskUUID == taskUUID && e.ID == id {
+			return &e, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *timerRepo) UpdateTimeEntry(ctx context.Context, tx interface{}, entry *TimeEntry) error {
+	for i := range r.entries {
+		if r.entries[i].ID == entry.ID {
+			r.entries[i] = *entry
+		}
+	}
+	return nil
+}
+
+// roleAuthorizer grants each user a fixed role in every workspace.
+type roleAuthorizer struct {
+	Authorizer
+	roles map[uint64]workspace.Role
+}
+
+var errRole = errors.New("insufficient role")
+
+func (a roleAuthorizer) Authorize(ctx context.Context, tx interface{}, workspaceID uint64, role workspace.Role) error {
+	have := a.roles[auth.OwnerID(ctx)]
+	if have == role || have == workspace.RoleOwner || (have == workspace.RoleEditor && role == workspace.RoleViewer) {
+		return nil
+	}
+	return errRole
+}
+
+func TestStopTimersClosesEveryUsersTimer(t *testing.T) {
+	start := time.Now().Add(-time.Hour)
+	repo := &timerRepo{
+		task: Task{UUID: "task-1"},
+		entries: []TimeEntry{
+			{ID: 1, TaskUUID: "task-1", UserID: 1, StartedAt: start},
+			{ID: 2, TaskUUID: "task-1", UserID: 2, StartedAt: start},
+			{ID: 3, TaskUUID: "task-2", UserID: 2, StartedAt: start},
+		},
+	}
+	s := NewService(repo, nil, zap.NewNop(), Options{})
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{UserID: 1})
+
+	if err := s.stopTimers(ctx, nil, []string{"task-1"}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range repo.entries {
+		stopped := e.EndedAt != nil
+		if want := e.TaskUUID == "task-1"; stopped != want {
+			t.Errorf("entry %d of user %d stopped = %v, want %v", e.ID, e.UserID, stopped, want)
+		}
+	}
+}
+
+func TestOwnTimeEntryRejectsOtherUsersEntries(t *testing.T) {
+	end := time.Now()
+	repo := &timerRepo{
+		task: Task{UUID: "task-1", WorkspaceID: 7},
+		entries: []TimeEntry{
+			{ID: 1, TaskUUID: "task-1", UserID: 1, StartedAt: end.Add(-time.Hour), EndedAt: &end},
+		},
+	}
+	s := NewService(repo, nil, zap.NewNop(), Options{})
+	s.SetAuthorizer(roleAuthorizer{roles: map[uint64]workspace.Role{
+		1: workspace.RoleEditor,
+		2: workspace.RoleEditor,
+		3: workspace.RoleOwner,
+	}})
+
+	for user, wantErr := range map[uint64]error{1: nil, 2: errRole, 3: nil} {
+		ctx := auth.WithPrincipal(context.Background(), auth.Principal{UserID: user})
+		if _, err := s.ownTimeEntry(ctx, nil, "task-1", 1); !errors.Is(err, wantErr) {
+			t.Errorf("user %d: err = %v, want %v", user, err, wantErr)
+		}
+	}
+}
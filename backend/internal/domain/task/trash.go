@@ -0,0 +1,89 @@
+package task
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"todolist/backend/internal/domain/workspace"
+)
+
+// MaxTrashSize bounds the number of trashed tasks listed at once.
+const MaxTrashSize = 200
+
+var ErrNotInTrash = errors.New("task is not in trash")
+
+// PurgeListener lets data kept outside the task tables follow tasks that are
+// purged from the trash. TasksPurged runs inside the purge transaction; the
+// cleanup it returns, if any, runs once the transaction has committed.
+type PurgeListener interface {
+	TasksPurged(ctx context.Context, tx *gorm.DB, uuids []string) (cleanup func(), err error)
+}
+
+// AddPurgeListener registers l to be told about every purge.
+func (s *Service) AddPurgeListener(l PurgeListener) {
+	s.purgeListeners = append(s.purgeListeners, l)
+}
+
+// ListTrash returns deleted tasks whose parent is not itself deleted, most
+// recently deleted first. Subtasks deleted with their parent are purged and
+// restored together with it.
+func (s *Service) ListTrash(ctx context.Context) ([]Task, error) {
+	return s.repo.ListDeleted(ctx, nil, MaxTrashSize)
+}
+
+// Purge removes a deleted task and its deleted subtasks for good, along with
+// their tags, checklist, dependencies, time entries, custom values, comments
+// and activity. Pending undo operations that touched them expire, so purged
+// tasks can no longer be restored through undo.
+func (s *Service) Purge(ctx context.Context, uuid string) error {
+	var cleanups []func()
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		root, err := s.repo.GetWithDeleted(ctx, tx, uuid)
+		if err != nil {
+			return err
+		}
+		if root == nil {
+			return ErrTaskNotFound
+		}
+		if !root.DeletedAt.Valid {
+			return ErrNotInTrash
+		}
+		if err := s.authorize(ctx, tx, root.WorkspaceID, workspace.RoleEditor); err != nil {
+			return err
+		}
+
+		uuids := []string{root.UUID}
+		frontier := []string{root.UUID}
+		for len(frontier) > 0 {
+			children, err := s.repo.GetDeletedByParentUUIDs(ctx, tx, frontier)
+			if err != nil {
+				return err
+			}
+			frontier = frontier[:0]
+			for _, child := range children {
+				uuids = append(uuids, child.UUID)
+				frontier = append(frontier, child.UUID)
+			}
+		}
+
+		for _, l := range s.purgeListeners {
+			cleanup, err := l.TasksPurged(ctx, tx, uuids)
+			if err != nil {
+				return err
+			}
+			if cleanup != nil {
+				cleanups = append(cleanups, cleanup)
+			}
+		}
+		return s.repo.Purge(ctx, tx, uuids)
+	})
+	if err != nil {
+		return err
+	}
+	for _, cleanup := range cleanups {
+		cleanup()
+	}
+	return nil
+}
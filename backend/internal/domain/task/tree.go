@@ -0,0 +1,200 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"todolist/backend/internal/domain/workspace"
+	"todolist/backend/internal/pkg/auth"
+	"todolist/backend/internal/pkg/events"
+)
+
+// TaskTreeInput describes a task to create together with its subtasks.
+type TaskTreeInput struct {
+	Title        string
+	Notes        *string
+	Deadline     *time.Time
+	Priority     Priority
+	Estimate     *int
+	EstimateUnit EstimateUnit
+	Checklist    []string
+	Children     []TaskTreeInput
+}
+
+// CreateTreeOptions places the root of a created tree. Subtasks inherit the
+// status and project of the root.
+type CreateTreeOptions struct {
+	Status      Status
+	ProjectUUID *string
+	ParentUUID  *string
+	// WorkspaceID picks the workspace of a root tree; nil means the caller's
+	// default. Trees under ParentUUID go to the parent's workspace.
+	WorkspaceID *uint64
+}
+
+var ErrInvalidTitle = errors.New("invalid title")
+
+// CreateTree creates root and all of its descendants in one transaction,
+// recorded as a single undoable create.
+func (s *Service) CreateTree(ctx context.Context, root TaskTreeInput, opts CreateTreeOptions) (*Task, string, error) {
+	status := opts.Status
+	if status == "" {
+		status = s.workflow.Initial
+	}
+	if !s.workflow.IsValid(status) {
+		return nil, "", errors.New("invalid status")
+	}
+	if err := validateTree(root); err != nil {
+		return nil, "", err
+	}
+
+	var created *Task
+	var undoToken string
+	var pending []events.Event
+
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		projectUUID := opts.ProjectUUID
+		var workspaceID uint64
+		if opts.ParentUUID != nil {
+			parent, err := s.repo.GetByUUID(ctx, tx, *opts.ParentUUID)
+			if err != nil {
+				return err
+			}
+			if parent == nil {
+				return errors.New("parent task not found")
+			}
+			if opts.WorkspaceID != nil && *opts.WorkspaceID != parent.WorkspaceID {
+				return ErrCrossWorkspace
+			}
+			if err := s.authorize(ctx, tx, parent.WorkspaceID, workspace.RoleEditor); err != nil {
+				return err
+			}
+			projectUUID = parent.ProjectUUID
+			workspaceID = parent.WorkspaceID
+		} else {
+			id, err := s.targetWorkspace(ctx, tx, opts.WorkspaceID)
+			if err != nil {
+				return err
+			}
+			workspaceID = id
+			if projectUUID != nil {
+				if err := s.checkProjectWritable(ctx, tx, *projectUUID, workspaceID); err != nil {
+					return err
+				}
+			}
+		}
+
+		var completedAt *time.Time
+		if s.workflow.IsTerminal(status) {
+			now := time.Now()
+			completedAt = &now
+		}
+		weight := s.defaultWeight()
+		var after []Snapshot
+
+		var create func(node TaskTreeInput, parentUUID *string) (*Task, error)
+		create = func(node TaskTreeInput, parentUUID *string) (*Task, error) {
+			priority := node.Priority
+			if priority == "" {
+				priority = PriorityNone
+			}
+			unit := node.EstimateUnit
+			if unit == "" {
+				unit = EstimateMinutes
+			}
+			t := &Task{
+				UUID:         uuid.NewString(),
+				OwnerID:      auth.OwnerID(ctx),
+				WorkspaceID:  workspaceID,
+				ParentUUID:   parentUUID,
+				ProjectUUID:  projectUUID,
+				Title:        strings.TrimSpace(node.Title),
+				Notes:        node.Notes,
+				Deadline:     node.Deadline,
+				Status:       status,
+				Priority:     priority,
+				Estimate:     node.Estimate,
+				EstimateUnit: unit,
+				SortWeight:   weight,
+				CompletedAt:  completedAt,
+			}
+			weight++
+			if err := s.repo.Create(ctx, tx, t); err != nil {
+				return nil, err
+			}
+			for idx, text := range node.Checklist {
+				item := ChecklistItem{TaskUUID: t.UUID, Text: strings.TrimSpace(text), Position: int64(idx)}
+				if err := s.repo.CreateChecklistItem(ctx, tx, &item); err != nil {
+					return nil, err
+				}
+				t.Checklist = append(t.Checklist, item)
+			}
+			after = append(after, t.ToSnapshot())
+			for _, child := range node.Children {
+				if _, err := create(child, &t.UUID); err != nil {
+					return nil, err
+				}
+			}
+			return t, nil
+		}
+
+		rootTask, err := create(root, opts.ParentUUID)
+		if err != nil {
+			return err
+		}
+
+		ids := make([]string, 0, len(after))
+		for _, snap := range after {
+			ids = append(ids, snap.UUID)
+		}
+		scope := ScopeSingle
+		if len(ids) > 1 {
+			scope = ScopeBulk
+		}
+		token, err := s.recordOperation(ctx, tx, &pending, ActionCreate, scope, ids, nil, after)
+		if err != nil {
+			return err
+		}
+		undoToken = token
+		created = rootTask
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	s.notify(pending)
+	loaded, err := s.Get(ctx, created.UUID, MaxTreeDepth)
+	if err != nil {
+		return nil, "", err
+	}
+	return loaded, undoToken, nil
+}
+
+func validateTree(node TaskTreeInput) error {
+	title := strings.TrimSpace(node.Title)
+	if title == "" || len(title) > 255 {
+		return ErrInvalidTitle
+	}
+	if node.Priority != "" && !IsValidPriority(node.Priority) {
+		return errors.New("invalid priority")
+	}
+	if (node.EstimateUnit != "" && !IsValidEstimateUnit(node.EstimateUnit)) || (node.Estimate != nil && *node.Estimate < 0) {
+		return ErrInvalidEstimate
+	}
+	for _, text := range node.Checklist {
+		if strings.TrimSpace(text) == "" {
+			return ErrInvalidChecklistText
+		}
+	}
+	for _, child := range node.Children {
+		if err := validateTree(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
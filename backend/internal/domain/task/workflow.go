@@ -0,0 +1,164 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// StatusDef describes one column of a workflow. Terminal statuses count as
+// done: moving a task into one sets CompletedAt.
+type StatusDef struct {
+	Key         Status
+	Name        string
+	Terminal    bool
+	DefaultSort SortKey
+}
+
+// Workflow is the set of statuses tasks move through. Transitions lists the
+// statuses reachable from a status; a status without an entry may move
+// anywhere. Active is where tasks being worked on sit, which capacity and
+// the blocker check look at; Backlog is where the daily plan picks tasks
+// from.
+type Workflow struct {
+	Statuses    []StatusDef
+	Initial     Status
+	Active      Status
+	Backlog     Status
+	Transitions map[Status][]Status
+}
+
+var (
+	ErrInvalidWorkflow   = errors.New("invalid workflow")
+	ErrInvalidTransition = errors.New("status transition not allowed")
+)
+
+var statusKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,31}$`)
+
+// DefaultWorkflow is the original now/future/history board.
+func DefaultWorkflow() *Workflow {
+	return &Workflow{
+		Statuses: []StatusDef{
+			{Key: StatusNow, Name: "Now", DefaultSort: SortDeadline},
+			{Key: StatusFuture, Name: "Future", DefaultSort: SortDeadline},
+			{Key: StatusHistory, Name: "History", Terminal: true, DefaultSort: SortCompleted},
+		},
+		Initial: StatusFuture,
+		Active:  StatusNow,
+		Backlog: StatusFuture,
+	}
+}
+
+// NewWorkflow validates a workflow definition.
+func NewWorkflow(statuses []StatusDef, initial, active, backlog Status, transitions map[Status][]Status) (*Workflow, error) {
+	w := &Workflow{Statuses: statuses, Initial: initial, Active: active, Backlog: backlog, Transitions: transitions}
+	if len(statuses) == 0 {
+		return nil, fmt.Errorf("%w: no statuses", ErrInvalidWorkflow)
+	}
+	seen := make(map[Status]bool, len(statuses))
+	hasTerminal := false
+	for _, def := range statuses {
+		if !statusKeyPattern.MatchString(string(def.Key)) {
+			return nil, fmt.Errorf("%w: bad status key %q", ErrInvalidWorkflow, def.Key)
+		}
+		if seen[def.Key] {
+			return nil, fmt.Errorf("%w: duplicate status %q", ErrInvalidWorkflow, def.Key)
+		}
+		if !IsValidSortKey(def.DefaultSort) {
+			return nil, fmt.Errorf("%w: bad default sort for %q", ErrInvalidWorkflow, def.Key)
+		}
+		seen[def.Key] = true
+		hasTerminal = hasTerminal || def.Terminal
+	}
+	if !hasTerminal {
+		return nil, fmt.Errorf("%w: no terminal status", ErrInvalidWorkflow)
+	}
+	if !seen[initial] {
+		return nil, fmt.Errorf("%w: unknown initial status %q", ErrInvalidWorkflow, initial)
+	}
+	if !seen[active] || w.IsTerminal(active) {
+		return nil, fmt.Errorf("%w: active status %q must be a non-terminal status", ErrInvalidWorkflow, active)
+	}
+	if !seen[backlog] || w.IsTerminal(backlog) {
+		return nil, fmt.Errorf("%w: backlog status %q must be a non-terminal status", ErrInvalidWorkflow, backlog)
+	}
+	if active == backlog {
+		return nil, fmt.Errorf("%w: active and backlog status are both %q", ErrInvalidWorkflow, active)
+	}
+	for from, targets := range transitions {
+		if !seen[from] {
+			return nil, fmt.Errorf("%w: unknown status %q in transitions", ErrInvalidWorkflow, from)
+		}
+		for _, to := range targets {
+			if !seen[to] {
+				return nil, fmt.Errorf("%w: unknown status %q in transitions", ErrInvalidWorkflow, to)
+			}
+		}
+	}
+	return w, nil
+}
+
+func (w *Workflow) Status(key Status) (StatusDef, bool) {
+	for _, def := range w.Statuses {
+		if def.Key == key {
+			return def, true
+		}
+	}
+	return StatusDef{}, false
+}
+
+func (w *Workflow) IsValid(status Status) bool {
+	_, ok := w.Status(status)
+	return ok
+}
+
+func (w *Workflow) IsTerminal(status Status) bool {
+	def, ok := w.Status(status)
+	return ok && def.Terminal
+}
+
+func (w *Workflow) TerminalStatuses() []Status {
+	result := make([]Status, 0, 1)
+	for _, def := range w.Statuses {
+		if def.Terminal {
+			result = append(result, def.Key)
+		}
+	}
+	return result
+}
+
+// CompletionStatus is where Complete and parent auto-completion move tasks:
+// history when the workflow has it as a terminal status, else the first
+// terminal status.
+func (w *Workflow) CompletionStatus() Status {
+	if w.IsTerminal(StatusHistory) {
+		return StatusHistory
+	}
+	return w.TerminalStatuses()[0]
+}
+
+func (w *Workflow) CanTransition(from, to Status) bool {
+	if from == to {
+		return true
+	}
+	targets, ok := w.Transitions[from]
+	if !ok {
+		return true
+	}
+	for _, t := range targets {
+		if t == to {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *Workflow) DefaultSort(status Status) SortKey {
+	def, _ := w.Status(status)
+	return def.DefaultSort
+}
+
+// Workflow returns the workflow the service enforces.
+func (s *Service) Workflow() *Workflow {
+	return s.workflow
+}
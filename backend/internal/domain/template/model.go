@@ -0,0 +1,53 @@
+package template
+
+import (
+	"encoding/json"
+	"time"
+
+	"todolist/backend/internal/domain/task"
+)
+
+// Template is a reusable task tree shared within a workspace. The tree itself
+// is kept as JSON in Tree.
+type Template struct {
+	ID          uint64    `gorm:"primaryKey;autoIncrement"`
+	WorkspaceID uint64    `gorm:"not null;default:0;index"`
+	Name        string    `gorm:"size:255;not null"`
+	Description *string   `gorm:"type:text"`
+	Tree        string    `gorm:"type:json;not null"`
+	CreatedAt   time.Time `gorm:"not null;autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"not null;autoUpdateTime"`
+}
+
+func (Template) TableName() string {
+	return "task_templates"
+}
+
+// Node is one task of a template. Titles may contain {{variable}}
+// placeholders. DeadlineOffset is in days relative to the anchor date given
+// at instantiation; nil means no deadline.
+type Node struct {
+	Title          string            `json:"title"`
+	Notes          *string           `json:"notes,omitempty"`
+	DeadlineOffset *int              `json:"deadlineOffset,omitempty"`
+	Priority       task.Priority     `json:"priority,omitempty"`
+	Estimate       *int              `json:"estimate,omitempty"`
+	EstimateUnit   task.EstimateUnit `json:"estimateUnit,omitempty"`
+	Checklist      []string          `json:"checklist,omitempty"`
+	Children       []Node            `json:"children,omitempty"`
+}
+
+func (t *Template) Root() (Node, error) {
+	var root Node
+	err := json.Unmarshal([]byte(t.Tree), &root)
+	return root, err
+}
+
+func (t *Template) SetRoot(root Node) error {
+	data, err := json.Marshal(root)
+	if err != nil {
+		return err
+	}
+	t.Tree = string(data)
+	return nil
+}
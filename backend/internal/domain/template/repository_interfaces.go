@@ -0,0 +1,17 @@
+package template
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// TemplateRepository defines the interface for template repository operations
+type TemplateRepository interface {
+	DB() *gorm.DB
+	List(ctx context.Context, workspaceID *uint64) ([]Template, error)
+	GetByID(ctx context.Context, tx interface{}, id uint64) (*Template, error)
+	Create(ctx context.Context, tx interface{}, t *Template) error
+	Update(ctx context.Context, tx interface{}, t *Template) error
+	Delete(ctx context.Context, tx interface{}, id uint64) error
+}
@@ -0,0 +1,375 @@
+package template
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"todolist/backend/internal/domain/task"
+	"todolist/backend/internal/domain/workspace"
+)
+
+// MaxNodes bounds the number of tasks a single template may create.
+const MaxNodes = 200
+
+var (
+	ErrTemplateNotFound = errors.New("template not found")
+	ErrInvalidName      = errors.New("invalid template name")
+	ErrInvalidTree      = errors.New("invalid template tree")
+	ErrMissingVariable  = errors.New("missing template variable")
+)
+
+// variablePattern matches {{name}} placeholders in node titles.
+var variablePattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}`)
+
+type Service struct {
+	repo        TemplateRepository
+	taskService *task.Service
+	access      Authorizer
+	logger      *zap.Logger
+}
+
+// Authorizer checks what the caller may do in a workspace.
+type Authorizer interface {
+	Authorize(ctx context.Context, tx interface{}, workspaceID uint64, role workspace.Role) error
+	DefaultWorkspace(ctx context.Context, tx interface{}) (uint64, error)
+}
+
+func NewService(repo TemplateRepository, taskService *task.Service, logger *zap.Logger) *Service {
+	return &Service{repo: repo, taskService: taskService, logger: logger}
+}
+
+// SetAuthorizer enables role checks: every member of a workspace sees and
+// uses its templates, changing them needs the editor role.
+func (s *Service) SetAuthorizer(a Authorizer) {
+	s.access = a
+}
+
+type CreateInput struct {
+	Name        string
+	Description *string
+	Root        Node
+	// WorkspaceID defaults to the caller's default workspace.
+	WorkspaceID *uint64
+}
+
+type UpdateInput struct {
+	Name           *string
+	Description    *string
+	DescriptionSet bool
+	Root           *Node
+}
+
+// InstantiateInput controls where a template's tree is created. Deadlines are
+// offset from AnchorDate; Variables fill the {{name}} placeholders, with
+// {{date}} defaulting to the anchor date. Without a parent or workspace the
+// tree goes to the template's workspace.
+type InstantiateInput struct {
+	AnchorDate  time.Time
+	Variables   map[string]string
+	Status      task.Status
+	ProjectUUID *string
+	ParentUUID  *string
+	WorkspaceID *uint64
+}
+
+// List returns the templates of every workspace the caller belongs to, or of
+// workspaceID when set.
+func (s *Service) List(ctx context.Context, workspaceID *uint64) ([]Template, error) {
+	if workspaceID != nil {
+		if err := s.authorize(ctx, nil, *workspaceID, workspace.RoleViewer); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.List(ctx, workspaceID)
+}
+
+func (s *Service) Get(ctx context.Context, id uint64) (*Template, error) {
+	t, err := s.repo.GetByID(ctx, nil, id)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, ErrTemplateNotFound
+	}
+	return t, nil
+}
+
+func (s *Service) Create(ctx context.Context, input CreateInput) (*Template, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, ErrInvalidName
+	}
+	if err := validateRoot(input.Root); err != nil {
+		return nil, err
+	}
+	workspaceID, err := s.targetWorkspace(ctx, input.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, nil, workspaceID, workspace.RoleEditor); err != nil {
+		return nil, err
+	}
+	t := &Template{WorkspaceID: workspaceID, Name: name, Description: input.Description}
+	if err := t.SetRoot(input.Root); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, nil, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (s *Service) Update(ctx context.Context, id uint64, input UpdateInput) (*Template, error) {
+	var updated *Template
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		existing, err := s.repo.GetByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return ErrTemplateNotFound
+		}
+		if err := s.authorize(ctx, tx, existing.WorkspaceID, workspace.RoleEditor); err != nil {
+			return err
+		}
+		if input.Name != nil {
+			name := strings.TrimSpace(*input.Name)
+			if name == "" {
+				return ErrInvalidName
+			}
+			existing.Name = name
+		}
+		if input.DescriptionSet {
+			existing.Description = input.Description
+		}
+		if input.Root != nil {
+			if err := validateRoot(*input.Root); err != nil {
+				return err
+			}
+			if err := existing.SetRoot(*input.Root); err != nil {
+				return err
+			}
+		}
+		if err := s.repo.Update(ctx, tx, existing); err != nil {
+			return err
+		}
+		updated = existing
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (s *Service) Delete(ctx context.Context, id uint64) error {
+	return s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		existing, err := s.repo.GetByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return ErrTemplateNotFound
+		}
+		if err := s.authorize(ctx, tx, existing.WorkspaceID, workspace.RoleEditor); err != nil {
+			return err
+		}
+		return s.repo.Delete(ctx, tx, id)
+	})
+}
+
+// Instantiate creates the template's task tree through task.Service in one
+// transaction and returns the new root task with its undo token.
+func (s *Service) Instantiate(ctx context.Context, id uint64, input InstantiateInput) (*task.Task, string, error) {
+	t, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+	root, err := t.Root()
+	if err != nil {
+		return nil, "", err
+	}
+
+	anchor := input.AnchorDate
+	if anchor.IsZero() {
+		anchor = time.Now()
+	}
+	anchor = time.Date(anchor.Year(), anchor.Month(), anchor.Day(), 0, 0, 0, 0, time.Local)
+	vars := map[string]string{"date": anchor.Format("2006-01-02")}
+	for k, v := range input.Variables {
+		vars[k] = v
+	}
+
+	tree, err := buildTree(root, anchor, vars)
+	if err != nil {
+		return nil, "", err
+	}
+	workspaceID := input.WorkspaceID
+	if workspaceID == nil && input.ParentUUID == nil {
+		workspaceID = &t.WorkspaceID
+	}
+	return s.taskService.CreateTree(ctx, tree, task.CreateTreeOptions{
+		Status:      input.Status,
+		ProjectUUID: input.ProjectUUID,
+		ParentUUID:  input.ParentUUID,
+		WorkspaceID: workspaceID,
+	})
+}
+
+// SaveFromTask stores a task and its subtasks as a new template of the task's
+// workspace. Deadlines become offsets from the root task's deadline, or from
+// today when the root has none.
+func (s *Service) SaveFromTask(ctx context.Context, taskUUID, name string, description *string) (*Template, error) {
+	source, err := s.taskService.Get(ctx, taskUUID, task.MaxTreeDepth)
+	if err != nil {
+		return nil, err
+	}
+	anchor := time.Now()
+	if source.Deadline != nil {
+		anchor = *source.Deadline
+	}
+	return s.Create(ctx, CreateInput{
+		Name:        name,
+		Description: description,
+		Root:        nodeFromTask(*source, anchor),
+		WorkspaceID: &source.WorkspaceID,
+	})
+}
+
+func (s *Service) targetWorkspace(ctx context.Context, workspaceID *uint64) (uint64, error) {
+	if workspaceID != nil {
+		return *workspaceID, nil
+	}
+	if s.access == nil {
+		return 0, nil
+	}
+	return s.access.DefaultWorkspace(ctx, nil)
+}
+
+func (s *Service) authorize(ctx context.Context, tx interface{}, workspaceID uint64, role workspace.Role) error {
+	if s.access == nil {
+		return nil
+	}
+	return s.access.Authorize(ctx, tx, workspaceID, role)
+}
+
+// Variables lists the placeholder names used anywhere in the tree.
+func Variables(root Node) []string {
+	seen := make(map[string]bool)
+	var walk func(n Node)
+	walk = func(n Node) {
+		for _, m := range variablePattern.FindAllStringSubmatch(n.Title, -1) {
+			seen[m[1]] = true
+		}
+		for _, child := range n.Children {
+			walk(child)
+		}
+	}
+	walk(root)
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func buildTree(n Node, anchor time.Time, vars map[string]string) (task.TaskTreeInput, error) {
+	var missing bool
+	title := variablePattern.ReplaceAllStringFunc(n.Title, func(m string) string {
+		name := variablePattern.FindStringSubmatch(m)[1]
+		v, ok := vars[name]
+		if !ok {
+			missing = true
+		}
+		return v
+	})
+	if missing {
+		return task.TaskTreeInput{}, ErrMissingVariable
+	}
+
+	input := task.TaskTreeInput{
+		Title:        title,
+		Notes:        n.Notes,
+		Priority:     n.Priority,
+		Estimate:     n.Estimate,
+		EstimateUnit: n.EstimateUnit,
+		Checklist:    n.Checklist,
+	}
+	if n.DeadlineOffset != nil {
+		deadline := anchor.AddDate(0, 0, *n.DeadlineOffset)
+		input.Deadline = &deadline
+	}
+	for _, child := range n.Children {
+		c, err := buildTree(child, anchor, vars)
+		if err != nil {
+			return task.TaskTreeInput{}, err
+		}
+		input.Children = append(input.Children, c)
+	}
+	return input, nil
+}
+
+func nodeFromTask(t task.Task, anchor time.Time) Node {
+	n := Node{
+		Title:    t.Title,
+		Notes:    t.Notes,
+		Priority: t.Priority,
+		Estimate: t.Estimate,
+	}
+	if t.Estimate != nil {
+		n.EstimateUnit = t.EstimateUnit
+	}
+	if t.Deadline != nil {
+		offset := daysBetween(anchor, *t.Deadline)
+		n.DeadlineOffset = &offset
+	}
+	for _, item := range t.Checklist {
+		n.Checklist = append(n.Checklist, item.Text)
+	}
+	for _, child := range t.Children {
+		n.Children = append(n.Children, nodeFromTask(child, anchor))
+	}
+	return n
+}
+
+func daysBetween(from, to time.Time) int {
+	a := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	b := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(b.Sub(a).Hours() / 24)
+}
+
+func validateRoot(root Node) error {
+	count := 0
+	var walk func(n Node, depth int) error
+	walk = func(n Node, depth int) error {
+		count++
+		if count > MaxNodes || depth > task.MaxTreeDepth {
+			return ErrInvalidTree
+		}
+		title := strings.TrimSpace(n.Title)
+		if title == "" || len(title) > 255 {
+			return ErrInvalidTree
+		}
+		if n.Priority != "" && !task.IsValidPriority(n.Priority) {
+			return ErrInvalidTree
+		}
+		if n.EstimateUnit != "" && !task.IsValidEstimateUnit(n.EstimateUnit) {
+			return ErrInvalidTree
+		}
+		for _, child := range n.Children {
+			if err := walk(child, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return walk(root, 0)
+}
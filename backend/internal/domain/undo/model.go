@@ -14,6 +14,13 @@ const (
     ActionBulkComplete Action = "bulk_complete"
     ActionBulkDelete   Action = "bulk_delete"
     ActionResort       Action = "resort"
+    ActionBulkTag      Action = "bulk_tag"
+    ActionBulkUntag    Action = "bulk_untag"
+    ActionMoveProject  Action = "move_project"
+    ActionReparent     Action = "reparent"
+    ActionChecklist    Action = "checklist"
+    ActionConvertItem  Action = "convert_item"
+    ActionBulkAssign   Action = "bulk_assign"
 )
 
 type Scope string
@@ -26,6 +33,7 @@ const (
 type TaskOperation struct {
     ID          uint64    `gorm:"primaryKey;autoIncrement"`
     Token       string    `gorm:"type:char(26);uniqueIndex"`
+    OwnerID     uint64    `gorm:"not null;default:0;index"`
     Action      Action    `gorm:"size:32;not null"`
     Scope       Scope     `gorm:"size:16;not null"`
     TaskIDs     string    `gorm:"type:json;not null"`
@@ -12,12 +12,18 @@ import (
 	"gorm.io/gorm"
 
 	"todolist/backend/internal/domain/task"
+	"todolist/backend/internal/domain/workspace"
+	"todolist/backend/internal/pkg/auth"
+	"todolist/backend/internal/pkg/events"
 	"todolist/backend/internal/repository"
 )
 
 type Service struct {
 	repo     *repository.UndoRepository
 	taskRepo *repository.TaskRepository
+	access   task.Authorizer
+	notifier task.Notifier
+	recorder task.EventRecorder
 	ttl      time.Duration
 	logger   *zap.Logger
 }
@@ -26,7 +32,56 @@ func NewService(repo *repository.UndoRepository, taskRepo *repository.TaskReposi
 	return &Service{repo: repo, taskRepo: taskRepo, ttl: ttl, logger: logger}
 }
 
+// SetAuthorizer makes Undo check that the caller may still edit the
+// workspace of the tasks an operation touched.
+func (s *Service) SetAuthorizer(a task.Authorizer) {
+	s.access = a
+}
+
+// SetNotifier makes Undo announce that an undo has committed.
+func (s *Service) SetNotifier(n task.Notifier) {
+	s.notifier = n
+}
+
+// SetEventRecorder makes Undo store its event inside the undo transaction.
+func (s *Service) SetEventRecorder(r task.EventRecorder) {
+	s.recorder = r
+}
+
+// EventApplied is published once an undo has committed.
+const EventApplied = "undo.applied"
+
+// AppliedData is the payload of undo.applied: the operation that was undone
+// and the state its tasks are back in. Tasks the operation had created are
+// gone and only listed in TaskUUIDs. Previous is the state the undo
+// replaced, which routing needs.
+type AppliedData struct {
+	Action    task.Action     `json:"action"`
+	TaskUUIDs []string        `json:"taskUuids"`
+	Tasks     []task.Snapshot `json:"tasks,omitempty"`
+	Previous  []task.Snapshot `json:"previous,omitempty"`
+}
+
+// Snapshots returns every state the undo touched, before and after.
+func (d AppliedData) Snapshots() []task.Snapshot {
+	return append(append([]task.Snapshot(nil), d.Tasks...), d.Previous...)
+}
+
+// activityUndo is the feed action logged when an operation is undone.
+const activityUndo = "undo"
+
+type activityPayload struct {
+	Scope  task.Scope  `json:"scope"`
+	Undoes task.Action `json:"undoes,omitempty"`
+}
+
+// RecordOperation stores an undoable operation and adds it to the activity
+// feed of every task it touched.
 func (s *Service) RecordOperation(ctx context.Context, tx interface{}, action task.Action, scope task.Scope, taskIDs []string, before, after []task.Snapshot) (string, error) {
+	return s.record(ctx, tx, action, scope, taskIDs, before, after, string(action), activityPayload{Scope: scope})
+}
+
+func (s *Service) record(ctx context.Context, tx interface{}, action task.Action, scope task.Scope, taskIDs []string, before, after []task.Snapshot, activity string, payload activityPayload) (string, error) {
 	token := generateToken()
 	beforeJSON, err := json.Marshal(before)
 	if err != nil {
@@ -43,6 +98,7 @@ func (s *Service) RecordOperation(ctx context.Context, tx interface{}, action ta
 
 	op := &repository.TaskOperation{
 		Token:       token,
+		OwnerID:     auth.OwnerID(ctx),
 		Action:      string(action),
 		Scope:       string(scope),
 		TaskIDs:     string(idsJSON),
@@ -64,22 +120,37 @@ func (s *Service) RecordOperation(ctx context.Context, tx interface{}, action ta
 	if err := s.repo.Create(ctx, db, op); err != nil {
 		return "", err
 	}
+
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	logs := make([]task.ActivityLog, 0, len(taskIDs))
+	actor := task.Actor(ctx)
+	for _, id := range taskIDs {
+		logs = append(logs, task.ActivityLog{
+			TaskUUID: id,
+			OwnerID:  op.OwnerID,
+			Action:   activity,
+			Payload:  string(payloadJSON),
+			Actor:    actor,
+		})
+	}
+	if err := s.taskRepo.CreateActivity(ctx, db, logs); err != nil {
+		return "", err
+	}
 	return token, nil
 }
 
+// Undo reverts the operation behind token. Tokens recorded for another user
+// are reported as not found.
 func (s *Service) Undo(ctx context.Context, token string) ([]string, string, error) {
 	op, err := s.repo.GetByToken(ctx, nil, token)
 	if err != nil {
 		return nil, "", err
 	}
-	if op == nil {
-		return nil, "", ErrTokenNotFound
-	}
-	if op.IsConsumed() {
-		return nil, "", ErrTokenConsumed
-	}
-	if op.IsExpired(time.Now()) {
-		return nil, "", ErrTokenExpired
+	if err := usable(ctx, op, time.Now()); err != nil {
+		return nil, "", err
 	}
 
 	var before []task.Snapshot
@@ -96,8 +167,18 @@ func (s *Service) Undo(ctx context.Context, token string) ([]string, string, err
 		return nil, "", err
 	}
 
+	applied := events.Event{
+		Type:        EventApplied,
+		WorkspaceID: snapshotWorkspace(before, after),
+		ActorID:     auth.OwnerID(ctx),
+		At:          time.Now(),
+		Data:        AppliedData{Action: task.Action(op.Action), TaskUUIDs: ids, Tasks: before, Previous: after},
+	}
 	var reverseToken string
 	err = s.taskRepo.DB().Transaction(func(tx *gorm.DB) error {
+		if err := s.authorize(ctx, tx, before, after); err != nil {
+			return err
+		}
 		if err := s.applyUndo(ctx, tx, task.Action(op.Action), before, after); err != nil {
 			return err
 		}
@@ -105,28 +186,99 @@ func (s *Service) Undo(ctx context.Context, token string) ([]string, string, err
 			return err
 		}
 		newAction := reverseAction(task.Action(op.Action))
-		newToken, err := s.RecordOperation(ctx, tx, newAction, task.Scope(op.Scope), ids, after, before)
+		newToken, err := s.record(ctx, tx, newAction, task.Scope(op.Scope), ids, after, before,
+			activityUndo, activityPayload{Scope: task.Scope(op.Scope), Undoes: task.Action(op.Action)})
 		if err != nil {
 			return err
 		}
 		reverseToken = newToken
+		if s.recorder != nil {
+			return s.recorder.RecordEvents(ctx, tx, []events.Event{applied})
+		}
 		return nil
 	})
 	if err != nil {
 		return nil, "", err
 	}
 
+	if s.notifier != nil {
+		s.notifier.Notify()
+	}
 	return ids, reverseToken, nil
 }
 
+// usable reports why op cannot be undone by the caller at now, if it cannot.
+// Tokens recorded for another user are reported as not found.
+func usable(ctx context.Context, op *repository.TaskOperation, now time.Time) error {
+	if op == nil {
+		return ErrTokenNotFound
+	}
+	if p, ok := auth.FromContext(ctx); ok && op.OwnerID != p.UserID {
+		return ErrTokenNotFound
+	}
+	if op.IsConsumed() {
+		return ErrTokenConsumed
+	}
+	if op.IsExpired(now) {
+		return ErrTokenExpired
+	}
+	return nil
+}
+
+// TasksPurged expires the pending operations that touched purged tasks, so
+// their tokens cannot bring the tasks back.
+func (s *Service) TasksPurged(ctx context.Context, tx *gorm.DB, uuids []string) (func(), error) {
+	return nil, s.repo.ExpireByTasks(ctx, tx, uuids, time.Now())
+}
+
+// snapshotWorkspace returns the workspace of an operation's tasks; authorize
+// has made sure they share one.
+func snapshotWorkspace(before, after []task.Snapshot) uint64 {
+	for _, snaps := range [][]task.Snapshot{before, after} {
+		if len(snaps) > 0 {
+			return snaps[0].WorkspaceID
+		}
+	}
+	return 0
+}
+
+// authorize checks that the snapshots of an operation share one workspace and
+// that the caller is an editor there.
+func (s *Service) authorize(ctx context.Context, tx *gorm.DB, before, after []task.Snapshot) error {
+	if s.access == nil {
+		return nil
+	}
+	var workspaceID uint64
+	seen := false
+	for _, snaps := range [][]task.Snapshot{before, after} {
+		for _, snap := range snaps {
+			if seen && snap.WorkspaceID != workspaceID {
+				return task.ErrMixedWorkspaces
+			}
+			workspaceID, seen = snap.WorkspaceID, true
+		}
+	}
+	if !seen {
+		return nil
+	}
+	return s.access.Authorize(ctx, tx, workspaceID, workspace.RoleEditor)
+}
+
 func (s *Service) applyUndo(ctx context.Context, tx *gorm.DB, action task.Action, before, after []task.Snapshot) error {
 	switch action {
-	case task.ActionCreate:
-		return s.taskRepo.DeleteBySnapshots(ctx, tx, after)
 	case task.ActionDelete, task.ActionBulkDelete:
 		return s.taskRepo.ReplaceSnapshots(ctx, tx, before)
-	case task.ActionMove, task.ActionComplete, task.ActionUpdate, task.ActionBulkMove, task.ActionBulkComplete, task.ActionResort:
+	case task.ActionMove, task.ActionComplete, task.ActionUpdate, task.ActionBulkMove, task.ActionBulkComplete, task.ActionResort,
+		task.ActionBulkTag, task.ActionBulkUntag, task.ActionMoveProject, task.ActionReparent, task.ActionChecklist,
+		task.ActionBulkAssign:
 		return s.taskRepo.ReplaceSnapshots(ctx, tx, before)
+	case task.ActionCreate, task.ActionConvertItem:
+		// Restore what the operation changed, such as siblings moved to make
+		// room or the checklist an item came from, and drop what it created
+		if err := s.taskRepo.ReplaceSnapshots(ctx, tx, before); err != nil {
+			return err
+		}
+		return s.taskRepo.DeleteBySnapshots(ctx, tx, createdSnapshots(before, after))
 	default:
 		return errors.New("unsupported action for undo")
 	}
@@ -152,11 +304,40 @@ func reverseAction(action task.Action) task.Action {
 		return task.ActionBulkDelete
 	case task.ActionResort:
 		return task.ActionResort
+	case task.ActionBulkTag:
+		return task.ActionBulkUntag
+	case task.ActionBulkUntag:
+		return task.ActionBulkTag
+	case task.ActionMoveProject:
+		return task.ActionMoveProject
+	case task.ActionReparent:
+		return task.ActionReparent
+	case task.ActionChecklist:
+		return task.ActionChecklist
+	case task.ActionConvertItem:
+		return task.ActionConvertItem
+	case task.ActionBulkAssign:
+		return task.ActionBulkAssign
 	default:
 		return action
 	}
 }
 
+// createdSnapshots returns the snapshots in after whose task does not appear in before.
+func createdSnapshots(before, after []task.Snapshot) []task.Snapshot {
+	existed := make(map[string]struct{}, len(before))
+	for _, snap := range before {
+		existed[snap.UUID] = struct{}{}
+	}
+	var created []task.Snapshot
+	for _, snap := range after {
+		if _, ok := existed[snap.UUID]; !ok {
+			created = append(created, snap)
+		}
+	}
+	return created
+}
+
 func generateToken() string {
 	return strings.ReplaceAll(uuid.NewString(), "-", "")[:26]
 }
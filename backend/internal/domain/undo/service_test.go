@@ -0,0 +1,39 @@
+package undo
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"todolist/backend/internal/pkg/response"
+	"todolist/backend/internal/repository"
+)
+
+func TestUndoAfterPurgeIsGone(t *testing.T) {
+	recorded := time.Now()
+	op := &repository.TaskOperation{ExpireAt: recorded.Add(time.Hour)}
+	if err := usable(context.Background(), op, recorded); err != nil {
+		t.Fatalf("fresh operation: %v", err)
+	}
+
+	// ExpireByTasks moves the expiry of every operation on a purged task to
+	// the time of the purge.
+	purged := recorded.Add(time.Minute)
+	op.ExpireAt = purged
+	err := usable(context.Background(), op, purged.Add(time.Millisecond))
+	if !errors.Is(err, ErrTokenExpired) {
+		t.Fatalf("err = %v, want %v", err, ErrTokenExpired)
+	}
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	response.Error(c, err)
+	if w.Code != 410 {
+		t.Fatalf("status = %d, want 410", w.Code)
+	}
+}
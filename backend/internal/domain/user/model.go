@@ -0,0 +1,65 @@
+package user
+
+import (
+	"strings"
+	"time"
+
+	"todolist/backend/internal/pkg/auth"
+)
+
+type User struct {
+	ID           uint64    `gorm:"primaryKey;autoIncrement"`
+	Username     string    `gorm:"size:64;not null;uniqueIndex"`
+	PasswordHash string    `gorm:"size:60;not null"`
+	CreatedAt    time.Time `gorm:"not null;autoCreateTime"`
+	UpdatedAt    time.Time `gorm:"not null;autoUpdateTime"`
+}
+
+// Session is a login. Only the SHA-256 of its token is stored, so a leaked
+// table cannot be replayed as credentials.
+type Session struct {
+	TokenHash string    `gorm:"type:char(64);primaryKey"`
+	UserID    uint64    `gorm:"not null;index"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+	CreatedAt time.Time `gorm:"not null;autoCreateTime"`
+}
+
+func (Session) TableName() string {
+	return "user_sessions"
+}
+
+// APIToken is a personal access token for scripts and integrations. Like
+// sessions only its hash is stored; Prefix keeps the first characters so
+// users can tell their tokens apart. Scopes is a comma-separated list.
+type APIToken struct {
+	ID         uint64     `gorm:"primaryKey;autoIncrement"`
+	UserID     uint64     `gorm:"not null;index"`
+	Name       string     `gorm:"size:128;not null"`
+	Prefix     string     `gorm:"size:16;not null"`
+	TokenHash  string     `gorm:"type:char(64);not null;uniqueIndex"`
+	Scopes     string     `gorm:"size:64;not null"`
+	ExpiresAt  *time.Time `gorm:"type:datetime"`
+	LastUsedAt *time.Time `gorm:"type:datetime"`
+	CreatedAt  time.Time  `gorm:"not null;autoCreateTime"`
+}
+
+func (APIToken) TableName() string {
+	return "api_tokens"
+}
+
+func (t APIToken) ScopeList() []auth.Scope {
+	if t.Scopes == "" {
+		return nil
+	}
+	parts := strings.Split(t.Scopes, ",")
+	scopes := make([]auth.Scope, 0, len(parts))
+	for _, p := range parts {
+		scopes = append(scopes, auth.Scope(p))
+	}
+	return scopes
+}
+
+// IsExpired reports whether the token had an expiry and it has passed.
+func (t APIToken) IsExpired(now time.Time) bool {
+	return t.ExpiresAt != nil && now.After(*t.ExpiresAt)
+}
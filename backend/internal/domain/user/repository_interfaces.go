@@ -0,0 +1,32 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UserRepository defines the interface for user and session persistence
+type UserRepository interface {
+	DB() *gorm.DB
+	Count(ctx context.Context, tx interface{}) (int64, error)
+	GetByID(ctx context.Context, tx interface{}, id uint64) (*User, error)
+	GetByUsername(ctx context.Context, tx interface{}, username string) (*User, error)
+	Create(ctx context.Context, tx interface{}, u *User) error
+	// ClaimUnowned hands every task, undo operation, comment and activity row
+	// without an owner to userID.
+	ClaimUnowned(ctx context.Context, tx interface{}, userID uint64) error
+
+	CreateSession(ctx context.Context, tx interface{}, s *Session) error
+	GetSession(ctx context.Context, tx interface{}, tokenHash string) (*Session, error)
+	DeleteSession(ctx context.Context, tx interface{}, tokenHash string) error
+	DeleteExpiredSessions(ctx context.Context, tx interface{}, userID uint64, now time.Time) error
+
+	CreateAPIToken(ctx context.Context, tx interface{}, t *APIToken) error
+	ListAPITokens(ctx context.Context, tx interface{}, userID uint64) ([]APIToken, error)
+	GetAPIToken(ctx context.Context, tx interface{}, userID, id uint64) (*APIToken, error)
+	GetAPITokenByHash(ctx context.Context, tx interface{}, tokenHash string) (*APIToken, error)
+	DeleteAPIToken(ctx context.Context, tx interface{}, userID, id uint64) error
+	TouchAPIToken(ctx context.Context, tx interface{}, id uint64, usedAt time.Time) error
+}
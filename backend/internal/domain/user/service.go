@@ -0,0 +1,226 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	"todolist/backend/internal/pkg/auth"
+)
+
+const (
+	// SessionTokenPrefix marks session tokens so they can be told apart from
+	// other bearer credentials.
+	SessionTokenPrefix = "sess_"
+
+	DefaultSessionTTL = 30 * 24 * time.Hour
+
+	MinPasswordLength = 8
+	// MaxPasswordBytes is the most bcrypt will hash.
+	MaxPasswordBytes = 72
+)
+
+var usernamePattern = regexp.MustCompile(`^[a-zA-Z0-9_.-]{3,64}$`)
+
+var (
+	ErrInvalidUsername      = errors.New("invalid username")
+	ErrInvalidPassword      = errors.New("invalid password")
+	ErrUsernameTaken        = errors.New("username already taken")
+	ErrInvalidCredentials   = errors.New("invalid credentials")
+	ErrUnauthenticated      = errors.New("authentication required")
+	ErrRegistrationDisabled = errors.New("registration disabled")
+)
+
+type Options struct {
+	SessionTTL        time.Duration
+	AllowRegistration bool
+}
+
+type Service struct {
+	repo        UserRepository
+	opts        Options
+	provisioner Provisioner
+	logger      *zap.Logger
+}
+
+// Provisioner sets up what a user needs before first use, such as their
+// personal workspace. It must be idempotent.
+type Provisioner interface {
+	ProvisionUser(ctx context.Context, tx interface{}, userID uint64) error
+}
+
+func NewService(repo UserRepository, opts Options, logger *zap.Logger) *Service {
+	if opts.SessionTTL <= 0 {
+		opts.SessionTTL = DefaultSessionTTL
+	}
+	return &Service{repo: repo, opts: opts, logger: logger}
+}
+
+// SetProvisioner runs p for every user on registration and login.
+func (s *Service) SetProvisioner(p Provisioner) {
+	s.provisioner = p
+}
+
+// Register creates an account. The first account takes over every task,
+// undo operation and activity entry recorded before users existed.
+func (s *Service) Register(ctx context.Context, username, password string) (*User, error) {
+	if !s.opts.AllowRegistration {
+		return nil, ErrRegistrationDisabled
+	}
+	username = strings.TrimSpace(username)
+	if !usernamePattern.MatchString(username) {
+		return nil, ErrInvalidUsername
+	}
+	if utf8.RuneCountInString(password) < MinPasswordLength || len(password) > MaxPasswordBytes {
+		return nil, ErrInvalidPassword
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+	u := &User{Username: username, PasswordHash: string(hash)}
+	err = s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		existing, err := s.repo.GetByUsername(ctx, tx, username)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return ErrUsernameTaken
+		}
+		count, err := s.repo.Count(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.Create(ctx, tx, u); err != nil {
+			return err
+		}
+		if count == 0 {
+			if err := s.repo.ClaimUnowned(ctx, tx, u.ID); err != nil {
+				return err
+			}
+		}
+		return s.provision(ctx, tx, u.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// Login checks the credentials and opens a session. It returns the session
+// token, which is only ever available here.
+func (s *Service) Login(ctx context.Context, username, password string) (string, *Session, *User, error) {
+	u, err := s.repo.GetByUsername(ctx, nil, strings.TrimSpace(username))
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if u == nil {
+		return "", nil, nil, ErrInvalidCredentials
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)); err != nil {
+		return "", nil, nil, ErrInvalidCredentials
+	}
+
+	token, err := generateToken(SessionTokenPrefix)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	now := time.Now()
+	session := &Session{TokenHash: HashToken(token), UserID: u.ID, ExpiresAt: now.Add(s.opts.SessionTTL)}
+	err = s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		if err := s.repo.DeleteExpiredSessions(ctx, tx, u.ID, now); err != nil {
+			return err
+		}
+		if err := s.provision(ctx, tx, u.ID); err != nil {
+			return err
+		}
+		return s.repo.CreateSession(ctx, tx, session)
+	})
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return token, session, u, nil
+}
+
+func (s *Service) provision(ctx context.Context, tx *gorm.DB, userID uint64) error {
+	if s.provisioner == nil {
+		return nil
+	}
+	return s.provisioner.ProvisionUser(ctx, tx, userID)
+}
+
+// Logout ends the session behind token; unknown tokens are ignored.
+func (s *Service) Logout(ctx context.Context, token string) error {
+	return s.repo.DeleteSession(ctx, nil, HashToken(token))
+}
+
+// Me returns the user signed in on ctx.
+func (s *Service) Me(ctx context.Context) (*User, error) {
+	p, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+	u, err := s.repo.GetByID(ctx, nil, p.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, ErrUnauthenticated
+	}
+	return u, nil
+}
+
+// Authenticate resolves a session token or personal access token to the
+// user it was issued to.
+func (s *Service) Authenticate(ctx context.Context, token string) (auth.Principal, error) {
+	switch {
+	case strings.HasPrefix(token, SessionTokenPrefix):
+		return s.authenticateSession(ctx, token)
+	case strings.HasPrefix(token, APITokenPrefix):
+		return s.authenticateAPIToken(ctx, token)
+	default:
+		return auth.Principal{}, ErrUnauthenticated
+	}
+}
+
+func (s *Service) authenticateSession(ctx context.Context, token string) (auth.Principal, error) {
+	session, err := s.repo.GetSession(ctx, nil, HashToken(token))
+	if err != nil {
+		return auth.Principal{}, err
+	}
+	if session == nil || time.Now().After(session.ExpiresAt) {
+		return auth.Principal{}, ErrUnauthenticated
+	}
+	u, err := s.repo.GetByID(ctx, nil, session.UserID)
+	if err != nil {
+		return auth.Principal{}, err
+	}
+	if u == nil {
+		return auth.Principal{}, ErrUnauthenticated
+	}
+	return auth.Principal{UserID: u.ID, Username: u.Username, Scopes: auth.AllScopes}, nil
+}
+
+// HashToken returns the hex SHA-256 under which a token is stored.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func generateToken(prefix string) (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return prefix + hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,137 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+
+	"todolist/backend/internal/pkg/auth"
+)
+
+const (
+	// APITokenPrefix marks personal access tokens.
+	APITokenPrefix = "pat_"
+	// apiTokenDisplayLength is how much of a token is kept for display.
+	apiTokenDisplayLength = 12
+	// lastUsedResolution bounds how often last-used tracking writes to the
+	// database for a busy token.
+	lastUsedResolution = time.Minute
+)
+
+var (
+	ErrTokenNotFound     = errors.New("api token not found")
+	ErrInvalidTokenName  = errors.New("invalid token name")
+	ErrInvalidScope      = errors.New("invalid token scope")
+	ErrInvalidExpiry     = errors.New("invalid token expiry")
+	ErrInsufficientScope = errors.New("insufficient scope")
+)
+
+type CreateTokenInput struct {
+	Name      string
+	Scopes    []auth.Scope
+	ExpiresAt *time.Time
+}
+
+// CreateToken issues a personal access token for the caller. A token can
+// only carry scopes the caller holds itself. The plain token is returned once
+// and never stored.
+func (s *Service) CreateToken(ctx context.Context, input CreateTokenInput) (string, *APIToken, error) {
+	p, ok := auth.FromContext(ctx)
+	if !ok {
+		return "", nil, ErrUnauthenticated
+	}
+	name := strings.TrimSpace(input.Name)
+	if name == "" || utf8.RuneCountInString(name) > 128 {
+		return "", nil, ErrInvalidTokenName
+	}
+	if len(input.Scopes) == 0 {
+		return "", nil, ErrInvalidScope
+	}
+	seen := make(map[auth.Scope]bool, len(input.Scopes))
+	scopes := make([]string, 0, len(input.Scopes))
+	for _, scope := range input.Scopes {
+		if !auth.IsValidScope(scope) {
+			return "", nil, ErrInvalidScope
+		}
+		if !p.Has(scope) {
+			return "", nil, ErrInsufficientScope
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, string(scope))
+		}
+	}
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now()) {
+		return "", nil, ErrInvalidExpiry
+	}
+
+	token, err := generateToken(APITokenPrefix)
+	if err != nil {
+		return "", nil, err
+	}
+	t := &APIToken{
+		UserID:    p.UserID,
+		Name:      name,
+		Prefix:    token[:apiTokenDisplayLength],
+		TokenHash: HashToken(token),
+		Scopes:    strings.Join(scopes, ","),
+		ExpiresAt: input.ExpiresAt,
+	}
+	if err := s.repo.CreateAPIToken(ctx, nil, t); err != nil {
+		return "", nil, err
+	}
+	return token, t, nil
+}
+
+// ListTokens returns the caller's personal access tokens, newest first.
+func (s *Service) ListTokens(ctx context.Context) ([]APIToken, error) {
+	p, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+	return s.repo.ListAPITokens(ctx, nil, p.UserID)
+}
+
+// RevokeToken deletes one of the caller's tokens; it stops working at once.
+func (s *Service) RevokeToken(ctx context.Context, id uint64) error {
+	p, ok := auth.FromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+	t, err := s.repo.GetAPIToken(ctx, nil, p.UserID, id)
+	if err != nil {
+		return err
+	}
+	if t == nil {
+		return ErrTokenNotFound
+	}
+	return s.repo.DeleteAPIToken(ctx, nil, p.UserID, id)
+}
+
+func (s *Service) authenticateAPIToken(ctx context.Context, token string) (auth.Principal, error) {
+	t, err := s.repo.GetAPITokenByHash(ctx, nil, HashToken(token))
+	if err != nil {
+		return auth.Principal{}, err
+	}
+	now := time.Now()
+	if t == nil || t.IsExpired(now) {
+		return auth.Principal{}, ErrUnauthenticated
+	}
+	u, err := s.repo.GetByID(ctx, nil, t.UserID)
+	if err != nil {
+		return auth.Principal{}, err
+	}
+	if u == nil {
+		return auth.Principal{}, ErrUnauthenticated
+	}
+	if t.LastUsedAt == nil || now.Sub(*t.LastUsedAt) >= lastUsedResolution {
+		if err := s.repo.TouchAPIToken(ctx, nil, t.ID, now); err != nil {
+			s.logger.Warn("record api token use", zap.Uint64("token_id", t.ID), zap.Error(err))
+		}
+	}
+	return auth.Principal{UserID: u.ID, Username: u.Username, Scopes: t.ScopeList()}, nil
+}
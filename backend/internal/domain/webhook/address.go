@@ -0,0 +1,108 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrPrivateAddress rejects webhook targets inside the server's own network.
+var ErrPrivateAddress = errors.New("webhook url must point to a public address")
+
+// reservedNets are ranges that are neither private nor loopback or link-local
+// to the standard library but still do not reach the public internet.
+var reservedNets = mustParseCIDRs(
+	"0.0.0.0/8",      // "this" network
+	"100.64.0.0/10",  // carrier-grade NAT
+	"192.0.0.0/24",   // IETF protocol assignments
+	"198.18.0.0/15",  // benchmarking
+	"240.0.0.0/4",    // reserved, including broadcast
+	"64:ff9b::/96",   // NAT64, which can reach private IPv4 addresses
+	"64:ff9b:1::/48", // local-use NAT64
+	"2001:db8::/32",  // documentation
+)
+
+func mustParseCIDRs(list ...string) []*net.IPNet {
+	nets := make([]*net.IPNet, 0, len(list))
+	for _, cidr := range list {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		nets = append(nets, n)
+	}
+	return nets
+}
+
+// publicIP reports whether ip may be the target of a delivery.
+func publicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, n := range reservedNets {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkHost resolves host and fails unless every address it has is public,
+// so a subscriber cannot point a webhook at the server's own network.
+func checkHost(ctx context.Context, host string) error {
+	if ip := net.ParseIP(host); ip != nil {
+		if !publicIP(ip) {
+			return ErrPrivateAddress
+		}
+		return nil
+	}
+	if strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") {
+		return ErrPrivateAddress
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		return ErrInvalidURL
+	}
+	for _, a := range addrs {
+		if !publicIP(a.IP) {
+			return ErrPrivateAddress
+		}
+	}
+	return nil
+}
+
+// deliveryTransport dials public addresses only. The check runs on the
+// address actually connected to, after resolution, so a name that resolved
+// to a public address when the webhook was saved cannot be rebound to an
+// internal one. Proxies are not used: they would make the connection on the
+// server's behalf, out of reach of the check.
+func deliveryTransport(allowPrivate bool) *http.Transport {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+				return ErrPrivateAddress
+			}
+			return nil
+		}
+	}
+	return &http.Transport{
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+}
@@ -0,0 +1,88 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestPublicIP(t *testing.T) {
+	cases := map[string]bool{
+		"93.184.216.34":          true,
+		"2606:4700::6810:84e5":   true,
+		"127.0.0.1":              false,
+		"::1":                    false,
+		"10.1.2.3":               false,
+		"172.16.0.1":             false,
+		"192.168.1.1":            false,
+		"169.254.169.254":        false,
+		"fe80::1":                false,
+		"fd00::1":                false,
+		"0.0.0.0":                false,
+		"100.64.0.1":             false,
+		"::ffff:127.0.0.1":       false,
+		"64:ff9b::a00:1":         false,
+		"255.255.255.255":        false,
+		"::ffff:169.254.169.254": false,
+	}
+	for addr, want := range cases {
+		if got := publicIP(net.ParseIP(addr)); got != want {
+			t.Errorf("publicIP(%s) = %v, want %v", addr, got, want)
+		}
+	}
+}
+
+func TestNormalizeURLRejectsPrivateHosts(t *testing.T) {
+	s := NewService(nil, Options{}, zap.NewNop())
+	for _, raw := range []string{
+		"http://127.0.0.1/hook",
+		"http://[::1]:8080/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://10.0.0.5/hook",
+		"http://localhost:9000/hook",
+	} {
+		if _, err := s.normalizeURL(context.Background(), raw); !errors.Is(err, ErrPrivateAddress) {
+			t.Errorf("normalizeURL(%s): err = %v, want ErrPrivateAddress", raw, err)
+		}
+	}
+	if _, err := s.normalizeURL(context.Background(), "https://93.184.216.34/hook"); err != nil {
+		t.Errorf("public address rejected: %v", err)
+	}
+
+	s = NewService(nil, Options{AllowPrivateNetworks: true}, zap.NewNop())
+	if _, err := s.normalizeURL(context.Background(), "http://127.0.0.1/hook"); err != nil {
+		t.Errorf("private address rejected although allowed: %v", err)
+	}
+}
+
+// TestDeliveryRefusesPrivateAddressesWhenDialing sends to a URL that skipped
+// the check on save, as a name rebound to an internal address would.
+func TestDeliveryRefusesPrivateAddressesWhenDialing(t *testing.T) {
+	var reached atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached.Store(true)
+	}))
+	defer srv.Close()
+
+	s := NewService(nil, Options{}, zap.NewNop())
+	sub := &Subscription{URL: srv.URL, Secret: "whsec_0123456789abcdef"}
+	if _, err := s.send(context.Background(), sub, &Delivery{Payload: "{}"}, time.Now()); !errors.Is(err, ErrPrivateAddress) {
+		t.Fatalf("send: err = %v, want ErrPrivateAddress", err)
+	}
+	if reached.Load() {
+		t.Fatal("request reached the loopback server")
+	}
+
+	s = NewService(nil, Options{AllowPrivateNetworks: true}, zap.NewNop())
+	status, err := s.send(context.Background(), sub, &Delivery{Payload: "{}"}, time.Now())
+	if err != nil || status != http.StatusOK || !reached.Load() {
+		t.Fatalf("send with private networks allowed: status %d, err %v", status, err)
+	}
+}
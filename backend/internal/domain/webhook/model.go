@@ -0,0 +1,108 @@
+package webhook
+
+import (
+	"strings"
+	"time"
+)
+
+// Subscription sends the events of a workspace to URL. Events lists the event
+// types it wants, comma separated; "task.*" matches every type with that
+// prefix and an empty list matches everything. Secret signs each payload.
+type Subscription struct {
+	ID          uint64    `gorm:"primaryKey;autoIncrement"`
+	WorkspaceID uint64    `gorm:"not null;index"`
+	URL         string    `gorm:"size:2048;not null"`
+	Secret      string    `gorm:"size:255;not null"`
+	Events      string    `gorm:"size:1024;not null"`
+	Active      bool      `gorm:"not null"`
+	CreatedBy   uint64    `gorm:"not null"`
+	CreatedAt   time.Time `gorm:"not null;autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"not null;autoUpdateTime"`
+}
+
+func (Subscription) TableName() string {
+	return "webhooks"
+}
+
+// EventList returns the event filter; nil means every event.
+func (s Subscription) EventList() []string {
+	if s.Events == "" {
+		return nil
+	}
+	return strings.Split(s.Events, ",")
+}
+
+// Matches reports whether the subscription wants events of type typ.
+func (s Subscription) Matches(typ string) bool {
+	list := s.EventList()
+	if len(list) == 0 {
+		return true
+	}
+	for _, pattern := range list {
+		if pattern == "*" || pattern == typ {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok && strings.HasPrefix(typ, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+type DeliveryStatus string
+
+const (
+	StatusPending   DeliveryStatus = "pending"
+	StatusSucceeded DeliveryStatus = "succeeded"
+	StatusFailed    DeliveryStatus = "failed"
+)
+
+func IsValidStatus(s DeliveryStatus) bool {
+	switch s {
+	case StatusPending, StatusSucceeded, StatusFailed:
+		return true
+	}
+	return false
+}
+
+// Delivery is one event queued for one subscription. Pending deliveries are
+// sent once NextAttemptAt has passed and retried until they succeed or run
+// out of attempts; the row then stays as the log of the delivery. EventID is
+// shared by every delivery of the same event, redeliveries included, so
+// receivers can drop duplicates.
+type Delivery struct {
+	ID             uint64         `gorm:"primaryKey;autoIncrement"`
+	WebhookID      uint64         `gorm:"not null;index"`
+	EventID        string         `gorm:"type:char(36);not null;index"`
+	EventType      string         `gorm:"size:64;not null"`
+	Payload        string         `gorm:"type:mediumtext;not null"`
+	Status         DeliveryStatus `gorm:"type:enum('pending','succeeded','failed');not null;index:idx_webhook_deliveries_due,priority:1"`
+	Attempts       int            `gorm:"not null"`
+	NextAttemptAt  time.Time      `gorm:"not null;index:idx_webhook_deliveries_due,priority:2"`
+	LastAttemptAt  *time.Time
+	ResponseStatus int    `gorm:"not null"`
+	LastError      string `gorm:"size:1024;not null"`
+	// RedeliveryOf names the delivery this one was manually resent from.
+	RedeliveryOf *uint64
+	CreatedAt    time.Time `gorm:"not null;autoCreateTime"`
+	UpdatedAt    time.Time `gorm:"not null;autoUpdateTime"`
+}
+
+func (Delivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// Attempt records one try at sending a delivery.
+type Attempt struct {
+	ID         uint64    `gorm:"primaryKey;autoIncrement"`
+	DeliveryID uint64    `gorm:"not null;index"`
+	Number     int       `gorm:"not null"`
+	StatusCode int       `gorm:"not null"`
+	Error      string    `gorm:"size:1024;not null"`
+	DurationMS int64     `gorm:"not null"`
+	CreatedAt  time.Time `gorm:"not null;autoCreateTime"`
+}
+
+func (Attempt) TableName() string {
+	return "webhook_attempts"
+}
@@ -0,0 +1,37 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// WebhookRepository defines the interface for subscription, delivery and
+// attempt persistence
+type WebhookRepository interface {
+	DB() *gorm.DB
+	List(ctx context.Context, tx interface{}, workspaceID uint64) ([]Subscription, error)
+	ListActive(ctx context.Context, tx interface{}, workspaceIDs []uint64) ([]Subscription, error)
+	Get(ctx context.Context, tx interface{}, workspaceID, id uint64) (*Subscription, error)
+	GetByID(ctx context.Context, tx interface{}, id uint64) (*Subscription, error)
+	Create(ctx context.Context, tx interface{}, sub *Subscription) error
+	Update(ctx context.Context, tx interface{}, sub *Subscription) error
+	// Delete removes a subscription with its deliveries and their attempts.
+	Delete(ctx context.Context, tx interface{}, id uint64) error
+
+	CreateDeliveries(ctx context.Context, tx interface{}, list []Delivery) error
+	// QueuedEvents returns which of eventIDs already have deliveries queued,
+	// redeliveries aside.
+	QueuedEvents(ctx context.Context, tx interface{}, eventIDs []string) ([]string, error)
+	ListDeliveries(ctx context.Context, tx interface{}, webhookID uint64, status DeliveryStatus, limit int) ([]Delivery, error)
+	GetDelivery(ctx context.Context, tx interface{}, webhookID, id uint64) (*Delivery, error)
+	// DueDeliveries returns pending deliveries whose next attempt is due.
+	DueDeliveries(ctx context.Context, tx interface{}, now time.Time, limit int) ([]Delivery, error)
+	// ClaimDelivery pushes the next attempt of a due delivery to until and
+	// reports whether this caller got it; another worker may have been first.
+	ClaimDelivery(ctx context.Context, tx interface{}, id uint64, now, until time.Time) (bool, error)
+	// FinishAttempt stores the outcome of an attempt on its delivery.
+	FinishAttempt(ctx context.Context, tx interface{}, d *Delivery, a *Attempt) error
+	ListAttempts(ctx context.Context, tx interface{}, deliveryID uint64) ([]Attempt, error)
+}
@@ -0,0 +1,445 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"todolist/backend/internal/domain/outbox"
+	"todolist/backend/internal/domain/workspace"
+	"todolist/backend/internal/pkg/auth"
+)
+
+const (
+	DefaultTimeout      = 10 * time.Second
+	DefaultMaxAttempts  = 8
+	DefaultBackoffBase  = 30 * time.Second
+	DefaultBackoffMax   = 6 * time.Hour
+	DefaultPollInterval = 2 * time.Second
+
+	// SecretPrefix marks generated signing secrets.
+	SecretPrefix = "whsec_"
+
+	maxURLLength      = 2048
+	minSecretLength   = 16
+	maxSecretLength   = 255
+	maxEventFilters   = 32
+	maxDeliveryList   = 100
+	defaultBatchSize  = 20
+	deliveryWorkers   = 4
+	maxResponseLength = 1024
+)
+
+var (
+	ErrWebhookNotFound  = errors.New("webhook not found")
+	ErrDeliveryNotFound = errors.New("delivery not found")
+	ErrInvalidURL       = errors.New("invalid webhook url")
+	ErrInvalidSecret    = errors.New("invalid webhook secret")
+	ErrInvalidEvents    = errors.New("invalid webhook events")
+	ErrInvalidStatus    = errors.New("invalid delivery status")
+	ErrInactive         = errors.New("webhook is inactive")
+)
+
+// eventPattern accepts an event type, a prefix ending in "*" or "*" alone.
+var eventPattern = regexp.MustCompile(`^(\*|[a-z]+(\.[a-z_]+)*(\.\*)?)$`)
+
+// Authorizer checks what the caller may do in a workspace.
+type Authorizer interface {
+	Authorize(ctx context.Context, tx interface{}, workspaceID uint64, role workspace.Role) error
+}
+
+type Options struct {
+	// Timeout bounds a single delivery attempt.
+	Timeout time.Duration
+	// MaxAttempts is how often a delivery is tried before it is given up.
+	MaxAttempts int
+	// BackoffBase is the wait after the first failed attempt; it doubles with
+	// every further failure up to BackoffMax.
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+	// PollInterval is how often the worker looks for due deliveries.
+	PollInterval time.Duration
+	// AllowPrivateNetworks lets webhooks target loopback, private and
+	// link-local addresses, e.g. for local development.
+	AllowPrivateNetworks bool
+}
+
+type Service struct {
+	repo   WebhookRepository
+	access Authorizer
+	client *http.Client
+	opts   Options
+	logger *zap.Logger
+}
+
+func NewService(repo WebhookRepository, opts Options, logger *zap.Logger) *Service {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = DefaultMaxAttempts
+	}
+	if opts.BackoffBase <= 0 {
+		opts.BackoffBase = DefaultBackoffBase
+	}
+	if opts.BackoffMax < opts.BackoffBase {
+		opts.BackoffMax = DefaultBackoffMax
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultPollInterval
+	}
+	client := &http.Client{
+		Timeout:   opts.Timeout,
+		Transport: deliveryTransport(opts.AllowPrivateNetworks),
+		// A redirect is reported as the response it is instead of being
+		// followed to a host the subscriber did not name.
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return &Service{repo: repo, client: client, opts: opts, logger: logger}
+}
+
+// SetAuthorizer enables role checks: webhooks are managed by workspace
+// owners only.
+func (s *Service) SetAuthorizer(a Authorizer) {
+	s.access = a
+}
+
+type CreateInput struct {
+	URL string
+	// Secret is generated when empty.
+	Secret string
+	Events []string
+	Active *bool
+}
+
+type UpdateInput struct {
+	URL    *string
+	Secret *string
+	Events *[]string
+	Active *bool
+}
+
+func (s *Service) List(ctx context.Context, workspaceID uint64) ([]Subscription, error) {
+	if err := s.authorize(ctx, nil, workspaceID); err != nil {
+		return nil, err
+	}
+	return s.repo.List(ctx, nil, workspaceID)
+}
+
+func (s *Service) Get(ctx context.Context, workspaceID, id uint64) (*Subscription, error) {
+	if err := s.authorize(ctx, nil, workspaceID); err != nil {
+		return nil, err
+	}
+	return s.get(ctx, nil, workspaceID, id)
+}
+
+func (s *Service) Create(ctx context.Context, workspaceID uint64, input CreateInput) (*Subscription, error) {
+	if err := s.authorize(ctx, nil, workspaceID); err != nil {
+		return nil, err
+	}
+	target, err := s.normalizeURL(ctx, input.URL)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := normalizeSecret(input.Secret)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := normalizeEvents(input.Events)
+	if err != nil {
+		return nil, err
+	}
+	sub := &Subscription{
+		WorkspaceID: workspaceID,
+		URL:         target,
+		Secret:      secret,
+		Events:      filter,
+		Active:      input.Active == nil || *input.Active,
+		CreatedBy:   auth.OwnerID(ctx),
+	}
+	if err := s.repo.Create(ctx, nil, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+func (s *Service) Update(ctx context.Context, workspaceID, id uint64, input UpdateInput) (*Subscription, error) {
+	if err := s.authorize(ctx, nil, workspaceID); err != nil {
+		return nil, err
+	}
+	sub, err := s.get(ctx, nil, workspaceID, id)
+	if err != nil {
+		return nil, err
+	}
+	if input.URL != nil {
+		if sub.URL, err = s.normalizeURL(ctx, *input.URL); err != nil {
+			return nil, err
+		}
+	}
+	if input.Secret != nil {
+		if sub.Secret, err = normalizeSecret(*input.Secret); err != nil {
+			return nil, err
+		}
+	}
+	if input.Events != nil {
+		if sub.Events, err = normalizeEvents(*input.Events); err != nil {
+			return nil, err
+		}
+	}
+	if input.Active != nil {
+		sub.Active = *input.Active
+	}
+	if err := s.repo.Update(ctx, nil, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// Delete removes a subscription together with its delivery log.
+func (s *Service) Delete(ctx context.Context, workspaceID, id uint64) error {
+	return s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		if err := s.authorize(ctx, tx, workspaceID); err != nil {
+			return err
+		}
+		if _, err := s.get(ctx, tx, workspaceID, id); err != nil {
+			return err
+		}
+		return s.repo.Delete(ctx, tx, id)
+	})
+}
+
+// Deliveries returns the latest deliveries of a subscription, newest first,
+// optionally only those in status.
+func (s *Service) Deliveries(ctx context.Context, workspaceID, id uint64, status DeliveryStatus, limit int) ([]Delivery, error) {
+	if status != "" && !IsValidStatus(status) {
+		return nil, ErrInvalidStatus
+	}
+	if limit <= 0 || limit > maxDeliveryList {
+		limit = maxDeliveryList
+	}
+	if err := s.authorize(ctx, nil, workspaceID); err != nil {
+		return nil, err
+	}
+	if _, err := s.get(ctx, nil, workspaceID, id); err != nil {
+		return nil, err
+	}
+	return s.repo.ListDeliveries(ctx, nil, id, status, limit)
+}
+
+// Delivery returns one delivery with the log of its attempts.
+func (s *Service) Delivery(ctx context.Context, workspaceID, id, deliveryID uint64) (*Delivery, []Attempt, error) {
+	if err := s.authorize(ctx, nil, workspaceID); err != nil {
+		return nil, nil, err
+	}
+	if _, err := s.get(ctx, nil, workspaceID, id); err != nil {
+		return nil, nil, err
+	}
+	d, err := s.repo.GetDelivery(ctx, nil, id, deliveryID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if d == nil {
+		return nil, nil, ErrDeliveryNotFound
+	}
+	attempts, err := s.repo.ListAttempts(ctx, nil, d.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return d, attempts, nil
+}
+
+// Redeliver queues the payload of a past delivery again as a new delivery
+// with the same event ID; the original keeps its log.
+func (s *Service) Redeliver(ctx context.Context, workspaceID, id, deliveryID uint64) (*Delivery, error) {
+	var redelivery *Delivery
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		if err := s.authorize(ctx, tx, workspaceID); err != nil {
+			return err
+		}
+		sub, err := s.get(ctx, tx, workspaceID, id)
+		if err != nil {
+			return err
+		}
+		if !sub.Active {
+			return ErrInactive
+		}
+		original, err := s.repo.GetDelivery(ctx, tx, id, deliveryID)
+		if err != nil {
+			return err
+		}
+		if original == nil {
+			return ErrDeliveryNotFound
+		}
+		list := []Delivery{{
+			WebhookID:     id,
+			EventID:       original.EventID,
+			EventType:     original.EventType,
+			Payload:       original.Payload,
+			Status:        StatusPending,
+			NextAttemptAt: time.Now(),
+			RedeliveryOf:  &original.ID,
+		}}
+		if err := s.repo.CreateDeliveries(ctx, tx, list); err != nil {
+			return err
+		}
+		redelivery = &list[0]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return redelivery, nil
+}
+
+// HandleMessages queues a delivery of each outbox message for every active
+// subscription of its workspace that wants it. Messages seen before, which
+// the outbox hands over again after a failure or a replay, are skipped; a
+// manual redelivery is the way to send an event twice.
+func (s *Service) HandleMessages(ctx context.Context, msgs []outbox.Message) error {
+	var workspaceIDs []uint64
+	var eventIDs []string
+	seen := make(map[uint64]bool)
+	for _, m := range msgs {
+		eventIDs = append(eventIDs, m.EventID)
+		if m.WorkspaceID != 0 && !seen[m.WorkspaceID] {
+			seen[m.WorkspaceID] = true
+			workspaceIDs = append(workspaceIDs, m.WorkspaceID)
+		}
+	}
+	if len(workspaceIDs) == 0 {
+		return nil
+	}
+
+	return s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		subs, err := s.repo.ListActive(ctx, tx, workspaceIDs)
+		if err != nil || len(subs) == 0 {
+			return err
+		}
+		queued, err := s.repo.QueuedEvents(ctx, tx, eventIDs)
+		if err != nil {
+			return err
+		}
+		done := make(map[string]bool, len(queued))
+		for _, id := range queued {
+			done[id] = true
+		}
+
+		now := time.Now()
+		var deliveries []Delivery
+		for _, m := range msgs {
+			if done[m.EventID] {
+				continue
+			}
+			var body []byte
+			for _, sub := range subs {
+				if sub.WorkspaceID != m.WorkspaceID || !sub.Matches(m.Type) {
+					continue
+				}
+				if body == nil {
+					if body, err = json.Marshal(m.Envelope()); err != nil {
+						return err
+					}
+				}
+				deliveries = append(deliveries, Delivery{
+					WebhookID:     sub.ID,
+					EventID:       m.EventID,
+					EventType:     m.Type,
+					Payload:       string(body),
+					Status:        StatusPending,
+					NextAttemptAt: now,
+				})
+			}
+		}
+		return s.repo.CreateDeliveries(ctx, tx, deliveries)
+	})
+}
+
+func (s *Service) get(ctx context.Context, tx interface{}, workspaceID, id uint64) (*Subscription, error) {
+	sub, err := s.repo.Get(ctx, tx, workspaceID, id)
+	if err != nil {
+		return nil, err
+	}
+	if sub == nil {
+		return nil, ErrWebhookNotFound
+	}
+	return sub, nil
+}
+
+func (s *Service) authorize(ctx context.Context, tx interface{}, workspaceID uint64) error {
+	if s.access == nil {
+		return nil
+	}
+	return s.access.Authorize(ctx, tx, workspaceID, workspace.RoleOwner)
+}
+
+// normalizeURL accepts absolute http and https URLs of hosts with public
+// addresses only. Deliveries check the address again when they connect.
+func (s *Service) normalizeURL(ctx context.Context, raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || len(raw) > maxURLLength {
+		return "", ErrInvalidURL
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" || u.User != nil {
+		return "", ErrInvalidURL
+	}
+	if !s.opts.AllowPrivateNetworks {
+		if err := checkHost(ctx, u.Hostname()); err != nil {
+			return "", err
+		}
+	}
+	return u.String(), nil
+}
+
+func normalizeSecret(secret string) (string, error) {
+	secret = strings.TrimSpace(secret)
+	if secret == "" {
+		return generateSecret(), nil
+	}
+	if len(secret) < minSecretLength || len(secret) > maxSecretLength {
+		return "", ErrInvalidSecret
+	}
+	return secret, nil
+}
+
+// normalizeEvents validates an event filter and joins it for storage. A
+// wildcard subsumes the rest of the list.
+func normalizeEvents(list []string) (string, error) {
+	if len(list) > maxEventFilters {
+		return "", ErrInvalidEvents
+	}
+	seen := make(map[string]bool, len(list))
+	result := make([]string, 0, len(list))
+	for _, e := range list {
+		e = strings.TrimSpace(e)
+		if !eventPattern.MatchString(e) {
+			return "", ErrInvalidEvents
+		}
+		if e == "*" {
+			return "", nil
+		}
+		if !seen[e] {
+			seen[e] = true
+			result = append(result, e)
+		}
+	}
+	return strings.Join(result, ","), nil
+}
+
+func generateSecret() string {
+	b := make([]byte, 24)
+	_, _ = rand.Read(b)
+	return SecretPrefix + hex.EncodeToString(b)
+}
@@ -0,0 +1,197 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Headers sent with every delivery. The signature is
+// "sha256=" + hex(HMAC-SHA256(secret, timestamp + "." + body)); receivers
+// should recompute it and reject stale timestamps.
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderEventID   = "X-Webhook-Event-Id"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+
+	userAgent = "todolist-webhooks/1"
+)
+
+// Signature signs body as sent at timestamp, in Unix seconds, with secret.
+func Signature(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Run sends due deliveries until ctx is cancelled. Several processes may run
+// it against the same database; each delivery is claimed by one of them.
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.opts.PollInterval)
+	defer ticker.Stop()
+	for {
+		// A full batch suggests a backlog, so look again without waiting.
+		if s.deliverDue(ctx) == defaultBatchSize {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliverDue sends one batch of due deliveries and returns how many it
+// claimed.
+func (s *Service) deliverDue(ctx context.Context) int {
+	if ctx.Err() != nil {
+		return 0
+	}
+	now := time.Now()
+	due, err := s.repo.DueDeliveries(ctx, nil, now, defaultBatchSize)
+	if err != nil {
+		if ctx.Err() == nil {
+			s.logger.Warn("load due webhook deliveries failed", zap.Error(err))
+		}
+		return 0
+	}
+
+	// A claim lasts longer than an attempt can take, so a delivery whose
+	// worker died is picked up again once the claim runs out.
+	until := now.Add(s.opts.Timeout + time.Minute)
+	sem := make(chan struct{}, deliveryWorkers)
+	var wg sync.WaitGroup
+	sent := 0
+	for i := range due {
+		d := &due[i]
+		claimed, err := s.repo.ClaimDelivery(ctx, nil, d.ID, now, until)
+		if err != nil {
+			s.logger.Warn("claim webhook delivery failed", zap.Uint64("delivery_id", d.ID), zap.Error(err))
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		sent++
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			s.attempt(ctx, d)
+		}()
+	}
+	wg.Wait()
+	return sent
+}
+
+// attempt sends d once and records the outcome: success, a retry after the
+// backoff, or failure once MaxAttempts is reached.
+func (s *Service) attempt(ctx context.Context, d *Delivery) {
+	sub, err := s.repo.GetByID(ctx, nil, d.WebhookID)
+	if err != nil {
+		s.logger.Warn("load webhook failed", zap.Uint64("webhook_id", d.WebhookID), zap.Error(err))
+		return
+	}
+
+	started := time.Now()
+	a := &Attempt{DeliveryID: d.ID, Number: d.Attempts + 1}
+	switch {
+	case sub == nil:
+		a.Error = "webhook removed"
+	case !sub.Active:
+		a.Error = ErrInactive.Error()
+	default:
+		a.StatusCode, err = s.send(ctx, sub, d, started)
+		if err != nil {
+			a.Error = err.Error()
+		} else if a.StatusCode < 200 || a.StatusCode > 299 {
+			a.Error = fmt.Sprintf("unexpected status %d", a.StatusCode)
+		}
+	}
+	if ctx.Err() != nil {
+		// Shutting down: leave the claim to run out so the attempt is
+		// retried rather than counted.
+		return
+	}
+	a.DurationMS = time.Since(started).Milliseconds()
+	a.Error = truncate(a.Error, maxResponseLength)
+
+	d.Attempts = a.Number
+	d.LastAttemptAt = &started
+	d.ResponseStatus = a.StatusCode
+	d.LastError = a.Error
+	switch {
+	case a.Error == "":
+		d.Status = StatusSucceeded
+	case sub == nil || !sub.Active || d.Attempts >= s.opts.MaxAttempts:
+		d.Status = StatusFailed
+	default:
+		d.NextAttemptAt = time.Now().Add(s.backoff(d.Attempts))
+	}
+	if err := s.repo.FinishAttempt(context.WithoutCancel(ctx), nil, d, a); err != nil {
+		s.logger.Warn("record webhook attempt failed", zap.Uint64("delivery_id", d.ID), zap.Error(err))
+	}
+}
+
+// send POSTs the payload of d to the subscription and returns the response
+// status. The response body is discarded: subscribers choose the URL, so
+// showing what it returned would let them read from any server it reaches.
+func (s *Service) send(ctx context.Context, sub *Subscription, d *Delivery, at time.Time) (int, error) {
+	body := []byte(d.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := at.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(HeaderEvent, d.EventType)
+	req.Header.Set(HeaderEventID, d.EventID)
+	req.Header.Set(HeaderDelivery, strconv.FormatUint(d.ID, 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Signature(sub.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Drain a little so the connection can be reused.
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseLength))
+	return resp.StatusCode, nil
+}
+
+// backoff is the wait after the given number of failed attempts.
+func (s *Service) backoff(attempts int) time.Duration {
+	wait := s.opts.BackoffBase
+	for i := 1; i < attempts && wait < s.opts.BackoffMax; i++ {
+		wait *= 2
+	}
+	if wait > s.opts.BackoffMax {
+		wait = s.opts.BackoffMax
+	}
+	return wait
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
@@ -0,0 +1,83 @@
+package workspace
+
+import "time"
+
+// Role is a member's permission level in a workspace. Each role includes the
+// ones below it: owners manage the workspace and its members, editors change
+// tasks and projects, viewers only read.
+type Role string
+
+const (
+	RoleOwner  Role = "owner"
+	RoleEditor Role = "editor"
+	RoleViewer Role = "viewer"
+)
+
+var roleRank = map[Role]int{RoleViewer: 1, RoleEditor: 2, RoleOwner: 3}
+
+func IsValidRole(r Role) bool {
+	_, ok := roleRank[r]
+	return ok
+}
+
+// Allows reports whether r grants at least min.
+func (r Role) Allows(min Role) bool {
+	return roleRank[r] >= roleRank[min]
+}
+
+// Workspace groups tasks and projects shared by its members. Every user has
+// one personal workspace, created on first login; PersonalOf names that user
+// and is unique so concurrent first requests cannot create two.
+type Workspace struct {
+	ID         uint64    `gorm:"primaryKey;autoIncrement"`
+	Name       string    `gorm:"size:128;not null"`
+	PersonalOf *uint64   `gorm:"uniqueIndex"`
+	CreatedBy  uint64    `gorm:"not null;index"`
+	CreatedAt  time.Time `gorm:"not null;autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"not null;autoUpdateTime"`
+
+	// Role is the caller's role, filled in by the repository on read.
+	Role Role `gorm:"->;-:migration"`
+}
+
+func (w Workspace) IsPersonal() bool {
+	return w.PersonalOf != nil
+}
+
+type Member struct {
+	WorkspaceID uint64    `gorm:"primaryKey"`
+	UserID      uint64    `gorm:"primaryKey;index"`
+	Role        Role      `gorm:"type:enum('owner','editor','viewer');not null"`
+	CreatedAt   time.Time `gorm:"not null;autoCreateTime"`
+
+	// Username is filled in by the repository on read.
+	Username string `gorm:"->;-:migration"`
+}
+
+func (Member) TableName() string {
+	return "workspace_members"
+}
+
+// Invitation offers a user membership with Role until it expires or is
+// accepted, declined or revoked.
+type Invitation struct {
+	ID          uint64    `gorm:"primaryKey;autoIncrement"`
+	WorkspaceID uint64    `gorm:"not null;uniqueIndex:idx_workspace_invitee"`
+	InviteeID   uint64    `gorm:"not null;uniqueIndex:idx_workspace_invitee;index"`
+	Role        Role      `gorm:"type:enum('owner','editor','viewer');not null"`
+	InvitedBy   uint64    `gorm:"not null"`
+	ExpiresAt   time.Time `gorm:"not null"`
+	CreatedAt   time.Time `gorm:"not null;autoCreateTime"`
+
+	// Filled in by the repository on read.
+	WorkspaceName   string `gorm:"->;-:migration"`
+	InviteeUsername string `gorm:"->;-:migration"`
+}
+
+func (Invitation) TableName() string {
+	return "workspace_invitations"
+}
+
+func (i Invitation) IsExpired(now time.Time) bool {
+	return now.After(i.ExpiresAt)
+}
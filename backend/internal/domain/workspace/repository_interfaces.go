@@ -0,0 +1,39 @@
+package workspace
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// WorkspaceRepository defines the interface for workspace, membership and
+// invitation persistence
+type WorkspaceRepository interface {
+	DB() *gorm.DB
+	ListForUser(ctx context.Context, tx interface{}, userID uint64) ([]Workspace, error)
+	GetForUser(ctx context.Context, tx interface{}, id, userID uint64) (*Workspace, error)
+	GetPersonal(ctx context.Context, tx interface{}, userID uint64) (*Workspace, error)
+	Create(ctx context.Context, tx interface{}, w *Workspace) error
+	Update(ctx context.Context, tx interface{}, w *Workspace) error
+	Delete(ctx context.Context, tx interface{}, id uint64) error
+	// CountContents counts the tasks, trashed ones included, and projects in a workspace.
+	CountContents(ctx context.Context, tx interface{}, id uint64) (int64, error)
+	// AdoptLegacy moves tasks and time entries recorded for userID before
+	// workspaces existed, and the projects, tags and custom fields those tasks
+	// use, into id. Ones no task of userID uses are left for their users.
+	AdoptLegacy(ctx context.Context, tx interface{}, id, userID uint64) error
+
+	GetMember(ctx context.Context, tx interface{}, id, userID uint64) (*Member, error)
+	ListMembers(ctx context.Context, tx interface{}, id uint64) ([]Member, error)
+	CountOwners(ctx context.Context, tx interface{}, id uint64) (int64, error)
+	SaveMember(ctx context.Context, tx interface{}, m *Member) error
+	DeleteMember(ctx context.Context, tx interface{}, id, userID uint64) error
+
+	FindUserID(ctx context.Context, tx interface{}, username string) (uint64, error)
+	CreateInvitation(ctx context.Context, tx interface{}, inv *Invitation) error
+	GetInvitation(ctx context.Context, tx interface{}, id uint64) (*Invitation, error)
+	FindInvitation(ctx context.Context, tx interface{}, id, inviteeID uint64) (*Invitation, error)
+	ListInvitations(ctx context.Context, tx interface{}, id uint64) ([]Invitation, error)
+	ListInvitationsFor(ctx context.Context, tx interface{}, inviteeID uint64) ([]Invitation, error)
+	DeleteInvitation(ctx context.Context, tx interface{}, invitationID uint64) error
+}
@@ -0,0 +1,464 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"todolist/backend/internal/pkg/auth"
+)
+
+const (
+	PersonalName         = "Personal"
+	DefaultInvitationTTL = 7 * 24 * time.Hour
+)
+
+var (
+	ErrWorkspaceNotFound  = errors.New("workspace not found")
+	ErrInsufficientRole   = errors.New("insufficient role")
+	ErrInvalidName        = errors.New("invalid workspace name")
+	ErrInvalidRole        = errors.New("invalid role")
+	ErrWorkspaceNotEmpty  = errors.New("workspace is not empty")
+	ErrPersonalWorkspace  = errors.New("personal workspace cannot be deleted")
+	ErrLastOwner          = errors.New("workspace needs an owner")
+	ErrMemberNotFound     = errors.New("member not found")
+	ErrUserNotFound       = errors.New("user not found")
+	ErrAlreadyMember      = errors.New("user is already a member")
+	ErrAlreadyInvited     = errors.New("user is already invited")
+	ErrInvitationNotFound = errors.New("invitation not found")
+	ErrNotAuthenticated   = errors.New("authentication required")
+)
+
+type Options struct {
+	InvitationTTL time.Duration
+}
+
+type Service struct {
+	repo   WorkspaceRepository
+	opts   Options
+	logger *zap.Logger
+}
+
+func NewService(repo WorkspaceRepository, opts Options, logger *zap.Logger) *Service {
+	if opts.InvitationTTL <= 0 {
+		opts.InvitationTTL = DefaultInvitationTTL
+	}
+	return &Service{repo: repo, opts: opts, logger: logger}
+}
+
+// List returns the caller's workspaces with the caller's role in each.
+func (s *Service) List(ctx context.Context) ([]Workspace, error) {
+	p, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, ErrNotAuthenticated
+	}
+	if _, err := s.EnsurePersonal(ctx, nil, p.UserID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListForUser(ctx, nil, p.UserID)
+}
+
+func (s *Service) Get(ctx context.Context, id uint64) (*Workspace, error) {
+	return s.member(ctx, nil, id, RoleViewer)
+}
+
+func (s *Service) Create(ctx context.Context, name string) (*Workspace, error) {
+	p, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, ErrNotAuthenticated
+	}
+	name, err := normalizeName(name)
+	if err != nil {
+		return nil, err
+	}
+	w := &Workspace{Name: name, CreatedBy: p.UserID, Role: RoleOwner}
+	err = s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		if err := s.repo.Create(ctx, tx, w); err != nil {
+			return err
+		}
+		return s.repo.SaveMember(ctx, tx, &Member{WorkspaceID: w.ID, UserID: p.UserID, Role: RoleOwner})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (s *Service) Rename(ctx context.Context, id uint64, name string) (*Workspace, error) {
+	name, err := normalizeName(name)
+	if err != nil {
+		return nil, err
+	}
+	var updated *Workspace
+	err = s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		w, err := s.member(ctx, tx, id, RoleOwner)
+		if err != nil {
+			return err
+		}
+		w.Name = name
+		if err := s.repo.Update(ctx, tx, w); err != nil {
+			return err
+		}
+		updated = w
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// Delete removes an empty shared workspace together with its memberships
+// and invitations. Personal workspaces stay.
+func (s *Service) Delete(ctx context.Context, id uint64) error {
+	return s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		w, err := s.member(ctx, tx, id, RoleOwner)
+		if err != nil {
+			return err
+		}
+		if w.IsPersonal() {
+			return ErrPersonalWorkspace
+		}
+		count, err := s.repo.CountContents(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrWorkspaceNotEmpty
+		}
+		return s.repo.Delete(ctx, tx, id)
+	})
+}
+
+func (s *Service) Members(ctx context.Context, id uint64) ([]Member, error) {
+	if _, err := s.member(ctx, nil, id, RoleViewer); err != nil {
+		return nil, err
+	}
+	return s.repo.ListMembers(ctx, nil, id)
+}
+
+// SetRole changes a member's role. A workspace always keeps at least one owner.
+func (s *Service) SetRole(ctx context.Context, id, userID uint64, role Role) (*Member, error) {
+	if !IsValidRole(role) {
+		return nil, ErrInvalidRole
+	}
+	var updated *Member
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		if _, err := s.member(ctx, tx, id, RoleOwner); err != nil {
+			return err
+		}
+		m, err := s.repo.GetMember(ctx, tx, id, userID)
+		if err != nil {
+			return err
+		}
+		if m == nil {
+			return ErrMemberNotFound
+		}
+		if m.Role == RoleOwner && role != RoleOwner {
+			if err := s.keepOwner(ctx, tx, id); err != nil {
+				return err
+			}
+		}
+		m.Role = role
+		if err := s.repo.SaveMember(ctx, tx, m); err != nil {
+			return err
+		}
+		updated = m
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// RemoveMember takes a user out of a workspace. Owners may remove anyone;
+// every member may leave.
+func (s *Service) RemoveMember(ctx context.Context, id, userID uint64) error {
+	return s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		minRole := RoleOwner
+		if auth.OwnerID(ctx) == userID {
+			minRole = RoleViewer
+		}
+		if _, err := s.member(ctx, tx, id, minRole); err != nil {
+			return err
+		}
+		m, err := s.repo.GetMember(ctx, tx, id, userID)
+		if err != nil {
+			return err
+		}
+		if m == nil {
+			return ErrMemberNotFound
+		}
+		if m.Role == RoleOwner {
+			if err := s.keepOwner(ctx, tx, id); err != nil {
+				return err
+			}
+		}
+		return s.repo.DeleteMember(ctx, tx, id, userID)
+	})
+}
+
+// Invite offers username membership with role. Only owners may invite.
+func (s *Service) Invite(ctx context.Context, id uint64, username string, role Role) (*Invitation, error) {
+	if !IsValidRole(role) {
+		return nil, ErrInvalidRole
+	}
+	var inv *Invitation
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		w, err := s.member(ctx, tx, id, RoleOwner)
+		if err != nil {
+			return err
+		}
+		inviteeID, err := s.repo.FindUserID(ctx, tx, strings.TrimSpace(username))
+		if err != nil {
+			return err
+		}
+		if inviteeID == 0 {
+			return ErrUserNotFound
+		}
+		existing, err := s.repo.GetMember(ctx, tx, id, inviteeID)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return ErrAlreadyMember
+		}
+		pending, err := s.repo.FindInvitation(ctx, tx, id, inviteeID)
+		if err != nil {
+			return err
+		}
+		if pending != nil {
+			if !pending.IsExpired(time.Now()) {
+				return ErrAlreadyInvited
+			}
+			if err := s.repo.DeleteInvitation(ctx, tx, pending.ID); err != nil {
+				return err
+			}
+		}
+		inv = &Invitation{
+			WorkspaceID:     id,
+			InviteeID:       inviteeID,
+			Role:            role,
+			InvitedBy:       auth.OwnerID(ctx),
+			ExpiresAt:       time.Now().Add(s.opts.InvitationTTL),
+			WorkspaceName:   w.Name,
+			InviteeUsername: strings.TrimSpace(username),
+		}
+		return s.repo.CreateInvitation(ctx, tx, inv)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return inv, nil
+}
+
+// Invitations lists the open invitations of a workspace to its owners.
+func (s *Service) Invitations(ctx context.Context, id uint64) ([]Invitation, error) {
+	if _, err := s.member(ctx, nil, id, RoleOwner); err != nil {
+		return nil, err
+	}
+	return s.repo.ListInvitations(ctx, nil, id)
+}
+
+func (s *Service) RevokeInvitation(ctx context.Context, id, invitationID uint64) error {
+	return s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		if _, err := s.member(ctx, tx, id, RoleOwner); err != nil {
+			return err
+		}
+		inv, err := s.repo.GetInvitation(ctx, tx, invitationID)
+		if err != nil {
+			return err
+		}
+		if inv == nil || inv.WorkspaceID != id {
+			return ErrInvitationNotFound
+		}
+		return s.repo.DeleteInvitation(ctx, tx, invitationID)
+	})
+}
+
+// PendingInvitations lists the invitations addressed to the caller that have
+// not expired.
+func (s *Service) PendingInvitations(ctx context.Context) ([]Invitation, error) {
+	p, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, ErrNotAuthenticated
+	}
+	return s.repo.ListInvitationsFor(ctx, nil, p.UserID)
+}
+
+// AcceptInvitation makes the caller a member with the invited role.
+func (s *Service) AcceptInvitation(ctx context.Context, invitationID uint64) (*Workspace, error) {
+	var joined *Workspace
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		inv, err := s.ownInvitation(ctx, tx, invitationID)
+		if err != nil {
+			return err
+		}
+		if inv.IsExpired(time.Now()) {
+			return ErrInvitationNotFound
+		}
+		if err := s.repo.DeleteInvitation(ctx, tx, inv.ID); err != nil {
+			return err
+		}
+		m := &Member{WorkspaceID: inv.WorkspaceID, UserID: inv.InviteeID, Role: inv.Role}
+		if err := s.repo.SaveMember(ctx, tx, m); err != nil {
+			return err
+		}
+		joined, err = s.repo.GetForUser(ctx, tx, inv.WorkspaceID, inv.InviteeID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if joined == nil {
+		return nil, ErrWorkspaceNotFound
+	}
+	return joined, nil
+}
+
+func (s *Service) DeclineInvitation(ctx context.Context, invitationID uint64) error {
+	return s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		inv, err := s.ownInvitation(ctx, tx, invitationID)
+		if err != nil {
+			return err
+		}
+		return s.repo.DeleteInvitation(ctx, tx, inv.ID)
+	})
+}
+
+// EnsurePersonal returns the personal workspace of userID, creating it on
+// first use. The first personal workspace also takes over everything
+// recorded before workspaces existed.
+func (s *Service) EnsurePersonal(ctx context.Context, tx interface{}, userID uint64) (uint64, error) {
+	w, err := s.repo.GetPersonal(ctx, tx, userID)
+	if err != nil {
+		return 0, err
+	}
+	if w != nil {
+		return w.ID, nil
+	}
+	create := func(tx *gorm.DB) error {
+		w = &Workspace{Name: PersonalName, PersonalOf: &userID, CreatedBy: userID}
+		if err := s.repo.Create(ctx, tx, w); err != nil {
+			return err
+		}
+		if err := s.repo.SaveMember(ctx, tx, &Member{WorkspaceID: w.ID, UserID: userID, Role: RoleOwner}); err != nil {
+			return err
+		}
+		return s.repo.AdoptLegacy(ctx, tx, w.ID, userID)
+	}
+	if db, ok := tx.(*gorm.DB); ok && db != nil {
+		err = create(db)
+	} else {
+		err = s.repo.DB().Transaction(create)
+	}
+	if err != nil {
+		// A concurrent request may have created it first.
+		if existing, getErr := s.repo.GetPersonal(ctx, nil, userID); getErr == nil && existing != nil {
+			return existing.ID, nil
+		}
+		return 0, err
+	}
+	return w.ID, nil
+}
+
+// ProvisionUser gives a new or returning user their personal workspace.
+func (s *Service) ProvisionUser(ctx context.Context, tx interface{}, userID uint64) error {
+	_, err := s.EnsurePersonal(ctx, tx, userID)
+	return err
+}
+
+// Authorize fails unless the caller holds at least role in the workspace.
+// Workspaces the caller does not belong to are reported as not found.
+// Internal callers marked with auth.WithSystem pass; contexts without a
+// principal fail.
+func (s *Service) Authorize(ctx context.Context, tx interface{}, workspaceID uint64, role Role) error {
+	if auth.IsSystem(ctx) {
+		return nil
+	}
+	if _, ok := auth.FromContext(ctx); !ok {
+		return ErrNotAuthenticated
+	}
+	_, err := s.member(ctx, tx, workspaceID, role)
+	return err
+}
+
+// DefaultWorkspace returns the workspace new items go to when the caller
+// names none: their personal one.
+func (s *Service) DefaultWorkspace(ctx context.Context, tx interface{}) (uint64, error) {
+	p, ok := auth.FromContext(ctx)
+	if !ok {
+		return 0, nil
+	}
+	return s.EnsurePersonal(ctx, tx, p.UserID)
+}
+
+// IsMember reports whether userID belongs to the workspace, in any role.
+func (s *Service) IsMember(ctx context.Context, tx interface{}, workspaceID, userID uint64) (bool, error) {
+	m, err := s.repo.GetMember(ctx, tx, workspaceID, userID)
+	if err != nil {
+		return false, err
+	}
+	return m != nil, nil
+}
+
+// member loads a workspace the caller belongs to with at least min.
+func (s *Service) member(ctx context.Context, tx interface{}, id uint64, min Role) (*Workspace, error) {
+	p, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, ErrNotAuthenticated
+	}
+	w, err := s.repo.GetForUser(ctx, tx, id, p.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if w == nil {
+		return nil, ErrWorkspaceNotFound
+	}
+	if !w.Role.Allows(min) {
+		return nil, ErrInsufficientRole
+	}
+	return w, nil
+}
+
+// keepOwner fails when the workspace would be left without an owner after
+// one of them steps down.
+func (s *Service) keepOwner(ctx context.Context, tx interface{}, id uint64) error {
+	owners, err := s.repo.CountOwners(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return ErrLastOwner
+	}
+	return nil
+}
+
+func (s *Service) ownInvitation(ctx context.Context, tx interface{}, invitationID uint64) (*Invitation, error) {
+	p, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, ErrNotAuthenticated
+	}
+	inv, err := s.repo.GetInvitation(ctx, tx, invitationID)
+	if err != nil {
+		return nil, err
+	}
+	if inv == nil || inv.InviteeID != p.UserID {
+		return nil, ErrInvitationNotFound
+	}
+	return inv, nil
+}
+
+func normalizeName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > 128 {
+		return "", ErrInvalidName
+	}
+	return name, nil
+}
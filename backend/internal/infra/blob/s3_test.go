@@ -0,0 +1,347 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"todolist/backend/internal/domain/attachment"
+)
+
+const (
+	testAccessKey = "AKIDEXAMPLE"
+	testSecretKey = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+	testRegion    = "eu-west-1"
+	testBucket    = "attachments"
+)
+
+// fakeS3 is an in-memory stand-in for one bucket of an S3-compatible server.
+// It checks the SigV4 signature of every request the way the real service
+// does and answers PUT, GET with Range, and DELETE.
+type fakeS3 struct {
+	t         *testing.T
+	pathStyle bool
+
+	mu      sync.Mutex
+	objects map[string]fakeObject
+}
+
+type fakeObject struct {
+	body        []byte
+	contentType string
+}
+
+func newFakeS3(t *testing.T, pathStyle bool) *fakeS3 {
+	return &fakeS3{t: t, pathStyle: pathStyle, objects: make(map[string]fakeObject)}
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := f.verify(r); err != nil {
+		f.t.Errorf("%s %s: %v", r.Method, r.URL.Path, err)
+		http.Error(w, "SignatureDoesNotMatch", http.StatusForbidden)
+		return
+	}
+	key, ok := f.objectKey(r)
+	if !ok {
+		http.Error(w, "NoSuchBucket", http.StatusNotFound)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if int64(len(body)) != r.ContentLength {
+			http.Error(w, "IncompleteBody", http.StatusBadRequest)
+			return
+		}
+		f.objects[key] = fakeObject{body: body, contentType: r.Header.Get("Content-Type")}
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet:
+		obj, ok := f.objects[key]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		rng := r.Header.Get("Range")
+		if rng == "" {
+			w.Header().Set("Content-Type", obj.contentType)
+			w.Write(obj.body)
+			return
+		}
+		start, end, ok := parseRange(rng, int64(len(obj.body)))
+		if !ok {
+			w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write(obj.body[start : end+1])
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (f *fakeS3) objectKey(r *http.Request) (string, bool) {
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	if f.pathStyle {
+		bucket, key, ok := strings.Cut(path, "/")
+		return key, ok && bucket == testBucket && key != ""
+	}
+	host, _, _ := net.SplitHostPort(r.Host)
+	return path, strings.HasPrefix(host, testBucket+".") && path != ""
+}
+
+// verify recomputes the signature from the request as received.
+func (f *fakeS3) verify(r *http.Request) error {
+	authz := r.Header.Get("Authorization")
+	const prefix = "AWS4-HMAC-SHA256 "
+	if !strings.HasPrefix(authz, prefix) {
+		return errors.New("missing SigV4 authorization")
+	}
+	fields := make(map[string]string)
+	for _, part := range strings.Split(strings.TrimPrefix(authz, prefix), ", ") {
+		name, value, _ := strings.Cut(part, "=")
+		fields[name] = value
+	}
+	amzDate := r.Header.Get("X-Amz-Date")
+	at, err := time.Parse("20060102T150405Z", amzDate)
+	if err != nil {
+		return errors.New("bad X-Amz-Date")
+	}
+	if d := time.Since(at); d > 15*time.Minute || d < -15*time.Minute {
+		return errors.New("request time too skewed")
+	}
+	day := amzDate[:8]
+	scope := day + "/" + testRegion + "/s3/aws4_request"
+	if fields["Credential"] != testAccessKey+"/"+scope {
+		return errors.New("bad credential " + fields["Credential"])
+	}
+	payloadHash := r.Header.Get("X-Amz-Content-Sha256")
+	if r.Method == http.MethodPut && payloadHash != "UNSIGNED-PAYLOAD" {
+		return errors.New("upload should be sent unsigned")
+	}
+
+	signed := strings.Split(fields["SignedHeaders"], ";")
+	for _, required := range []string{"host", "x-amz-content-sha256", "x-amz-date"} {
+		if !contains(signed, required) {
+			return errors.New(required + " is not signed")
+		}
+	}
+	if r.Header.Get("Range") != "" && !contains(signed, "range") {
+		return errors.New("range is not signed")
+	}
+	sort.Strings(signed)
+	var headers strings.Builder
+	for _, name := range signed {
+		value := r.Header.Get(name)
+		if name == "host" {
+			value = r.Host
+		}
+		headers.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+	canonical := strings.Join([]string{
+		r.Method, r.URL.EscapedPath(), r.URL.RawQuery, headers.String(), strings.Join(signed, ";"), payloadHash,
+	}, "\n")
+	digest := sha256.Sum256([]byte(canonical))
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(digest[:])
+
+	key := []byte("AWS4" + testSecretKey)
+	for _, part := range []string{day, testRegion, "s3", "aws4_request"} {
+		key = mac(key, part)
+	}
+	if want := hex.EncodeToString(mac(key, toSign)); fields["Signature"] != want {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+func mac(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRange resolves a single "bytes=start-[end]" range against size.
+func parseRange(header string, size int64) (int64, int64, bool) {
+	spec, ok := strings.CutPrefix(header, "bytes=")
+	if !ok {
+		return 0, 0, false
+	}
+	from, to, _ := strings.Cut(spec, "-")
+	start, err := strconv.ParseInt(from, 10, 64)
+	if err != nil || start >= size {
+		return 0, 0, false
+	}
+	end := size - 1
+	if to != "" {
+		if end, err = strconv.ParseInt(to, 10, 64); err != nil || end < start {
+			return 0, 0, false
+		}
+		if end >= size {
+			end = size - 1
+		}
+	}
+	return start, end, true
+}
+
+func newTestStore(t *testing.T, fake *fakeS3) *S3Store {
+	t.Helper()
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+	store, err := NewS3Store(S3Config{
+		Endpoint:  srv.URL,
+		Region:    testRegion,
+		Bucket:    testBucket,
+		AccessKey: testAccessKey,
+		SecretKey: testSecretKey,
+		PathStyle: fake.pathStyle,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fake.pathStyle {
+		// Virtual-hosted names do not resolve here; send them to the server.
+		addr := srv.Listener.Addr().String()
+		store.client = &http.Client{Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, network, addr)
+			},
+		}}
+	}
+	return store
+}
+
+func readRange(t *testing.T, store *S3Store, key string, offset, length int64) string {
+	t.Helper()
+	rc, err := store.ReadRange(context.Background(), key, offset, length)
+	if err != nil {
+		t.Fatalf("ReadRange(%d, %d): %v", offset, length, err)
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestS3StoreRoundTrip(t *testing.T) {
+	for _, pathStyle := range []bool{true, false} {
+		name := "virtual-hosted"
+		if pathStyle {
+			name = "path-style"
+		}
+		t.Run(name, func(t *testing.T) {
+			fake := newFakeS3(t, pathStyle)
+			store := newTestStore(t, fake)
+			ctx := context.Background()
+			key := "tasks/3f2a/notes v2+final.txt"
+			content := "hello, attachment world"
+
+			err := store.Put(ctx, key, strings.NewReader(content), int64(len(content)), "text/plain")
+			if err != nil {
+				t.Fatalf("Put: %v", err)
+			}
+			if got := fake.objects[key].contentType; got != "text/plain" {
+				t.Errorf("stored content type = %q, want text/plain", got)
+			}
+
+			cases := []struct {
+				offset, length int64
+				want           string
+			}{
+				{0, -1, content},
+				{7, 10, "attachment"},
+				{18, -1, "world"},
+				{18, 100, "world"},
+				{int64(len(content)), -1, ""},
+			}
+			for _, c := range cases {
+				if got := readRange(t, store, key, c.offset, c.length); got != c.want {
+					t.Errorf("ReadRange(%d, %d) = %q, want %q", c.offset, c.length, got, c.want)
+				}
+			}
+
+			if err := store.Delete(ctx, key); err != nil {
+				t.Fatalf("Delete: %v", err)
+			}
+			if _, err := store.ReadRange(ctx, key, 0, -1); !errors.Is(err, attachment.ErrBlobNotFound) {
+				t.Errorf("ReadRange after Delete: err = %v, want ErrBlobNotFound", err)
+			}
+			if err := store.Delete(ctx, key); err != nil {
+				t.Errorf("Delete of a missing blob: %v", err)
+			}
+		})
+	}
+}
+
+func TestS3StorePutEmpty(t *testing.T) {
+	fake := newFakeS3(t, true)
+	store := newTestStore(t, fake)
+	if err := store.Put(context.Background(), "empty", bytes.NewReader(nil), 0, ""); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if obj, ok := fake.objects["empty"]; !ok || len(obj.body) != 0 {
+		t.Fatalf("empty object not stored: %+v", obj)
+	}
+}
+
+func TestS3StoreReportsServerErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "<Error><Code>AccessDenied</Code></Error>", http.StatusForbidden)
+	}))
+	defer srv.Close()
+	store, err := NewS3Store(S3Config{Endpoint: srv.URL, Bucket: testBucket, PathStyle: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.Put(context.Background(), "k", strings.NewReader("x"), 1, "")
+	if err == nil || !strings.Contains(err.Error(), "AccessDenied") {
+		t.Errorf("Put: err = %v, want the AccessDenied response", err)
+	}
+	if _, err := store.ReadRange(context.Background(), "k", 0, -1); err == nil || errors.Is(err, attachment.ErrBlobNotFound) {
+		t.Errorf("ReadRange: err = %v, want the AccessDenied response", err)
+	}
+}
+
+func TestNewS3StoreValidatesConfig(t *testing.T) {
+	if _, err := NewS3Store(S3Config{Bucket: testBucket}); err == nil {
+		t.Error("missing endpoint accepted")
+	}
+	if _, err := NewS3Store(S3Config{Endpoint: "http://localhost:9000"}); err == nil {
+		t.Error("missing bucket accepted")
+	}
+	if _, err := NewS3Store(S3Config{Endpoint: "not a url", Bucket: testBucket}); err == nil {
+		t.Error("endpoint without a host accepted")
+	}
+}
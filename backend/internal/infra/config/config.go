@@ -9,10 +9,21 @@ import (
 )
 
 type Config struct {
-	App      AppConfig
-	Database DatabaseConfig
-	Undo     UndoConfig
-	CORS     CORSConfig
+	App         AppConfig
+	Database    DatabaseConfig
+	Undo        UndoConfig
+	Task        TaskConfig
+	Workflow    WorkflowConfig
+	Attachment  AttachmentConfig
+	Auth        AuthConfig
+	Workspace   WorkspaceConfig
+	Limits      LimitsConfig
+	Idempotency IdempotencyConfig
+	Events      EventsConfig
+	Realtime    RealtimeConfig
+	Webhook     WebhookConfig
+	Outbox      OutboxConfig
+	CORS        CORSConfig
 }
 
 type AppConfig struct {
@@ -36,6 +47,156 @@ type UndoConfig struct {
 	TTL time.Duration
 }
 
+type TaskConfig struct {
+	AutoCompleteParent   bool
+	DailyCapacityMinutes int
+	MinutesPerPoint      int
+	WorkDaysPerWeek      int
+	CommentEditWindow    time.Duration
+}
+
+// WorkflowConfig defines custom task statuses. With no statuses configured the
+// default now/future/history workflow is used. Transitions maps a status key
+// to the keys it may move to; statuses left out may move anywhere. Active is
+// the status of work in progress and Backlog the one the daily plan draws
+// from; they default to the initial status for Backlog and the first other
+// non-terminal status for Active.
+type WorkflowConfig struct {
+	Initial     string
+	Active      string
+	Backlog     string
+	Statuses    []WorkflowStatusConfig
+	Transitions map[string][]string
+}
+
+type WorkflowStatusConfig struct {
+	Key         string
+	Name        string
+	Terminal    bool
+	DefaultSort string
+}
+
+// AttachmentConfig limits uploads and selects the blob store: "local" keeps
+// files under LocalDir, "s3" uses an S3-compatible bucket. TransferTimeout
+// replaces the server's read and write timeouts for uploads and downloads,
+// which are too short for large files.
+type AttachmentConfig struct {
+	MaxSize         int64
+	AllowedTypes    []string
+	Store           string
+	LocalDir        string
+	TransferTimeout time.Duration
+	S3              S3Config
+}
+
+type S3Config struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PathStyle bool
+}
+
+// AuthConfig controls user sessions. SecureCookie should be on whenever the
+// API is served over HTTPS.
+type AuthConfig struct {
+	SessionTTL        time.Duration
+	AllowRegistration bool
+	SecureCookie      bool
+}
+
+type WorkspaceConfig struct {
+	InvitationTTL time.Duration
+}
+
+// IdempotencyConfig sets how long Idempotency-Key responses are kept for
+// replay.
+type IdempotencyConfig struct {
+	TTL time.Duration
+}
+
+// EventsConfig tunes the live change stream: how many recent events are kept
+// for clients resuming with Last-Event-ID and how often idle streams send a
+// heartbeat.
+type EventsConfig struct {
+	BufferSize int
+	Heartbeat  time.Duration
+}
+
+// RealtimeConfig tunes the WebSocket channel. Presence on a task lapses after
+// PresenceTTL without a heartbeat from its connection.
+type RealtimeConfig struct {
+	PresenceTTL time.Duration
+}
+
+// WebhookConfig tunes outgoing webhook deliveries. A failed delivery is
+// retried after BackoffBase, doubling up to BackoffMax, until MaxAttempts
+// have been made. Webhooks may only target public addresses unless
+// AllowPrivateNetworks is set.
+type WebhookConfig struct {
+	Timeout              time.Duration
+	MaxAttempts          int
+	BackoffBase          time.Duration
+	BackoffMax           time.Duration
+	PollInterval         time.Duration
+	AllowPrivateNetworks bool
+}
+
+// OutboxConfig tunes the relay of committed events to consumers. A gap in
+// the offsets is waited for up to GapTimeout, which has to exceed the longest
+// transaction, and looked for again until GapRecheck has passed. Operators
+// name the users allowed to inspect and replay the outbox; Sinks are external
+// endpoints that get every message.
+type OutboxConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	GapTimeout   time.Duration
+	GapRecheck   time.Duration
+	RetryMax     time.Duration
+	Retention    time.Duration
+	Operators    []string
+	Sinks        []OutboxSinkConfig
+}
+
+// OutboxSinkConfig is an HTTP endpoint receiving outbox messages in batches,
+// signed with Secret when set. Name keys its position in the outbox.
+type OutboxSinkConfig struct {
+	Name    string
+	URL     string
+	Secret  string
+	Timeout time.Duration
+}
+
+// LimitsConfig guards the API against oversized and overly frequent
+// requests. MaxBodyBytes applies to every route except attachment uploads,
+// which follow Attachment.MaxSize. TrustedProxies lists the proxies, as
+// addresses or CIDRs, whose X-Forwarded-For header is believed when telling
+// clients apart; by default none is and the peer address counts, since
+// anyone can send the header.
+type LimitsConfig struct {
+	MaxBodyBytes   int64
+	MaxBulkIDs     int
+	TrustedProxies []string
+	RateLimit      RateLimitConfig
+}
+
+// RateLimitConfig holds one token bucket per client for each route group:
+// Public covers registration and login, API every authenticated route and
+// Bulk the bulk and reorder routes on top of API.
+type RateLimitConfig struct {
+	Public RateLimitRule
+	API    RateLimitRule
+	Bulk   RateLimitRule
+}
+
+// RateLimitRule allows Rate requests per second with bursts of up to Burst.
+// A zero rate turns the limit off.
+type RateLimitRule struct {
+	Rate  float64
+	Burst int
+}
+
 type CORSConfig struct {
 	AllowOrigins []string
 	AllowMethods []string
@@ -78,7 +239,7 @@ func Load() (*Config, error) {
 	}
 
 	if len(cfg.CORS.AllowHeaders) == 0 {
-		cfg.CORS.AllowHeaders = []string{"Content-Type", "Authorization", "X-Requested-With"}
+		cfg.CORS.AllowHeaders = []string{"Content-Type", "Authorization", "X-Requested-With", "Idempotency-Key", "Last-Event-ID"}
 	}
 
 	return cfg, nil
@@ -96,5 +257,55 @@ func setDefaults(v *viper.Viper) {
 
 	v.SetDefault("undo.ttl", "5s")
 
+	v.SetDefault("task.autoCompleteParent", false)
+	v.SetDefault("task.dailyCapacityMinutes", 480)
+	v.SetDefault("task.minutesPerPoint", 60)
+	v.SetDefault("task.workDaysPerWeek", 5)
+	v.SetDefault("task.commentEditWindow", "24h")
+
+	v.SetDefault("attachment.maxSize", 25<<20)
+	v.SetDefault("attachment.store", "local")
+	v.SetDefault("attachment.localDir", "./data/attachments")
+	v.SetDefault("attachment.transferTimeout", "10m")
+	v.SetDefault("attachment.s3.region", "us-east-1")
+	v.SetDefault("attachment.s3.pathStyle", true)
+
+	v.SetDefault("auth.sessionTTL", "720h")
+	v.SetDefault("auth.allowRegistration", true)
+	v.SetDefault("auth.secureCookie", false)
+
+	v.SetDefault("workspace.invitationTTL", "168h")
+
+	v.SetDefault("idempotency.ttl", "24h")
+
+	v.SetDefault("events.bufferSize", 1024)
+	v.SetDefault("events.heartbeat", "25s")
+
+	v.SetDefault("realtime.presenceTTL", "30s")
+
+	v.SetDefault("webhook.timeout", "10s")
+	v.SetDefault("webhook.maxAttempts", 8)
+	v.SetDefault("webhook.backoffBase", "30s")
+	v.SetDefault("webhook.backoffMax", "6h")
+	v.SetDefault("webhook.pollInterval", "2s")
+	v.SetDefault("webhook.allowPrivateNetworks", false)
+
+	v.SetDefault("outbox.pollInterval", "1s")
+	v.SetDefault("outbox.batchSize", 100)
+	v.SetDefault("outbox.gapTimeout", "10s")
+	v.SetDefault("outbox.gapRecheck", "10m")
+	v.SetDefault("outbox.retryMax", "1m")
+	v.SetDefault("outbox.retention", "168h")
+
+	v.SetDefault("limits.maxBodyBytes", 1<<20)
+	v.SetDefault("limits.maxBulkIDs", 500)
+	v.SetDefault("limits.trustedProxies", []string{})
+	v.SetDefault("limits.rateLimit.public.rate", 1)
+	v.SetDefault("limits.rateLimit.public.burst", 10)
+	v.SetDefault("limits.rateLimit.api.rate", 20)
+	v.SetDefault("limits.rateLimit.api.burst", 40)
+	v.SetDefault("limits.rateLimit.bulk.rate", 1)
+	v.SetDefault("limits.rateLimit.bulk.burst", 5)
+
 	v.SetDefault("cors.allowOrigins", []string{"*"})
 }
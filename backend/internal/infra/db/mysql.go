@@ -8,8 +8,18 @@ import (
     "gorm.io/gorm"
     "gorm.io/gorm/logger"
 
+    "todolist/backend/internal/domain/attachment"
+    "todolist/backend/internal/domain/customfield"
+    "todolist/backend/internal/domain/idempotency"
+    "todolist/backend/internal/domain/outbox"
+    "todolist/backend/internal/domain/project"
+    "todolist/backend/internal/domain/tag"
     "todolist/backend/internal/domain/task"
+    "todolist/backend/internal/domain/template"
     "todolist/backend/internal/domain/undo"
+    "todolist/backend/internal/domain/user"
+    "todolist/backend/internal/domain/webhook"
+    "todolist/backend/internal/domain/workspace"
     "todolist/backend/internal/infra/config"
 )
 
@@ -36,9 +46,47 @@ func Connect(cfg *config.Config, log *zap.Logger) (*gorm.DB, error) {
 }
 
 func AutoMigrate(db *gorm.DB, log *zap.Logger) error {
-    if err := db.AutoMigrate(&task.Task{}, &undo.TaskOperation{}, &task.ActivityLog{}); err != nil {
+    if err := db.SetupJoinTable(&task.Task{}, "Tags", &tag.TaskTag{}); err != nil {
+        return fmt.Errorf("setup join table: %w", err)
+    }
+    if err := db.AutoMigrate(&task.Task{}, &undo.TaskOperation{}, &task.ActivityLog{}, &tag.Tag{}, &project.Project{}, &task.Dependency{}, &task.AssigneeOrder{}, &task.ChecklistItem{}, &task.TimeEntry{}, &task.Comment{}, &attachment.Attachment{}, &template.Template{}, &customfield.Field{}, &customfield.Value{}, &user.User{}, &user.Session{}, &user.APIToken{}, &workspace.Workspace{}, &workspace.Member{}, &workspace.Invitation{}, &idempotency.Record{}, &webhook.Subscription{}, &webhook.Delivery{}, &webhook.Attempt{}, &outbox.Message{}, &outbox.Cursor{}, &outbox.Gap{}); err != nil {
         return fmt.Errorf("auto migrate: %w", err)
     }
+    // Tag names and custom field keys used to be unique across all users,
+    // then per owner; they are now unique within a workspace.
+    legacy := []struct {
+        model interface{}
+        index string
+    }{
+        {&tag.Tag{}, "idx_tags_name"},
+        {&tag.Tag{}, "idx_tags_owner_name"},
+        {&customfield.Field{}, "idx_custom_fields_key"},
+        {&customfield.Field{}, "idx_custom_fields_owner_key"},
+    }
+    for _, l := range legacy {
+        if !db.Migrator().HasIndex(l.model, l.index) {
+            continue
+        }
+        if err := db.Migrator().DropIndex(l.model, l.index); err != nil {
+            return fmt.Errorf("drop index %s: %w", l.index, err)
+        }
+    }
+    // Tags, custom fields and templates belong to a workspace rather than
+    // to their creator.
+    for _, model := range []interface{}{&tag.Tag{}, &customfield.Field{}, &template.Template{}} {
+        if !db.Migrator().HasColumn(model, "owner_id") {
+            continue
+        }
+        if err := db.Migrator().DropColumn(model, "owner_id"); err != nil {
+            return fmt.Errorf("drop owner of %T: %w", model, err)
+        }
+    }
+    // Webhook response bodies are no longer kept.
+    if db.Migrator().HasColumn(&webhook.Attempt{}, "response_body") {
+        if err := db.Migrator().DropColumn(&webhook.Attempt{}, "response_body"); err != nil {
+            return fmt.Errorf("drop webhook response bodies: %w", err)
+        }
+    }
     return nil
 }
 
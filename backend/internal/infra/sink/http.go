@@ -0,0 +1,81 @@
+// Package sink relays outbox messages to systems outside the process.
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"todolist/backend/internal/domain/outbox"
+	"todolist/backend/internal/domain/webhook"
+)
+
+const defaultTimeout = 10 * time.Second
+
+// HTTP POSTs each batch of outbox messages as {"messages": [...]} to a URL.
+// With a secret the body is signed like a webhook delivery. Any status
+// outside 2xx fails the batch, which the outbox then hands over again, so
+// the receiver sees every message at least once and in order; it should
+// drop duplicates by message id.
+type HTTP struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+func NewHTTP(url, secret string, timeout time.Duration) *HTTP {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &HTTP{
+		url:    url,
+		secret: secret,
+		client: &http.Client{
+			Timeout: timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+type batch struct {
+	Messages []outbox.Envelope `json:"messages"`
+}
+
+func (h *HTTP) HandleMessages(ctx context.Context, msgs []outbox.Message) error {
+	envelopes := make([]outbox.Envelope, 0, len(msgs))
+	for _, m := range msgs {
+		envelopes = append(envelopes, m.Envelope())
+	}
+	body, err := json.Marshal(batch{Messages: envelopes})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if h.secret != "" {
+		timestamp := time.Now().Unix()
+		req.Header.Set(webhook.HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+		req.Header.Set(webhook.HeaderSignature, webhook.Signature(h.secret, timestamp, body))
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("sink %s: unexpected status %d", h.url, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// Package auth carries the authenticated caller through request contexts.
+package auth
+
+import "context"
+
+// Scope is a permission level granted to a credential. Each scope includes
+// the ones below it: admin implies write, write implies read.
+type Scope string
+
+const (
+	ScopeRead  Scope = "read"
+	ScopeWrite Scope = "write"
+	ScopeAdmin Scope = "admin"
+)
+
+// AllScopes is granted to interactive sessions.
+var AllScopes = []Scope{ScopeRead, ScopeWrite, ScopeAdmin}
+
+var scopeRank = map[Scope]int{ScopeRead: 1, ScopeWrite: 2, ScopeAdmin: 3}
+
+func IsValidScope(s Scope) bool {
+	_, ok := scopeRank[s]
+	return ok
+}
+
+// Principal identifies the user a request acts for and what it may do.
+type Principal struct {
+	UserID   uint64
+	Username string
+	Scopes   []Scope
+}
+
+// Has reports whether the principal was granted scope or a broader one.
+func (p Principal) Has(scope Scope) bool {
+	for _, s := range p.Scopes {
+		if scopeRank[s] >= scopeRank[scope] {
+			return true
+		}
+	}
+	return false
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx that carries p.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal stored in ctx, if any. Contexts without
+// one see nothing unless they are marked with WithSystem.
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
+
+type systemKey struct{}
+
+// WithSystem returns a copy of ctx for internal work done for no user, such
+// as background jobs. Such contexts pass permission checks and see the rows
+// of every workspace, so they must never come from a request.
+func WithSystem(ctx context.Context) context.Context {
+	return context.WithValue(ctx, systemKey{}, true)
+}
+
+// IsSystem reports whether ctx was returned by WithSystem.
+func IsSystem(ctx context.Context) bool {
+	system, _ := ctx.Value(systemKey{}).(bool)
+	return system
+}
+
+// OwnerID returns the user id of the principal in ctx, or zero when there is none.
+func OwnerID(ctx context.Context) uint64 {
+	p, _ := FromContext(ctx)
+	return p.UserID
+}
@@ -0,0 +1,157 @@
+// Package events fans committed changes out to live subscribers within the
+// process.
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultBufferSize is how many recent events are kept for resuming
+	// subscribers when no size is configured.
+	DefaultBufferSize = 1024
+
+	// subscriberBuffer is how far a subscriber may fall behind before it is
+	// dropped.
+	subscriberBuffer = 64
+)
+
+// Event is a change that has been committed. WorkspaceID tells who may see
+// it; Data is the JSON-encodable payload.
+type Event struct {
+	ID          uint64
+	Type        string
+	WorkspaceID uint64
+	ActorID     uint64
+	At          time.Time
+	Data        interface{}
+}
+
+// Bus hands published events to every subscriber and keeps the most recent
+// ones so a subscriber that lost its connection can resume after the last
+// event it saw. Event IDs start at the time the bus was created and grow by
+// one, so IDs handed out before a restart are older than every new one.
+type Bus struct {
+	mu     sync.Mutex
+	size   int
+	nextID uint64
+	recent []Event
+	subs   map[*Subscription]struct{}
+	closed bool
+}
+
+func NewBus(size int) *Bus {
+	if size <= 0 {
+		size = DefaultBufferSize
+	}
+	return &Bus{
+		size:   size,
+		nextID: uint64(time.Now().UnixNano()),
+		recent: make([]Event, 0, size),
+		subs:   make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish numbers e and delivers it. A subscriber whose queue is full is
+// dropped rather than holding up the publisher; it can resume from the
+// buffer.
+func (b *Bus) Publish(e Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+
+	b.nextID++
+	e.ID = b.nextID
+	if e.At.IsZero() {
+		e.At = time.Now()
+	}
+	if len(b.recent) == b.size {
+		copy(b.recent, b.recent[1:])
+		b.recent = b.recent[:b.size-1]
+	}
+	b.recent = append(b.recent, e)
+
+	for sub := range b.subs {
+		select {
+		case sub.ch <- e:
+		default:
+			b.drop(sub)
+		}
+	}
+}
+
+// Subscribe starts a subscription. With a non-zero lastID it also returns the
+// buffered events published after it; complete is false when some of them
+// have already left the buffer and the subscriber has to reload instead.
+func (b *Bus) Subscribe(lastID uint64) (sub *Subscription, missed []Event, complete bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub = &Subscription{bus: b, ch: make(chan Event, subscriberBuffer)}
+	sub.C = sub.ch
+	if b.closed {
+		close(sub.ch)
+		return sub, nil, true
+	}
+	b.subs[sub] = struct{}{}
+
+	if lastID == 0 {
+		return sub, nil, true
+	}
+	oldest := b.nextID + 1
+	if len(b.recent) > 0 {
+		oldest = b.recent[0].ID
+	}
+	complete = lastID >= oldest-1 && lastID <= b.nextID
+	for _, e := range b.recent {
+		if e.ID > lastID {
+			missed = append(missed, e)
+		}
+	}
+	return sub, missed, complete
+}
+
+// Close ends every subscription; later publishes are discarded.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.closed = true
+	for sub := range b.subs {
+		b.drop(sub)
+	}
+}
+
+// Closed reports whether Close has been called.
+func (b *Bus) Closed() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.closed
+}
+
+func (b *Bus) drop(sub *Subscription) {
+	delete(b.subs, sub)
+	close(sub.ch)
+}
+
+// Subscription receives events on C until it is closed, falls behind or the
+// bus shuts down; C is closed then.
+type Subscription struct {
+	C <-chan Event
+
+	bus *Bus
+	ch  chan Event
+}
+
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	if _, ok := s.bus.subs[s]; ok {
+		s.bus.drop(s)
+	}
+}
@@ -37,18 +37,42 @@ func BadRequest(c *gin.Context, msg string) {
     c.JSON(400, Envelope{Code: 40001, Message: msg})
 }
 
+func Unauthorized(c *gin.Context, msg string) {
+    c.JSON(401, Envelope{Code: 40100, Message: msg})
+}
+
 func NotFound(c *gin.Context, msg string) {
     c.JSON(404, Envelope{Code: 40400, Message: msg})
 }
 
+func Forbidden(c *gin.Context, msg string) {
+    c.JSON(403, Envelope{Code: 40300, Message: msg})
+}
+
 func Conflict(c *gin.Context, msg string) {
     c.JSON(409, Envelope{Code: 40900, Message: msg})
 }
 
+func PayloadTooLarge(c *gin.Context, msg string) {
+    c.JSON(413, Envelope{Code: 41300, Message: msg})
+}
+
+func UnsupportedMediaType(c *gin.Context, msg string) {
+    c.JSON(415, Envelope{Code: 41500, Message: msg})
+}
+
 func Gone(c *gin.Context, msg string) {
     c.JSON(410, Envelope{Code: 41000, Message: msg})
 }
 
+func UnprocessableEntity(c *gin.Context, msg string) {
+    c.JSON(422, Envelope{Code: 42200, Message: msg})
+}
+
+func TooManyRequests(c *gin.Context, msg string) {
+    c.JSON(429, Envelope{Code: 42900, Message: msg})
+}
+
 func InternalServerError(c *gin.Context, msg string) {
     c.JSON(500, Envelope{Code: 50000, Message: msg})
 }
@@ -64,10 +88,49 @@ func Error(c *gin.Context, err error) {
 	// This is a simple implementation; in a real app, you might use errors.Is or custom error types
 	msg := err.Error()
 	switch msg {
-	case "task not found":
+	case "task not found", "tag not found", "project not found", "parent task not found",
+		"checklist item not found", "time entry not found", "template not found",
+		"custom field not found", "comment not found", "attachment not found", "blob not found",
+		"api token not found", "workspace not found", "member not found", "user not found",
+		"invitation not found", "webhook not found", "delivery not found",
+		"outbox consumer not found":
 		NotFound(c, msg)
-	case "invalid status", "invalid deadline format", "invalid completed time", "empty ids", "ordered list empty":
+	case "tag already exists", "project is archived", "project is not empty",
+		"task is blocked by unfinished tasks", "dependency would create a cycle", "parent would create a cycle",
+		"another timer is already running", "no timer running on task", "status transition not allowed",
+		"custom field already exists", "custom field option in use", "comment can no longer be edited",
+		"task is not in trash", "username already taken", "workspace is not empty",
+		"personal workspace cannot be deleted", "workspace needs an owner", "user is already a member",
+		"user is already invited", "request with this idempotency key is still in progress",
+		"webhook is inactive":
+		Conflict(c, msg)
+	case "invalid status", "invalid deadline format", "invalid completed time", "empty ids", "ordered list empty",
+		"invalid priority", "invalid sort key",
+		"empty tag ids", "invalid tag name", "cannot merge tag into itself", "cannot merge tags across workspaces",
+		"invalid project name", "task does not belong to project", "subtask follows its parent's project",
+		"task cannot block itself", "invalid checklist text", "invalid time range",
+		"invalid estimate", "invalid title", "invalid template name", "invalid template tree",
+		"missing template variable", "invalid custom field key", "invalid custom field name",
+		"invalid custom field type", "invalid custom field options", "invalid custom field value",
+		"invalid comment body", "invalid file name", "invalid username", "invalid password",
+		"invalid token name", "invalid token scope", "invalid token expiry",
+		"invalid workspace name", "invalid role", "tasks belong to different workspaces",
+		"cannot link items across workspaces", "assignee is not a workspace member", "task is not assigned to you",
+		"too many ids", "invalid idempotency key", "invalid webhook url", "invalid webhook secret",
+		"invalid webhook events", "webhook url must point to a public address", "invalid delivery status",
+		"invalid outbox offset":
 		BadRequest(c, msg)
+	case "invalid credentials", "authentication required":
+		Unauthorized(c, msg)
+	case "only the author can change a comment", "registration disabled", "insufficient scope",
+		"insufficient role", "operator access required":
+		Forbidden(c, msg)
+	case "attachment too large", "request body too large":
+		PayloadTooLarge(c, msg)
+	case "idempotency key reused with a different request":
+		UnprocessableEntity(c, msg)
+	case "attachment type not allowed":
+		UnsupportedMediaType(c, msg)
 	case "undo token not found", "undo token expired", "undo token consumed":
 		Gone(c, msg)
 	default:
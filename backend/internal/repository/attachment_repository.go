@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	domain "todolist/backend/internal/domain/attachment"
+)
+
+type AttachmentRepository struct {
+	db *gorm.DB
+}
+
+func NewAttachmentRepository(db *gorm.DB) *AttachmentRepository {
+	return &AttachmentRepository{db: db}
+}
+
+func (r *AttachmentRepository) DB() *gorm.DB {
+	return r.db
+}
+
+func (r *AttachmentRepository) dbWith(tx interface{}) *gorm.DB {
+	if tx != nil {
+		if db, ok := tx.(*gorm.DB); ok {
+			return db
+		}
+	}
+	return r.db
+}
+
+func (r *AttachmentRepository) Create(ctx context.Context, tx interface{}, a *domain.Attachment) error {
+	return r.dbWith(tx).WithContext(ctx).Create(a).Error
+}
+
+func (r *AttachmentRepository) Get(ctx context.Context, tx interface{}, taskUUID string, id uint64) (*domain.Attachment, error) {
+	var a domain.Attachment
+	err := r.dbWith(tx).WithContext(ctx).Where("task_uuid = ? AND id = ?", taskUUID, id).First(&a).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *AttachmentRepository) ListByTask(ctx context.Context, tx interface{}, taskUUID string) ([]domain.Attachment, error) {
+	var attachments []domain.Attachment
+	err := r.dbWith(tx).WithContext(ctx).
+		Where("task_uuid = ?", taskUUID).
+		Order("created_at ASC, id ASC").
+		Find(&attachments).Error
+	return attachments, err
+}
+
+func (r *AttachmentRepository) ListByTasks(ctx context.Context, tx interface{}, taskUUIDs []string) ([]domain.Attachment, error) {
+	if len(taskUUIDs) == 0 {
+		return []domain.Attachment{}, nil
+	}
+	var attachments []domain.Attachment
+	err := r.dbWith(tx).WithContext(ctx).
+		Where("task_uuid IN ?", taskUUIDs).
+		Find(&attachments).Error
+	return attachments, err
+}
+
+func (r *AttachmentRepository) Delete(ctx context.Context, tx interface{}, id uint64) error {
+	return r.dbWith(tx).WithContext(ctx).Where("id = ?", id).Delete(&domain.Attachment{}).Error
+}
+
+func (r *AttachmentRepository) DeleteByTasks(ctx context.Context, tx interface{}, taskUUIDs []string) error {
+	if len(taskUUIDs) == 0 {
+		return nil
+	}
+	return r.dbWith(tx).WithContext(ctx).Where("task_uuid IN ?", taskUUIDs).Delete(&domain.Attachment{}).Error
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	domain "todolist/backend/internal/domain/customfield"
+)
+
+type CustomFieldRepository struct {
+	db *gorm.DB
+}
+
+func NewCustomFieldRepository(db *gorm.DB) *CustomFieldRepository {
+	return &CustomFieldRepository{db: db}
+}
+
+func (r *CustomFieldRepository) DB() *gorm.DB {
+	return r.db
+}
+
+func (r *CustomFieldRepository) dbWith(tx interface{}) *gorm.DB {
+	if tx != nil {
+		if db, ok := tx.(*gorm.DB); ok {
+			return db
+		}
+	}
+	return r.db
+}
+
+func (r *CustomFieldRepository) List(ctx context.Context, workspaceID *uint64) ([]domain.Field, error) {
+	query := r.db.WithContext(ctx).Scopes(visible(ctx, "custom_fields.workspace_id"))
+	if workspaceID != nil {
+		query = query.Where("workspace_id = ?", *workspaceID)
+	}
+	var fields []domain.Field
+	err := query.Order("name ASC, id ASC").Find(&fields).Error
+	return fields, err
+}
+
+func (r *CustomFieldRepository) GetByID(ctx context.Context, tx interface{}, id uint64) (*domain.Field, error) {
+	var f domain.Field
+	err := r.dbWith(tx).WithContext(ctx).Scopes(visible(ctx, "custom_fields.workspace_id")).Where("id = ?", id).First(&f).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+func (r *CustomFieldRepository) GetByKey(ctx context.Context, tx interface{}, workspaceID uint64, key string) (*domain.Field, error) {
+	var f domain.Field
+	err := r.dbWith(tx).WithContext(ctx).Where("workspace_id = ? AND `key` = ?", workspaceID, key).First(&f).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+func (r *CustomFieldRepository) Create(ctx context.Context, tx interface{}, f *domain.Field) error {
+	return r.dbWith(tx).WithContext(ctx).Create(f).Error
+}
+
+func (r *CustomFieldRepository) Update(ctx context.Context, tx interface{}, f *domain.Field) error {
+	return r.dbWith(tx).WithContext(ctx).Save(f).Error
+}
+
+func (r *CustomFieldRepository) Delete(ctx context.Context, tx interface{}, id uint64) error {
+	db := r.dbWith(tx).WithContext(ctx)
+	if err := db.Where("field_id = ?", id).Delete(&domain.Value{}).Error; err != nil {
+		return err
+	}
+	return db.Where("id = ?", id).Delete(&domain.Field{}).Error
+}
+
+// CountValues counts the tasks, deleted ones included, whose value of the
+// field is one of values.
+func (r *CustomFieldRepository) CountValues(ctx context.Context, tx interface{}, fieldID uint64, values []string) (int64, error) {
+	var count int64
+	err := r.dbWith(tx).WithContext(ctx).
+		Model(&domain.Value{}).
+		Where("field_id = ? AND value IN ?", fieldID, values).
+		Count(&count).Error
+	return count, err
+}
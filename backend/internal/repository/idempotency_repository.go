@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	domain "todolist/backend/internal/domain/idempotency"
+)
+
+type IdempotencyRepository struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyRepository(db *gorm.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+func (r *IdempotencyRepository) DB() *gorm.DB {
+	return r.db
+}
+
+func (r *IdempotencyRepository) dbWith(tx interface{}) *gorm.DB {
+	if tx != nil {
+		if db, ok := tx.(*gorm.DB); ok {
+			return db
+		}
+	}
+	return r.db
+}
+
+func (r *IdempotencyRepository) Reserve(ctx context.Context, tx interface{}, rec *domain.Record) (bool, error) {
+	res := r.dbWith(tx).WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(rec)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *IdempotencyRepository) Get(ctx context.Context, tx interface{}, userID uint64, key string) (*domain.Record, error) {
+	var rec domain.Record
+	err := r.dbWith(tx).WithContext(ctx).
+		Where("user_id = ? AND idempotency_key = ?", userID, key).
+		First(&rec).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (r *IdempotencyRepository) SaveResponse(ctx context.Context, tx interface{}, userID uint64, key string, status int, contentType string, body []byte) error {
+	return r.dbWith(tx).WithContext(ctx).Model(&domain.Record{}).
+		Where("user_id = ? AND idempotency_key = ?", userID, key).
+		Updates(map[string]interface{}{
+			"status_code":  status,
+			"content_type": contentType,
+			"body":         body,
+		}).Error
+}
+
+func (r *IdempotencyRepository) Delete(ctx context.Context, tx interface{}, userID uint64, key string) error {
+	return r.dbWith(tx).WithContext(ctx).
+		Where("user_id = ? AND idempotency_key = ?", userID, key).
+		Delete(&domain.Record{}).Error
+}
+
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context, tx interface{}, userID uint64, now time.Time) error {
+	return r.dbWith(tx).WithContext(ctx).
+		Where("user_id = ? AND expires_at < ?", userID, now).
+		Delete(&domain.Record{}).Error
+}
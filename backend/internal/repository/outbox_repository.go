@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	domain "todolist/backend/internal/domain/outbox"
+)
+
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+func (r *OutboxRepository) DB() *gorm.DB {
+	return r.db
+}
+
+func (r *OutboxRepository) dbWith(tx interface{}) *gorm.DB {
+	if tx != nil {
+		if db, ok := tx.(*gorm.DB); ok {
+			return db
+		}
+	}
+	return r.db
+}
+
+func (r *OutboxRepository) Append(ctx context.Context, tx interface{}, list []domain.Message) error {
+	if len(list) == 0 {
+		return nil
+	}
+	return r.dbWith(tx).WithContext(ctx).Create(&list).Error
+}
+
+func (r *OutboxRepository) ListAfter(ctx context.Context, tx interface{}, offset uint64, limit int) ([]domain.Message, error) {
+	var list []domain.Message
+	err := r.dbWith(tx).WithContext(ctx).
+		Where("id > ?", offset).
+		Order("id ASC").
+		Limit(limit).
+		Find(&list).Error
+	return list, err
+}
+
+func (r *OutboxRepository) ListByIDs(ctx context.Context, tx interface{}, ids []uint64, limit int) ([]domain.Message, error) {
+	var list []domain.Message
+	if len(ids) == 0 {
+		return list, nil
+	}
+	err := r.dbWith(tx).WithContext(ctx).
+		Where("id IN ?", ids).
+		Order("id ASC").
+		Limit(limit).
+		Find(&list).Error
+	return list, err
+}
+
+func (r *OutboxRepository) Head(ctx context.Context, tx interface{}) (uint64, error) {
+	var head uint64
+	err := r.dbWith(tx).WithContext(ctx).Model(&domain.Message{}).
+		Select("COALESCE(MAX(id), 0)").
+		Scan(&head).Error
+	return head, err
+}
+
+func (r *OutboxRepository) Prune(ctx context.Context, tx interface{}, offset uint64, before time.Time, limit int) (int64, error) {
+	res := r.dbWith(tx).WithContext(ctx).
+		Where("id <= ? AND created_at < ?", offset, before).
+		Order("id ASC").
+		Limit(limit).
+		Delete(&domain.Message{})
+	return res.RowsAffected, res.Error
+}
+
+func (r *OutboxRepository) EnsureCursor(ctx context.Context, tx interface{}, consumer string, offset uint64) error {
+	return r.dbWith(tx).WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&domain.Cursor{Consumer: consumer, LastOffset: offset}).Error
+}
+
+func (r *OutboxRepository) GetCursor(ctx context.Context, tx interface{}, consumer string) (*domain.Cursor, error) {
+	var c domain.Cursor
+	err := r.dbWith(tx).WithContext(ctx).Where("consumer = ?", consumer).First(&c).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *OutboxRepository) ListCursors(ctx context.Context, tx interface{}, consumers []string) ([]domain.Cursor, error) {
+	var list []domain.Cursor
+	if len(consumers) == 0 {
+		return list, nil
+	}
+	err := r.dbWith(tx).WithContext(ctx).Where("consumer IN ?", consumers).Find(&list).Error
+	return list, err
+}
+
+func (r *OutboxRepository) AdvanceCursor(ctx context.Context, tx interface{}, consumer string, from, to uint64) (bool, error) {
+	res := r.dbWith(tx).WithContext(ctx).Model(&domain.Cursor{}).
+		Where("consumer = ? AND last_offset = ?", consumer, from).
+		Update("last_offset", to)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *OutboxRepository) SetCursor(ctx context.Context, tx interface{}, consumer string, offset uint64) error {
+	return r.dbWith(tx).WithContext(ctx).Model(&domain.Cursor{}).
+		Where("consumer = ?", consumer).
+		Update("last_offset", offset).Error
+}
+
+func (r *OutboxRepository) AddGaps(ctx context.Context, tx interface{}, consumer string, offsets []uint64, skippedAt time.Time) error {
+	if len(offsets) == 0 {
+		return nil
+	}
+	gaps := make([]domain.Gap, 0, len(offsets))
+	for _, offset := range offsets {
+		gaps = append(gaps, domain.Gap{Consumer: consumer, MessageID: offset, SkippedAt: skippedAt})
+	}
+	return r.dbWith(tx).WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&gaps).Error
+}
+
+func (r *OutboxRepository) ListFilledGaps(ctx context.Context, tx interface{}, consumer string, since time.Time, limit int) ([]domain.Message, error) {
+	db := r.dbWith(tx).WithContext(ctx)
+	gaps := db.Model(&domain.Gap{}).
+		Select("message_id").
+		Where("consumer = ? AND skipped_at >= ?", consumer, since)
+	var list []domain.Message
+	err := db.Where("id IN (?)", gaps).
+		Order("id ASC").
+		Limit(limit).
+		Find(&list).Error
+	return list, err
+}
+
+func (r *OutboxRepository) DeleteGaps(ctx context.Context, tx interface{}, consumer string, offsets []uint64) error {
+	if len(offsets) == 0 {
+		return nil
+	}
+	return r.dbWith(tx).WithContext(ctx).
+		Where("consumer = ? AND message_id IN ?", consumer, offsets).
+		Delete(&domain.Gap{}).Error
+}
+
+func (r *OutboxRepository) ClearGaps(ctx context.Context, tx interface{}, consumer string, offset uint64) error {
+	return r.dbWith(tx).WithContext(ctx).
+		Where("consumer = ? AND message_id >= ?", consumer, offset).
+		Delete(&domain.Gap{}).Error
+}
+
+func (r *OutboxRepository) PruneGaps(ctx context.Context, tx interface{}, before time.Time) error {
+	return r.dbWith(tx).WithContext(ctx).
+		Where("skipped_at < ?", before).
+		Delete(&domain.Gap{}).Error
+}
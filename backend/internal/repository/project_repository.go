@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	domain "todolist/backend/internal/domain/project"
+	"todolist/backend/internal/domain/task"
+)
+
+type ProjectRepository struct {
+	db *gorm.DB
+}
+
+func NewProjectRepository(db *gorm.DB) *ProjectRepository {
+	return &ProjectRepository{db: db}
+}
+
+func (r *ProjectRepository) DB() *gorm.DB {
+	return r.db
+}
+
+func (r *ProjectRepository) dbWith(tx interface{}) *gorm.DB {
+	if tx != nil {
+		if db, ok := tx.(*gorm.DB); ok {
+			return db
+		}
+	}
+	return r.db
+}
+
+func (r *ProjectRepository) List(ctx context.Context, workspaceID *uint64, includeArchived bool) ([]domain.Project, error) {
+	query := r.db.WithContext(ctx).Model(&domain.Project{}).Scopes(visible(ctx, "projects.workspace_id"))
+	if workspaceID != nil {
+		query = query.Where("workspace_id = ?", *workspaceID)
+	}
+	if !includeArchived {
+		query = query.Where("archived = ?", false)
+	}
+	var projects []domain.Project
+	err := query.Order("archived ASC, name ASC").Find(&projects).Error
+	return projects, err
+}
+
+func (r *ProjectRepository) GetByUUID(ctx context.Context, tx interface{}, uuid string) (*domain.Project, error) {
+	var p domain.Project
+	err := r.dbWith(tx).WithContext(ctx).Scopes(visible(ctx, "projects.workspace_id")).Where("uuid = ?", uuid).First(&p).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *ProjectRepository) Create(ctx context.Context, tx interface{}, p *domain.Project) error {
+	return r.dbWith(tx).WithContext(ctx).Create(p).Error
+}
+
+func (r *ProjectRepository) Update(ctx context.Context, tx interface{}, p *domain.Project) error {
+	return r.dbWith(tx).WithContext(ctx).Save(p).Error
+}
+
+func (r *ProjectRepository) DeleteByUUID(ctx context.Context, tx interface{}, uuid string) error {
+	return r.dbWith(tx).WithContext(ctx).Where("uuid = ?", uuid).Delete(&domain.Project{}).Error
+}
+
+func (r *ProjectRepository) CountTasks(ctx context.Context, tx interface{}, uuid string) (int64, error) {
+	var count int64
+	err := r.dbWith(tx).WithContext(ctx).Model(&task.Task{}).Where("project_uuid = ?", uuid).Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"todolist/backend/internal/pkg/auth"
+)
+
+// visible limits a query to rows whose workspace, held in column, the caller
+// in ctx is a member of. Internal callers marked with auth.WithSystem see
+// every row; a context with no caller at all sees none.
+func visible(ctx context.Context, column string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if auth.IsSystem(ctx) {
+			return db
+		}
+		p, ok := auth.FromContext(ctx)
+		if !ok {
+			return db.Where("1 = 0")
+		}
+		return db.Where(column+" IN (SELECT workspace_id FROM workspace_members WHERE user_id = ?)", p.UserID)
+	}
+}
+
+// loggedBy limits a time entry query to the entries of the caller in ctx,
+// with the same rules for internal and anonymous callers as visible.
+func loggedBy(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if auth.IsSystem(ctx) {
+			return db
+		}
+		p, ok := auth.FromContext(ctx)
+		if !ok {
+			return db.Where("1 = 0")
+		}
+		return db.Where("task_time_entries.user_id = ?", p.UserID)
+	}
+}
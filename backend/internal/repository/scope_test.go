@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+
+	"todolist/backend/internal/domain/tag"
+	"todolist/backend/internal/pkg/auth"
+)
+
+func dryRun(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(mysql.New(mysql.Config{DSN: "user:pass@tcp(127.0.0.1:1)/todo", SkipInitializeWithVersion: true}),
+		&gorm.Config{DisableAutomaticPing: true, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestVisibleFailsClosed(t *testing.T) {
+	db := dryRun(t)
+	user := auth.WithPrincipal(context.Background(), auth.Principal{UserID: 7})
+	cases := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{name: "no caller", ctx: context.Background(), want: "1 = 0"},
+		{name: "user", ctx: user, want: "tags.workspace_id IN (SELECT workspace_id FROM workspace_members WHERE user_id = 7)"},
+		{name: "system", ctx: auth.WithSystem(context.Background()), want: ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+				return tx.Scopes(visible(tc.ctx, "tags.workspace_id")).Find(&[]tag.Tag{})
+			})
+			switch {
+			case tc.want == "" && strings.Contains(sql, "WHERE"):
+				t.Fatalf("system query is scoped: %s", sql)
+			case !strings.Contains(sql, tc.want):
+				t.Fatalf("query %s lacks %q", sql, tc.want)
+			}
+		})
+	}
+}
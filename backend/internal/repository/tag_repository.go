@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	domain "todolist/backend/internal/domain/tag"
+)
+
+type TagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) *TagRepository {
+	return &TagRepository{db: db}
+}
+
+func (r *TagRepository) DB() *gorm.DB {
+	return r.db
+}
+
+func (r *TagRepository) dbWith(tx interface{}) *gorm.DB {
+	if tx != nil {
+		if db, ok := tx.(*gorm.DB); ok {
+			return db
+		}
+	}
+	return r.db
+}
+
+func (r *TagRepository) List(ctx context.Context, workspaceID *uint64) ([]domain.Tag, error) {
+	query := r.db.WithContext(ctx).Scopes(visible(ctx, "tags.workspace_id"))
+	if workspaceID != nil {
+		query = query.Where("workspace_id = ?", *workspaceID)
+	}
+	var tags []domain.Tag
+	err := query.Order("name ASC, id ASC").Find(&tags).Error
+	return tags, err
+}
+
+func (r *TagRepository) GetByID(ctx context.Context, tx interface{}, id uint64) (*domain.Tag, error) {
+	var t domain.Tag
+	err := r.dbWith(tx).WithContext(ctx).Scopes(visible(ctx, "tags.workspace_id")).Where("id = ?", id).First(&t).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *TagRepository) GetByName(ctx context.Context, tx interface{}, workspaceID uint64, name string) (*domain.Tag, error) {
+	var t domain.Tag
+	err := r.dbWith(tx).WithContext(ctx).Where("workspace_id = ? AND name = ?", workspaceID, name).First(&t).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *TagRepository) Create(ctx context.Context, tx interface{}, t *domain.Tag) error {
+	return r.dbWith(tx).WithContext(ctx).Create(t).Error
+}
+
+func (r *TagRepository) Update(ctx context.Context, tx interface{}, t *domain.Tag) error {
+	return r.dbWith(tx).WithContext(ctx).Save(t).Error
+}
+
+func (r *TagRepository) Delete(ctx context.Context, tx interface{}, id uint64) error {
+	db := r.dbWith(tx).WithContext(ctx)
+	if err := db.Where("tag_id = ?", id).Delete(&domain.TaskTag{}).Error; err != nil {
+		return err
+	}
+	return db.Where("id = ?", id).Delete(&domain.Tag{}).Error
+}
+
+// Merge re-points every task_tags row from sourceID to targetID, skipping tasks
+// that already carry the target, then deletes the source tag.
+func (r *TagRepository) Merge(ctx context.Context, tx interface{}, sourceID, targetID uint64) error {
+	db := r.dbWith(tx).WithContext(ctx)
+
+	var taskUUIDs []string
+	if err := db.Model(&domain.TaskTag{}).Where("tag_id = ?", sourceID).Pluck("task_uuid", &taskUUIDs).Error; err != nil {
+		return err
+	}
+	if len(taskUUIDs) > 0 {
+		rows := make([]domain.TaskTag, 0, len(taskUUIDs))
+		for _, id := range taskUUIDs {
+			rows = append(rows, domain.TaskTag{TaskUUID: id, TagID: targetID})
+		}
+		if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error; err != nil {
+			return err
+		}
+	}
+	return r.Delete(ctx, db, sourceID)
+}
@@ -4,25 +4,66 @@ import (
 	"context"
 	"errors"
 	"strings"
+	"time"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 
+	"todolist/backend/internal/domain/customfield"
+	"todolist/backend/internal/domain/project"
+	"todolist/backend/internal/domain/tag"
 	domain "todolist/backend/internal/domain/task"
 )
 
+// urgencyExpr mirrors task.Task.Urgency so lists can be ordered by urgency in SQL.
+const urgencyExpr = "(CASE priority WHEN 'low' THEN 2 WHEN 'medium' THEN 4 WHEN 'high' THEN 6 WHEN 'urgent' THEN 8 ELSE 0 END)" +
+	" + (CASE WHEN deadline IS NULL THEN 0 WHEN deadline < CURDATE() THEN 10 ELSE GREATEST(0, 8 - DATEDIFF(deadline, CURDATE())) END)" +
+	" + LEAST(FLOOR(DATEDIFF(CURDATE(), created_at) / 7), 3)"
+
+// rollupJoin attaches per-parent child counts so lists can filter and sort by
+// progress. Its single argument is the list of terminal statuses.
+const rollupJoin = "LEFT JOIN (SELECT parent_uuid AS rollup_parent_uuid, COUNT(*) AS rollup_child_count," +
+	" SUM(CASE WHEN status IN ? THEN 1 ELSE 0 END) AS rollup_completed_count" +
+	" FROM tasks WHERE deleted_at IS NULL AND parent_uuid IS NOT NULL GROUP BY parent_uuid) rollup" +
+	" ON rollup.rollup_parent_uuid = tasks.uuid"
+
+const progressExpr = "(rollup.rollup_completed_count * 100 / rollup.rollup_child_count)"
+
+const deadlineOrder = "CASE WHEN deadline IS NULL THEN 1 ELSE 0 END ASC, deadline ASC, sort_weight ASC"
+
 type TaskRepository struct {
-	db *gorm.DB
+	db       *gorm.DB
+	workflow *domain.Workflow
 }
 
-func NewTaskRepository(db *gorm.DB) *TaskRepository {
-	return &TaskRepository{db: db}
+// NewTaskRepository creates a repository that treats the terminal statuses of
+// workflow as done; nil means the default workflow.
+func NewTaskRepository(db *gorm.DB, workflow *domain.Workflow) *TaskRepository {
+	if workflow == nil {
+		workflow = domain.DefaultWorkflow()
+	}
+	return &TaskRepository{db: db, workflow: workflow}
 }
 
 func (r *TaskRepository) DB() *gorm.DB {
 	return r.db
 }
 
+// withDetails preloads the associations every task response carries.
+func withDetails(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Tags").
+		Preload("BlockedBy").
+		Preload("Blocks").
+		Preload("Checklist", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		Preload("CustomValues", func(db *gorm.DB) *gorm.DB {
+			return db.Order("field_id ASC")
+		}).
+		Preload("CustomValues.Field")
+}
+
 func (r *TaskRepository) dbWith(tx interface{}) *gorm.DB {
 	if tx != nil {
 		if db, ok := tx.(*gorm.DB); ok {
@@ -33,27 +74,29 @@ func (r *TaskRepository) dbWith(tx interface{}) *gorm.DB {
 }
 
 func (r *TaskRepository) Create(ctx context.Context, tx interface{}, t *domain.Task) error {
-	return r.dbWith(tx).WithContext(ctx).Create(t).Error
+	return r.dbWith(tx).WithContext(ctx).Omit(clause.Associations).Create(t).Error
 }
 
 func (r *TaskRepository) Update(ctx context.Context, tx interface{}, t *domain.Task) error {
-	return r.dbWith(tx).WithContext(ctx).Save(t).Error
+	return r.dbWith(tx).WithContext(ctx).Omit(clause.Associations).Save(t).Error
 }
 
 func (r *TaskRepository) UpdateColumns(ctx context.Context, tx interface{}, uuid string, columns map[string]any) error {
-	return r.dbWith(tx).WithContext(ctx).Model(&domain.Task{}).Where("uuid = ?", uuid).Updates(columns).Error
+	return r.dbWith(tx).WithContext(ctx).Model(&domain.Task{}).Scopes(visible(ctx, "tasks.workspace_id")).Where("uuid = ?", uuid).Updates(columns).Error
 }
 
 func (r *TaskRepository) DeleteByUUID(ctx context.Context, tx interface{}, uuid string) error {
-	return r.dbWith(tx).WithContext(ctx).Where("uuid = ?", uuid).Delete(&domain.Task{}).Error
+	return r.dbWith(tx).WithContext(ctx).Scopes(visible(ctx, "tasks.workspace_id")).Where("uuid = ?", uuid).Delete(&domain.Task{}).Error
 }
 
 func (r *TaskRepository) GetByUUID(ctx context.Context, tx interface{}, uuid string) (*domain.Task, error) {
 	var t domain.Task
-	err := r.dbWith(tx).WithContext(ctx).
+	err := withDetails(r.dbWith(tx).WithContext(ctx)).
 		Preload("Children", func(db *gorm.DB) *gorm.DB {
 			return db.Order("sort_weight ASC")
 		}).
+		Preload("Children.Tags").
+		Scopes(visible(ctx, "tasks.workspace_id")).
 		Where("uuid = ?", uuid).
 		First(&t).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -62,7 +105,11 @@ func (r *TaskRepository) GetByUUID(ctx context.Context, tx interface{}, uuid str
 	if err != nil {
 		return nil, err
 	}
-	return &t, nil
+	tasks := []domain.Task{t}
+	if err := r.attachRollups(ctx, tx, tasks); err != nil {
+		return nil, err
+	}
+	return &tasks[0], nil
 }
 
 func (r *TaskRepository) GetByUUIDs(ctx context.Context, tx interface{}, uuids []string) ([]domain.Task, error) {
@@ -70,27 +117,111 @@ func (r *TaskRepository) GetByUUIDs(ctx context.Context, tx interface{}, uuids [
 		return []domain.Task{}, nil
 	}
 	var tasks []domain.Task
-	err := r.dbWith(tx).WithContext(ctx).
+	err := withDetails(r.dbWith(tx).WithContext(ctx)).
+		Scopes(visible(ctx, "tasks.workspace_id")).
 		Where("uuid IN ?", uuids).
 		Find(&tasks).Error
-	return tasks, err
+	if err != nil {
+		return nil, err
+	}
+	return tasks, r.attachRollups(ctx, tx, tasks)
+}
+
+func (r *TaskRepository) GetByParentUUIDs(ctx context.Context, tx interface{}, parentUUIDs []string) ([]domain.Task, error) {
+	if len(parentUUIDs) == 0 {
+		return []domain.Task{}, nil
+	}
+	var tasks []domain.Task
+	err := withDetails(r.dbWith(tx).WithContext(ctx)).
+		Scopes(visible(ctx, "tasks.workspace_id")).
+		Where("parent_uuid IN ?", parentUUIDs).
+		Order("sort_weight ASC").
+		Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+	return tasks, r.attachRollups(ctx, tx, tasks)
 }
 
 func (r *TaskRepository) List(ctx context.Context, filter domain.ListFilter) ([]domain.Task, int64, error) {
-	query := r.db.WithContext(ctx).Model(&domain.Task{})
+	query := r.db.WithContext(ctx).Model(&domain.Task{}).Scopes(visible(ctx, "tasks.workspace_id"))
 
 	// Only show root tasks in the main list
 	query = query.Where("parent_uuid IS NULL")
 
+	joinRollup := filter.ProgressMin != nil || filter.ProgressMax != nil || filter.Sort == domain.SortProgress
+	if joinRollup {
+		query = query.Joins(rollupJoin, r.workflow.TerminalStatuses())
+	}
+	if filter.ProgressMin != nil {
+		query = query.Where(progressExpr+" >= ?", *filter.ProgressMin)
+	}
+	if filter.ProgressMax != nil {
+		query = query.Where(progressExpr+" <= ?", *filter.ProgressMax)
+	}
+
+	if filter.WorkspaceID != nil {
+		query = query.Where("tasks.workspace_id = ?", *filter.WorkspaceID)
+	}
+	if filter.AssigneeID != nil {
+		query = query.Where("tasks.assignee_id = ?", *filter.AssigneeID)
+	} else if filter.Unassigned {
+		query = query.Where("tasks.assignee_id IS NULL")
+	}
 	if filter.Status != nil {
 		query = query.Where("status = ?", *filter.Status)
 	}
 
+	if filter.Priority != nil {
+		query = query.Where("priority = ?", *filter.Priority)
+	}
+
+	if filter.Blocked != nil {
+		openBlockers := r.db.Model(&domain.Dependency{}).
+			Select("1").
+			Joins("JOIN tasks blocker ON blocker.uuid = task_dependencies.blocker_uuid").
+			Where("task_dependencies.task_uuid = tasks.uuid").
+			Where("blocker.status NOT IN ? AND blocker.deleted_at IS NULL", r.workflow.TerminalStatuses())
+		if *filter.Blocked {
+			query = query.Where("EXISTS (?)", openBlockers)
+		} else {
+			query = query.Where("NOT EXISTS (?)", openBlockers)
+		}
+	}
+
+	if filter.ProjectUUID != nil {
+		query = query.Where("project_uuid = ?", *filter.ProjectUUID)
+	} else if filter.NoProject {
+		query = query.Where("project_uuid IS NULL")
+	}
+
 	if keyword := strings.TrimSpace(filter.Keyword); keyword != "" {
 		like := "%" + keyword + "%"
 		query = query.Where("title LIKE ? OR notes LIKE ?", like, like)
 	}
 
+	for _, name := range filter.Tags {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		tagged := r.db.Model(&tag.TaskTag{}).
+			Select("task_tags.task_uuid").
+			Joins("JOIN tags ON tags.id = task_tags.tag_id").
+			Where("tags.name = ?", name)
+		query = query.Where("uuid IN (?)", tagged)
+	}
+
+	for _, candidates := range filter.CustomValues {
+		pairs := make([][]interface{}, 0, len(candidates))
+		for _, v := range candidates {
+			pairs = append(pairs, []interface{}{v.FieldID, v.Value})
+		}
+		matching := r.db.Model(&customfield.Value{}).
+			Select("task_uuid").
+			Where("(field_id, value) IN ?", pairs)
+		query = query.Where("uuid IN (?)", matching)
+	}
+
 	var total int64
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -106,19 +237,36 @@ func (r *TaskRepository) List(ctx context.Context, filter domain.ListFilter) ([]
 	offset := (filter.Page - 1) * filter.PageSize
 
 	order := "sort_weight ASC"
-	if filter.Status != nil {
-		if *filter.Status == domain.StatusHistory {
-			order = "completed_at DESC"
-		} else {
-			order = "CASE WHEN deadline IS NULL THEN 1 ELSE 0 END ASC, deadline ASC, sort_weight ASC"
+	if filter.AssigneeID != nil {
+		// The assignee's own order, with tasks they never placed at their
+		// shared position.
+		query = query.Joins("LEFT JOIN task_assignee_orders assignee_order ON assignee_order.task_uuid = tasks.uuid AND assignee_order.assignee_id = ?", *filter.AssigneeID)
+		order = "COALESCE(assignee_order.position, tasks.sort_weight) ASC"
+	}
+	switch filter.Sort {
+	case domain.SortCompleted:
+		order = "completed_at DESC, sort_weight ASC"
+	case domain.SortUrgency:
+		order = urgencyExpr + " DESC, " + deadlineOrder
+	case domain.SortDeadline:
+		order = deadlineOrder
+	case domain.SortProgress:
+		direction := "ASC"
+		if filter.OrderDesc {
+			direction = "DESC"
 		}
+		order = "CASE WHEN rollup.rollup_child_count IS NULL THEN 1 ELSE 0 END ASC, " + progressExpr + " " + direction + ", sort_weight ASC"
+	}
+	if joinRollup || filter.AssigneeID != nil {
+		query = query.Select("tasks.*")
 	}
 
 	var tasks []domain.Task
-	err := query.
+	err := withDetails(query).
 		Preload("Children", func(db *gorm.DB) *gorm.DB {
 			return db.Order("sort_weight ASC")
 		}).
+		Preload("Children.Tags").
 		Order(order).
 		Offset(offset).
 		Limit(filter.PageSize).
@@ -126,11 +274,164 @@ func (r *TaskRepository) List(ctx context.Context, filter domain.ListFilter) ([]
 	if err != nil {
 		return nil, 0, err
 	}
+	if err := r.attachRollups(ctx, nil, tasks); err != nil {
+		return nil, 0, err
+	}
 	return tasks, total, nil
 }
 
+type rollupRow struct {
+	ParentUUID     string
+	ChildCount     int64
+	CompletedCount int64
+}
+
+type trackedRow struct {
+	TaskUUID     string
+	Seconds      int64
+	RunningSince *time.Time
+}
+
+// attachRollups fills ChildCount and CompletedChildCount on tasks and their
+// loaded children with a single aggregate query over direct subtasks.
+func (r *TaskRepository) attachRollups(ctx context.Context, tx interface{}, tasks []domain.Task) error {
+	var uuids []string
+	var collect func(list []domain.Task)
+	collect = func(list []domain.Task) {
+		for _, t := range list {
+			uuids = append(uuids, t.UUID)
+			collect(t.Children)
+		}
+	}
+	collect(tasks)
+	if len(uuids) == 0 {
+		return nil
+	}
+
+	var rows []rollupRow
+	err := r.dbWith(tx).WithContext(ctx).
+		Model(&domain.Task{}).
+		Select("parent_uuid, COUNT(*) AS child_count, SUM(CASE WHEN status IN ? THEN 1 ELSE 0 END) AS completed_count", r.workflow.TerminalStatuses()).
+		Where("parent_uuid IN ?", uuids).
+		Group("parent_uuid").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+	byParent := make(map[string]rollupRow, len(rows))
+	for _, row := range rows {
+		byParent[row.ParentUUID] = row
+	}
+
+	var tracked []trackedRow
+	err = r.dbWith(tx).WithContext(ctx).
+		Model(&domain.TimeEntry{}).
+		Select("task_uuid, SUM(duration_seconds) AS seconds, MAX(CASE WHEN ended_at IS NULL THEN started_at END) AS running_since").
+		Where("task_uuid IN ?", uuids).
+		Group("task_uuid").
+		Scan(&tracked).Error
+	if err != nil {
+		return err
+	}
+	byTask := make(map[string]trackedRow, len(tracked))
+	for _, row := range tracked {
+		byTask[row.TaskUUID] = row
+	}
+
+	var assign func(list []domain.Task)
+	assign = func(list []domain.Task) {
+		for i := range list {
+			row := byParent[list[i].UUID]
+			list[i].ChildCount = row.ChildCount
+			list[i].CompletedChildCount = row.CompletedCount
+			list[i].TrackedSeconds = byTask[list[i].UUID].Seconds
+			list[i].RunningSince = byTask[list[i].UUID].RunningSince
+			assign(list[i].Children)
+		}
+	}
+	assign(tasks)
+	return nil
+}
+
+// ListByStatus returns every task, subtasks included, in the given status.
+func (r *TaskRepository) ListByStatus(ctx context.Context, tx interface{}, status domain.Status, projectUUID *string) ([]domain.Task, error) {
+	query := r.dbWith(tx).WithContext(ctx).Scopes(visible(ctx, "tasks.workspace_id")).Where("status = ?", status)
+	if projectUUID != nil {
+		query = query.Where("project_uuid = ?", *projectUUID)
+	}
+	var tasks []domain.Task
+	if err := query.Order("sort_weight ASC").Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	if err := r.attachRollups(ctx, tx, tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// ListCompleted returns tasks, subtasks included, completed (in a terminal
+// status) within [from, to).
+func (r *TaskRepository) ListCompleted(ctx context.Context, tx interface{}, from, to time.Time, projectUUID *string) ([]domain.Task, error) {
+	query := r.dbWith(tx).WithContext(ctx).Scopes(visible(ctx, "tasks.workspace_id")).
+		Where("status IN ?", r.workflow.TerminalStatuses()).
+		Where("completed_at >= ? AND completed_at < ?", from, to)
+	if projectUUID != nil {
+		query = query.Where("project_uuid = ?", *projectUUID)
+	}
+	var tasks []domain.Task
+	if err := query.Order("completed_at ASC").Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	if err := r.attachRollups(ctx, tx, tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// NextSortWeight returns the smallest sort weight above after among the
+// siblings (same workspace and parent) in status, or nil when none follows.
+func (r *TaskRepository) NextSortWeight(ctx context.Context, tx interface{}, workspaceID uint64, status domain.Status, parentUUID *string, after int64) (*int64, error) {
+	var weights []int64
+	err := siblingScope(r.dbWith(tx).WithContext(ctx).Model(&domain.Task{}).Where("workspace_id = ?", workspaceID), status, parentUUID).
+		Where("sort_weight > ?", after).
+		Order("sort_weight ASC").
+		Limit(1).
+		Pluck("sort_weight", &weights).Error
+	if err != nil || len(weights) == 0 {
+		return nil, err
+	}
+	return &weights[0], nil
+}
+
+func (r *TaskRepository) ListSiblingsAfter(ctx context.Context, tx interface{}, workspaceID uint64, status domain.Status, parentUUID *string, after int64) ([]domain.Task, error) {
+	var tasks []domain.Task
+	err := siblingScope(withDetails(r.dbWith(tx).WithContext(ctx)).Where("workspace_id = ?", workspaceID), status, parentUUID).
+		Where("sort_weight > ?", after).
+		Order("sort_weight ASC").
+		Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+	return tasks, r.attachRollups(ctx, tx, tasks)
+}
+
+// ShiftSortWeights pushes every sibling in status ordered after after back by delta.
+func (r *TaskRepository) ShiftSortWeights(ctx context.Context, tx interface{}, workspaceID uint64, status domain.Status, parentUUID *string, after, delta int64) error {
+	return siblingScope(r.dbWith(tx).WithContext(ctx).Model(&domain.Task{}).Where("workspace_id = ?", workspaceID), status, parentUUID).
+		Where("sort_weight > ?", after).
+		UpdateColumn("sort_weight", gorm.Expr("sort_weight + ?", delta)).Error
+}
+
+func siblingScope(db *gorm.DB, status domain.Status, parentUUID *string) *gorm.DB {
+	db = db.Where("status = ?", status)
+	if parentUUID == nil {
+		return db.Where("parent_uuid IS NULL")
+	}
+	return db.Where("parent_uuid = ?", *parentUUID)
+}
+
 func (r *TaskRepository) BulkUpdateStatus(ctx context.Context, tx interface{}, uuids []string, status domain.Status, columns map[string]any) error {
-	q := r.dbWith(tx).WithContext(ctx).Model(&domain.Task{}).Where("uuid IN ?", uuids)
+	q := r.dbWith(tx).WithContext(ctx).Model(&domain.Task{}).Scopes(visible(ctx, "tasks.workspace_id")).Where("uuid IN ?", uuids)
 	updates := map[string]any{
 		"status": status,
 	}
@@ -141,7 +442,7 @@ func (r *TaskRepository) BulkUpdateStatus(ctx context.Context, tx interface{}, u
 }
 
 func (r *TaskRepository) BulkDelete(ctx context.Context, tx interface{}, uuids []string) error {
-	return r.dbWith(tx).WithContext(ctx).Where("uuid IN ?", uuids).Delete(&domain.Task{}).Error
+	return r.dbWith(tx).WithContext(ctx).Scopes(visible(ctx, "tasks.workspace_id")).Where("uuid IN ?", uuids).Delete(&domain.Task{}).Error
 }
 
 func (r *TaskRepository) ReplaceSnapshots(ctx context.Context, tx interface{}, snapshots []domain.Snapshot) error {
@@ -159,6 +460,15 @@ func (r *TaskRepository) ReplaceSnapshots(ctx context.Context, tx interface{}, s
 		if err != nil {
 			return err
 		}
+		if err := r.ReplaceTags(ctx, tx, snap.UUID, snap.TagIDs); err != nil {
+			return err
+		}
+		if err := r.ReplaceChecklist(ctx, tx, snap.UUID, snap.Checklist); err != nil {
+			return err
+		}
+		if err := r.ReplaceCustomValues(ctx, tx, snap.UUID, snap.CustomValues); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -173,3 +483,416 @@ func (r *TaskRepository) DeleteBySnapshots(ctx context.Context, tx interface{},
 	}
 	return r.BulkDelete(ctx, tx, uuids)
 }
+
+func (r *TaskRepository) GetTagsByIDs(ctx context.Context, tx interface{}, ids []uint64) ([]tag.Tag, error) {
+	if len(ids) == 0 {
+		return []tag.Tag{}, nil
+	}
+	var tags []tag.Tag
+	err := r.dbWith(tx).WithContext(ctx).
+		Scopes(visible(ctx, "tags.workspace_id")).
+		Where("id IN ?", ids).
+		Order("name ASC").
+		Find(&tags).Error
+	return tags, err
+}
+
+// ReplaceTags makes tagIDs the complete tag set of the task.
+func (r *TaskRepository) ReplaceTags(ctx context.Context, tx interface{}, uuid string, tagIDs []uint64) error {
+	if err := r.dbWith(tx).WithContext(ctx).Where("task_uuid = ?", uuid).Delete(&tag.TaskTag{}).Error; err != nil {
+		return err
+	}
+	return r.AddTags(ctx, tx, []string{uuid}, tagIDs)
+}
+
+func (r *TaskRepository) AddTags(ctx context.Context, tx interface{}, uuids []string, tagIDs []uint64) error {
+	if len(uuids) == 0 || len(tagIDs) == 0 {
+		return nil
+	}
+	rows := make([]tag.TaskTag, 0, len(uuids)*len(tagIDs))
+	for _, id := range uuids {
+		for _, tagID := range tagIDs {
+			rows = append(rows, tag.TaskTag{TaskUUID: id, TagID: tagID})
+		}
+	}
+	return r.dbWith(tx).WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&rows).Error
+}
+
+func (r *TaskRepository) RemoveTags(ctx context.Context, tx interface{}, uuids []string, tagIDs []uint64) error {
+	if len(uuids) == 0 || len(tagIDs) == 0 {
+		return nil
+	}
+	return r.dbWith(tx).WithContext(ctx).
+		Where("task_uuid IN ? AND tag_id IN ?", uuids, tagIDs).
+		Delete(&tag.TaskTag{}).Error
+}
+
+// Assign sets the assignee of the tasks; nil unassigns them.
+func (r *TaskRepository) Assign(ctx context.Context, tx interface{}, uuids []string, assigneeID *uint64) error {
+	if len(uuids) == 0 {
+		return nil
+	}
+	return r.dbWith(tx).WithContext(ctx).Model(&domain.Task{}).Scopes(visible(ctx, "tasks.workspace_id")).
+		Where("uuid IN ?", uuids).
+		UpdateColumn("assignee_id", assigneeID).Error
+}
+
+func (r *TaskRepository) SaveAssigneeOrder(ctx context.Context, tx interface{}, orders []domain.AssigneeOrder) error {
+	if len(orders) == 0 {
+		return nil
+	}
+	return r.dbWith(tx).WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "assignee_id"}, {Name: "task_uuid"}},
+			DoUpdates: clause.AssignmentColumns([]string{"position", "updated_at"}),
+		}).
+		Create(&orders).Error
+}
+
+func (r *TaskRepository) GetProject(ctx context.Context, tx interface{}, uuid string) (*project.Project, error) {
+	var p project.Project
+	err := r.dbWith(tx).WithContext(ctx).Scopes(visible(ctx, "projects.workspace_id")).Where("uuid = ?", uuid).First(&p).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *TaskRepository) AddDependency(ctx context.Context, tx interface{}, taskUUID, blockerUUID string) error {
+	return r.dbWith(tx).WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&domain.Dependency{TaskUUID: taskUUID, BlockerUUID: blockerUUID}).Error
+}
+
+func (r *TaskRepository) RemoveDependency(ctx context.Context, tx interface{}, taskUUID, blockerUUID string) error {
+	return r.dbWith(tx).WithContext(ctx).
+		Where("task_uuid = ? AND blocker_uuid = ?", taskUUID, blockerUUID).
+		Delete(&domain.Dependency{}).Error
+}
+
+// GetDependencies returns the blocked-by edges leaving the given tasks.
+func (r *TaskRepository) GetDependencies(ctx context.Context, tx interface{}, taskUUIDs []string) ([]domain.Dependency, error) {
+	if len(taskUUIDs) == 0 {
+		return []domain.Dependency{}, nil
+	}
+	var deps []domain.Dependency
+	err := r.dbWith(tx).WithContext(ctx).
+		Where("task_uuid IN ?", taskUUIDs).
+		Find(&deps).Error
+	return deps, err
+}
+
+// GetOpenBlockers returns the blocked-by edges of the given tasks whose blocker
+// is neither completed nor deleted.
+func (r *TaskRepository) GetOpenBlockers(ctx context.Context, tx interface{}, taskUUIDs []string) ([]domain.Dependency, error) {
+	if len(taskUUIDs) == 0 {
+		return []domain.Dependency{}, nil
+	}
+	var deps []domain.Dependency
+	err := r.dbWith(tx).WithContext(ctx).
+		Select("task_dependencies.*").
+		Joins("JOIN tasks blocker ON blocker.uuid = task_dependencies.blocker_uuid").
+		Where("task_dependencies.task_uuid IN ?", taskUUIDs).
+		Where("blocker.status NOT IN ? AND blocker.deleted_at IS NULL", r.workflow.TerminalStatuses()).
+		Find(&deps).Error
+	return deps, err
+}
+
+func (r *TaskRepository) CreateChecklistItem(ctx context.Context, tx interface{}, item *domain.ChecklistItem) error {
+	return r.dbWith(tx).WithContext(ctx).Create(item).Error
+}
+
+func (r *TaskRepository) UpdateChecklistItem(ctx context.Context, tx interface{}, item *domain.ChecklistItem) error {
+	return r.dbWith(tx).WithContext(ctx).Save(item).Error
+}
+
+func (r *TaskRepository) DeleteChecklistItem(ctx context.Context, tx interface{}, taskUUID string, id uint64) error {
+	return r.dbWith(tx).WithContext(ctx).
+		Where("task_uuid = ? AND id = ?", taskUUID, id).
+		Delete(&domain.ChecklistItem{}).Error
+}
+
+// ReplaceChecklist makes items, with their original IDs, the complete checklist of the task.
+func (r *TaskRepository) ReplaceChecklist(ctx context.Context, tx interface{}, taskUUID string, items []domain.ChecklistItem) error {
+	db := r.dbWith(tx).WithContext(ctx)
+	if err := db.Where("task_uuid = ?", taskUUID).Delete(&domain.ChecklistItem{}).Error; err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	rows := make([]domain.ChecklistItem, len(items))
+	copy(rows, items)
+	for i := range rows {
+		rows[i].TaskUUID = taskUUID
+	}
+	return db.Create(&rows).Error
+}
+
+// GetCustomFields lists the fields of the caller's workspaces, or of
+// workspaceID when set.
+func (r *TaskRepository) GetCustomFields(ctx context.Context, tx interface{}, workspaceID *uint64) ([]customfield.Field, error) {
+	query := r.dbWith(tx).WithContext(ctx).Scopes(visible(ctx, "custom_fields.workspace_id"))
+	if workspaceID != nil {
+		query = query.Where("workspace_id = ?", *workspaceID)
+	}
+	var fields []customfield.Field
+	err := query.Order("id ASC").Find(&fields).Error
+	return fields, err
+}
+
+// ReplaceCustomValues makes values the complete set of custom field values of
+// the task. Values of fields deleted in the meantime are dropped, which keeps
+// undo working after a field is removed.
+func (r *TaskRepository) ReplaceCustomValues(ctx context.Context, tx interface{}, taskUUID string, values []customfield.Value) error {
+	db := r.dbWith(tx).WithContext(ctx)
+	if err := db.Where("task_uuid = ?", taskUUID).Delete(&customfield.Value{}).Error; err != nil {
+		return err
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	fieldIDs := make([]uint64, 0, len(values))
+	for _, v := range values {
+		fieldIDs = append(fieldIDs, v.FieldID)
+	}
+	var existing []uint64
+	if err := db.Model(&customfield.Field{}).Where("id IN ?", fieldIDs).Pluck("id", &existing).Error; err != nil {
+		return err
+	}
+	present := make(map[uint64]bool, len(existing))
+	for _, id := range existing {
+		present[id] = true
+	}
+	rows := make([]customfield.Value, 0, len(values))
+	for _, v := range values {
+		if present[v.FieldID] {
+			rows = append(rows, customfield.Value{TaskUUID: taskUUID, FieldID: v.FieldID, Value: v.Value})
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	return db.Create(&rows).Error
+}
+
+func (r *TaskRepository) CreateTimeEntry(ctx context.Context, tx interface{}, entry *domain.TimeEntry) error {
+	return r.dbWith(tx).WithContext(ctx).Create(entry).Error
+}
+
+func (r *TaskRepository) UpdateTimeEntry(ctx context.Context, tx interface{}, entry *domain.TimeEntry) error {
+	return r.dbWith(tx).WithContext(ctx).Save(entry).Error
+}
+
+func (r *TaskRepository) DeleteTimeEntry(ctx context.Context, tx interface{}, taskUUID string, id uint64) error {
+	return r.dbWith(tx).WithContext(ctx).
+		Where("task_uuid = ? AND id = ?", taskUUID, id).
+		Delete(&domain.TimeEntry{}).Error
+}
+
+func (r *TaskRepository) GetTimeEntry(ctx context.Context, tx interface{}, taskUUID string, id uint64) (*domain.TimeEntry, error) {
+	var entry domain.TimeEntry
+	err := r.dbWith(tx).WithContext(ctx).Where("task_uuid = ? AND id = ?", taskUUID, id).First(&entry).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *TaskRepository) ListTimeEntries(ctx context.Context, tx interface{}, taskUUID string) ([]domain.TimeEntry, error) {
+	var entries []domain.TimeEntry
+	err := r.dbWith(tx).WithContext(ctx).
+		Where("task_uuid = ?", taskUUID).
+		Order("started_at DESC").
+		Find(&entries).Error
+	return entries, err
+}
+
+// ListTimeEntriesBetween returns the caller's entries started within [from, to),
+// oldest first.
+func (r *TaskRepository) ListTimeEntriesBetween(ctx context.Context, tx interface{}, from, to time.Time) ([]domain.TimeEntry, error) {
+	var entries []domain.TimeEntry
+	err := r.dbWith(tx).WithContext(ctx).Scopes(loggedBy(ctx)).
+		Where("started_at >= ? AND started_at < ?", from, to).
+		Order("started_at ASC").
+		Find(&entries).Error
+	return entries, err
+}
+
+// LockTimers locks the user's row for the rest of the transaction so that
+// concurrent timer starts for the same user run one after the other.
+func (r *TaskRepository) LockTimers(ctx context.Context, tx interface{}, userID uint64) error {
+	var id uint64
+	return r.dbWith(tx).WithContext(ctx).
+		Table("users").
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		Where("id = ?", userID).
+		Scan(&id).Error
+}
+
+// ListRunningTimeEntries returns the open timers of every user on the tasks,
+// locking them for the rest of the transaction.
+func (r *TaskRepository) ListRunningTimeEntries(ctx context.Context, tx interface{}, taskUUIDs []string) ([]domain.TimeEntry, error) {
+	entries := make([]domain.TimeEntry, 0)
+	if len(taskUUIDs) == 0 {
+		return entries, nil
+	}
+	err := r.dbWith(tx).WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("task_uuid IN ? AND ended_at IS NULL", taskUUIDs).
+		Find(&entries).Error
+	return entries, err
+}
+
+// GetRunningTimeEntry returns the caller's open timer, if any, locking it for the
+// rest of the transaction.
+func (r *TaskRepository) GetRunningTimeEntry(ctx context.Context, tx interface{}) (*domain.TimeEntry, error) {
+	var entry domain.TimeEntry
+	err := r.dbWith(tx).WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Scopes(loggedBy(ctx)).
+		Where("ended_at IS NULL").
+		Order("started_at DESC").
+		First(&entry).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *TaskRepository) CreateComment(ctx context.Context, tx interface{}, c *domain.Comment) error {
+	return r.dbWith(tx).WithContext(ctx).Create(c).Error
+}
+
+func (r *TaskRepository) UpdateComment(ctx context.Context, tx interface{}, c *domain.Comment) error {
+	return r.dbWith(tx).WithContext(ctx).Save(c).Error
+}
+
+func (r *TaskRepository) DeleteComment(ctx context.Context, tx interface{}, taskUUID string, id uint64) error {
+	return r.dbWith(tx).WithContext(ctx).
+		Where("task_uuid = ? AND id = ?", taskUUID, id).
+		Delete(&domain.Comment{}).Error
+}
+
+func (r *TaskRepository) GetComment(ctx context.Context, tx interface{}, taskUUID string, id uint64) (*domain.Comment, error) {
+	var c domain.Comment
+	err := r.dbWith(tx).WithContext(ctx).Where("task_uuid = ? AND id = ?", taskUUID, id).First(&c).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *TaskRepository) ListComments(ctx context.Context, tx interface{}, taskUUID string) ([]domain.Comment, error) {
+	var comments []domain.Comment
+	err := r.dbWith(tx).WithContext(ctx).
+		Where("task_uuid = ?", taskUUID).
+		Order("created_at ASC, id ASC").
+		Find(&comments).Error
+	return comments, err
+}
+
+func (r *TaskRepository) CreateActivity(ctx context.Context, tx interface{}, logs []domain.ActivityLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+	return r.dbWith(tx).WithContext(ctx).Create(&logs).Error
+}
+
+// ListActivity returns up to limit feed entries of a task, newest first,
+// starting below beforeID when it is non-zero.
+func (r *TaskRepository) ListActivity(ctx context.Context, tx interface{}, taskUUID string, limit int, beforeID uint64) ([]domain.ActivityLog, error) {
+	q := r.dbWith(tx).WithContext(ctx).Where("task_uuid = ?", taskUUID)
+	if beforeID > 0 {
+		q = q.Where("id < ?", beforeID)
+	}
+	var logs []domain.ActivityLog
+	err := q.Order("id DESC").Limit(limit).Find(&logs).Error
+	return logs, err
+}
+
+// ListDeleted returns trashed tasks whose parent is not trashed as well, most
+// recently deleted first.
+func (r *TaskRepository) ListDeleted(ctx context.Context, tx interface{}, limit int) ([]domain.Task, error) {
+	var tasks []domain.Task
+	err := withDetails(r.dbWith(tx).WithContext(ctx).Unscoped()).
+		Scopes(visible(ctx, "tasks.workspace_id")).
+		Where("deleted_at IS NOT NULL").
+		Where("parent_uuid IS NULL OR NOT EXISTS (SELECT 1 FROM tasks parent WHERE parent.uuid = tasks.parent_uuid AND parent.deleted_at IS NOT NULL)").
+		Order("deleted_at DESC").
+		Limit(limit).
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// GetWithDeleted loads a task whether or not it is in the trash.
+func (r *TaskRepository) GetWithDeleted(ctx context.Context, tx interface{}, uuid string) (*domain.Task, error) {
+	var t domain.Task
+	err := r.dbWith(tx).WithContext(ctx).Unscoped().Scopes(visible(ctx, "tasks.workspace_id")).Where("uuid = ?", uuid).First(&t).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *TaskRepository) GetDeletedByParentUUIDs(ctx context.Context, tx interface{}, parentUUIDs []string) ([]domain.Task, error) {
+	if len(parentUUIDs) == 0 {
+		return []domain.Task{}, nil
+	}
+	var tasks []domain.Task
+	err := r.dbWith(tx).WithContext(ctx).Unscoped().Scopes(visible(ctx, "tasks.workspace_id")).
+		Where("parent_uuid IN ? AND deleted_at IS NOT NULL", parentUUIDs).
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// Purge hard-deletes the tasks and every row that hangs off them.
+func (r *TaskRepository) Purge(ctx context.Context, tx interface{}, uuids []string) error {
+	if len(uuids) == 0 {
+		return nil
+	}
+	db := r.dbWith(tx).WithContext(ctx)
+	if err := db.Where("task_uuid IN ?", uuids).Delete(&tag.TaskTag{}).Error; err != nil {
+		return err
+	}
+	if err := db.Where("task_uuid IN ? OR blocker_uuid IN ?", uuids, uuids).Delete(&domain.Dependency{}).Error; err != nil {
+		return err
+	}
+	if err := db.Where("task_uuid IN ?", uuids).Delete(&domain.ChecklistItem{}).Error; err != nil {
+		return err
+	}
+	if err := db.Where("task_uuid IN ?", uuids).Delete(&domain.TimeEntry{}).Error; err != nil {
+		return err
+	}
+	if err := db.Where("task_uuid IN ?", uuids).Delete(&customfield.Value{}).Error; err != nil {
+		return err
+	}
+	if err := db.Where("task_uuid IN ?", uuids).Delete(&domain.AssigneeOrder{}).Error; err != nil {
+		return err
+	}
+	if err := db.Unscoped().Where("task_uuid IN ?", uuids).Delete(&domain.Comment{}).Error; err != nil {
+		return err
+	}
+	if err := db.Where("task_uuid IN ?", uuids).Delete(&domain.ActivityLog{}).Error; err != nil {
+		return err
+	}
+	return db.Unscoped().Where("uuid IN ?", uuids).Delete(&domain.Task{}).Error
+}
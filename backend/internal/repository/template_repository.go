@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	domain "todolist/backend/internal/domain/template"
+)
+
+type TemplateRepository struct {
+	db *gorm.DB
+}
+
+func NewTemplateRepository(db *gorm.DB) *TemplateRepository {
+	return &TemplateRepository{db: db}
+}
+
+func (r *TemplateRepository) DB() *gorm.DB {
+	return r.db
+}
+
+func (r *TemplateRepository) dbWith(tx interface{}) *gorm.DB {
+	if tx != nil {
+		if db, ok := tx.(*gorm.DB); ok {
+			return db
+		}
+	}
+	return r.db
+}
+
+func (r *TemplateRepository) List(ctx context.Context, workspaceID *uint64) ([]domain.Template, error) {
+	query := r.db.WithContext(ctx).Scopes(visible(ctx, "task_templates.workspace_id"))
+	if workspaceID != nil {
+		query = query.Where("workspace_id = ?", *workspaceID)
+	}
+	var templates []domain.Template
+	err := query.Order("name ASC, id ASC").Find(&templates).Error
+	return templates, err
+}
+
+func (r *TemplateRepository) GetByID(ctx context.Context, tx interface{}, id uint64) (*domain.Template, error) {
+	var t domain.Template
+	err := r.dbWith(tx).WithContext(ctx).Scopes(visible(ctx, "task_templates.workspace_id")).Where("id = ?", id).First(&t).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *TemplateRepository) Create(ctx context.Context, tx interface{}, t *domain.Template) error {
+	return r.dbWith(tx).WithContext(ctx).Create(t).Error
+}
+
+func (r *TemplateRepository) Update(ctx context.Context, tx interface{}, t *domain.Template) error {
+	return r.dbWith(tx).WithContext(ctx).Save(t).Error
+}
+
+func (r *TemplateRepository) Delete(ctx context.Context, tx interface{}, id uint64) error {
+	return r.dbWith(tx).WithContext(ctx).Where("id = ?", id).Delete(&domain.Template{}).Error
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -11,6 +12,7 @@ import (
 type TaskOperation struct {
 	ID          uint   `gorm:"primaryKey"`
 	Token       string `gorm:"uniqueIndex;size:26;not null"`
+	OwnerID     uint64 `gorm:"not null;default:0;index"`
 	Action      string `gorm:"size:20;not null"`
 	Scope       string `gorm:"size:10;not null"`
 	TaskIDs     string `gorm:"type:text;not null"`
@@ -63,6 +65,26 @@ func (r *UndoRepository) MarkConsumed(ctx context.Context, tx *gorm.DB, token st
 		Error
 }
 
+// ExpireByTasks expires, as of t, the pending operations that touched any of
+// the tasks.
+func (r *UndoRepository) ExpireByTasks(ctx context.Context, tx *gorm.DB, taskUUIDs []string, t time.Time) error {
+	if len(taskUUIDs) == 0 {
+		return nil
+	}
+	touched := make([]string, 0, len(taskUUIDs))
+	args := make([]interface{}, 0, len(taskUUIDs))
+	for _, id := range taskUUIDs {
+		touched = append(touched, "task_ids LIKE ?")
+		args = append(args, `%"`+id+`"%`)
+	}
+	return r.dbWith(tx).WithContext(ctx).
+		Model(&TaskOperation{}).
+		Where("consumed_at IS NULL AND expire_at > ?", t).
+		Where(strings.Join(touched, " OR "), args...).
+		Update("expire_at", t).
+		Error
+}
+
 func (r *UndoRepository) dbWith(tx *gorm.DB) *gorm.DB {
 	if tx != nil {
 		return tx
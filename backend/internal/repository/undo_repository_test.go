@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func TestExpireByTasksMatchesEveryTask(t *testing.T) {
+	db := dryRun(t).Session(&gorm.Session{SkipDefaultTransaction: true})
+	var sql string
+	err := db.Callback().Update().After("gorm:update").Register("test:capture", func(tx *gorm.DB) {
+		sql = tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := NewUndoRepository(db).ExpireByTasks(context.Background(), nil, []string{"a", "b"}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"UPDATE `task_operations` SET `expire_at`=",
+		"consumed_at IS NULL AND expire_at >",
+		`(task_ids LIKE '%"a"%' OR task_ids LIKE '%"b"%')`,
+	} {
+		if !strings.Contains(sql, want) {
+			t.Fatalf("query %s lacks %q", sql, want)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"todolist/backend/internal/domain/task"
+	domain "todolist/backend/internal/domain/user"
+)
+
+type UserRepository struct {
+	db *gorm.DB
+}
+
+func NewUserRepository(db *gorm.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+func (r *UserRepository) DB() *gorm.DB {
+	return r.db
+}
+
+func (r *UserRepository) dbWith(tx interface{}) *gorm.DB {
+	if tx != nil {
+		if db, ok := tx.(*gorm.DB); ok {
+			return db
+		}
+	}
+	return r.db
+}
+
+func (r *UserRepository) Count(ctx context.Context, tx interface{}) (int64, error) {
+	var count int64
+	err := r.dbWith(tx).WithContext(ctx).Model(&domain.User{}).Count(&count).Error
+	return count, err
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, tx interface{}, id uint64) (*domain.User, error) {
+	var u domain.User
+	err := r.dbWith(tx).WithContext(ctx).Where("id = ?", id).First(&u).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (r *UserRepository) GetByUsername(ctx context.Context, tx interface{}, username string) (*domain.User, error) {
+	var u domain.User
+	err := r.dbWith(tx).WithContext(ctx).Where("username = ?", username).First(&u).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (r *UserRepository) Create(ctx context.Context, tx interface{}, u *domain.User) error {
+	return r.dbWith(tx).WithContext(ctx).Create(u).Error
+}
+
+func (r *UserRepository) ClaimUnowned(ctx context.Context, tx interface{}, userID uint64) error {
+	db := r.dbWith(tx).WithContext(ctx)
+	if err := db.Unscoped().Model(&task.Task{}).Where("owner_id = 0").Update("owner_id", userID).Error; err != nil {
+		return err
+	}
+	if err := db.Model(&TaskOperation{}).Where("owner_id = 0").Update("owner_id", userID).Error; err != nil {
+		return err
+	}
+	if err := db.Unscoped().Model(&task.Comment{}).Where("author_id = 0").Update("author_id", userID).Error; err != nil {
+		return err
+	}
+	return db.Model(&task.ActivityLog{}).Where("owner_id = 0").Update("owner_id", userID).Error
+}
+
+func (r *UserRepository) CreateSession(ctx context.Context, tx interface{}, s *domain.Session) error {
+	return r.dbWith(tx).WithContext(ctx).Create(s).Error
+}
+
+func (r *UserRepository) GetSession(ctx context.Context, tx interface{}, tokenHash string) (*domain.Session, error) {
+	var s domain.Session
+	err := r.dbWith(tx).WithContext(ctx).Where("token_hash = ?", tokenHash).First(&s).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *UserRepository) DeleteSession(ctx context.Context, tx interface{}, tokenHash string) error {
+	return r.dbWith(tx).WithContext(ctx).Where("token_hash = ?", tokenHash).Delete(&domain.Session{}).Error
+}
+
+func (r *UserRepository) DeleteExpiredSessions(ctx context.Context, tx interface{}, userID uint64, now time.Time) error {
+	return r.dbWith(tx).WithContext(ctx).
+		Where("user_id = ? AND expires_at < ?", userID, now).
+		Delete(&domain.Session{}).Error
+}
+
+func (r *UserRepository) CreateAPIToken(ctx context.Context, tx interface{}, t *domain.APIToken) error {
+	return r.dbWith(tx).WithContext(ctx).Create(t).Error
+}
+
+func (r *UserRepository) ListAPITokens(ctx context.Context, tx interface{}, userID uint64) ([]domain.APIToken, error) {
+	var tokens []domain.APIToken
+	err := r.dbWith(tx).WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Find(&tokens).Error
+	return tokens, err
+}
+
+func (r *UserRepository) GetAPIToken(ctx context.Context, tx interface{}, userID, id uint64) (*domain.APIToken, error) {
+	var t domain.APIToken
+	err := r.dbWith(tx).WithContext(ctx).Where("user_id = ? AND id = ?", userID, id).First(&t).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *UserRepository) GetAPITokenByHash(ctx context.Context, tx interface{}, tokenHash string) (*domain.APIToken, error) {
+	var t domain.APIToken
+	err := r.dbWith(tx).WithContext(ctx).Where("token_hash = ?", tokenHash).First(&t).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *UserRepository) DeleteAPIToken(ctx context.Context, tx interface{}, userID, id uint64) error {
+	return r.dbWith(tx).WithContext(ctx).Where("user_id = ? AND id = ?", userID, id).Delete(&domain.APIToken{}).Error
+}
+
+func (r *UserRepository) TouchAPIToken(ctx context.Context, tx interface{}, id uint64, usedAt time.Time) error {
+	return r.dbWith(tx).WithContext(ctx).Model(&domain.APIToken{}).Where("id = ?", id).Update("last_used_at", usedAt).Error
+}
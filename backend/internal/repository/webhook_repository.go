@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	domain "todolist/backend/internal/domain/webhook"
+)
+
+type WebhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+func (r *WebhookRepository) DB() *gorm.DB {
+	return r.db
+}
+
+func (r *WebhookRepository) dbWith(tx interface{}) *gorm.DB {
+	if tx != nil {
+		if db, ok := tx.(*gorm.DB); ok {
+			return db
+		}
+	}
+	return r.db
+}
+
+func (r *WebhookRepository) List(ctx context.Context, tx interface{}, workspaceID uint64) ([]domain.Subscription, error) {
+	var list []domain.Subscription
+	err := r.dbWith(tx).WithContext(ctx).
+		Where("workspace_id = ?", workspaceID).
+		Order("id ASC").
+		Find(&list).Error
+	return list, err
+}
+
+func (r *WebhookRepository) ListActive(ctx context.Context, tx interface{}, workspaceIDs []uint64) ([]domain.Subscription, error) {
+	var list []domain.Subscription
+	err := r.dbWith(tx).WithContext(ctx).
+		Where("workspace_id IN ? AND active = ?", workspaceIDs, true).
+		Order("id ASC").
+		Find(&list).Error
+	return list, err
+}
+
+func (r *WebhookRepository) Get(ctx context.Context, tx interface{}, workspaceID, id uint64) (*domain.Subscription, error) {
+	var sub domain.Subscription
+	err := r.dbWith(tx).WithContext(ctx).
+		Where("id = ? AND workspace_id = ?", id, workspaceID).
+		First(&sub).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+func (r *WebhookRepository) GetByID(ctx context.Context, tx interface{}, id uint64) (*domain.Subscription, error) {
+	var sub domain.Subscription
+	err := r.dbWith(tx).WithContext(ctx).Where("id = ?", id).First(&sub).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+func (r *WebhookRepository) Create(ctx context.Context, tx interface{}, sub *domain.Subscription) error {
+	return r.dbWith(tx).WithContext(ctx).Create(sub).Error
+}
+
+func (r *WebhookRepository) Update(ctx context.Context, tx interface{}, sub *domain.Subscription) error {
+	return r.dbWith(tx).WithContext(ctx).Model(sub).
+		Select("url", "secret", "events", "active", "updated_at").
+		Updates(sub).Error
+}
+
+func (r *WebhookRepository) Delete(ctx context.Context, tx interface{}, id uint64) error {
+	db := r.dbWith(tx).WithContext(ctx)
+	deliveries := db.Model(&domain.Delivery{}).Select("id").Where("webhook_id = ?", id)
+	if err := db.Where("delivery_id IN (?)", deliveries).Delete(&domain.Attempt{}).Error; err != nil {
+		return err
+	}
+	if err := db.Where("webhook_id = ?", id).Delete(&domain.Delivery{}).Error; err != nil {
+		return err
+	}
+	return db.Where("id = ?", id).Delete(&domain.Subscription{}).Error
+}
+
+func (r *WebhookRepository) CreateDeliveries(ctx context.Context, tx interface{}, list []domain.Delivery) error {
+	if len(list) == 0 {
+		return nil
+	}
+	return r.dbWith(tx).WithContext(ctx).Create(&list).Error
+}
+
+func (r *WebhookRepository) QueuedEvents(ctx context.Context, tx interface{}, eventIDs []string) ([]string, error) {
+	var ids []string
+	if len(eventIDs) == 0 {
+		return ids, nil
+	}
+	err := r.dbWith(tx).WithContext(ctx).Model(&domain.Delivery{}).
+		Where("event_id IN ? AND redelivery_of IS NULL", eventIDs).
+		Distinct().
+		Pluck("event_id", &ids).Error
+	return ids, err
+}
+
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, tx interface{}, webhookID uint64, status domain.DeliveryStatus, limit int) ([]domain.Delivery, error) {
+	q := r.dbWith(tx).WithContext(ctx).Where("webhook_id = ?", webhookID)
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	var list []domain.Delivery
+	err := q.Order("id DESC").Limit(limit).Find(&list).Error
+	return list, err
+}
+
+func (r *WebhookRepository) GetDelivery(ctx context.Context, tx interface{}, webhookID, id uint64) (*domain.Delivery, error) {
+	var d domain.Delivery
+	err := r.dbWith(tx).WithContext(ctx).
+		Where("id = ? AND webhook_id = ?", id, webhookID).
+		First(&d).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *WebhookRepository) DueDeliveries(ctx context.Context, tx interface{}, now time.Time, limit int) ([]domain.Delivery, error) {
+	var list []domain.Delivery
+	err := r.dbWith(tx).WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", domain.StatusPending, now).
+		Order("next_attempt_at ASC, id ASC").
+		Limit(limit).
+		Find(&list).Error
+	return list, err
+}
+
+func (r *WebhookRepository) ClaimDelivery(ctx context.Context, tx interface{}, id uint64, now, until time.Time) (bool, error) {
+	res := r.dbWith(tx).WithContext(ctx).Model(&domain.Delivery{}).
+		Where("id = ? AND status = ? AND next_attempt_at <= ?", id, domain.StatusPending, now).
+		Update("next_attempt_at", until)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *WebhookRepository) FinishAttempt(ctx context.Context, tx interface{}, d *domain.Delivery, a *domain.Attempt) error {
+	return r.dbWith(tx).WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(d).
+			Select("status", "attempts", "next_attempt_at", "last_attempt_at", "response_status", "last_error", "updated_at").
+			Updates(d)
+		if res.Error != nil || res.RowsAffected == 0 {
+			// The delivery went away with its webhook meanwhile.
+			return res.Error
+		}
+		return tx.Create(a).Error
+	})
+}
+
+func (r *WebhookRepository) ListAttempts(ctx context.Context, tx interface{}, deliveryID uint64) ([]domain.Attempt, error) {
+	var list []domain.Attempt
+	err := r.dbWith(tx).WithContext(ctx).
+		Where("delivery_id = ?", deliveryID).
+		Order("number ASC").
+		Find(&list).Error
+	return list, err
+}
@@ -0,0 +1,350 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"todolist/backend/internal/domain/customfield"
+	"todolist/backend/internal/domain/project"
+	"todolist/backend/internal/domain/tag"
+	"todolist/backend/internal/domain/task"
+	"todolist/backend/internal/domain/template"
+	"todolist/backend/internal/domain/user"
+	"todolist/backend/internal/domain/webhook"
+	domain "todolist/backend/internal/domain/workspace"
+)
+
+const invitationColumns = "workspace_invitations.*, workspaces.name AS workspace_name, users.username AS invitee_username"
+
+type WorkspaceRepository struct {
+	db *gorm.DB
+}
+
+func NewWorkspaceRepository(db *gorm.DB) *WorkspaceRepository {
+	return &WorkspaceRepository{db: db}
+}
+
+func (r *WorkspaceRepository) DB() *gorm.DB {
+	return r.db
+}
+
+func (r *WorkspaceRepository) dbWith(tx interface{}) *gorm.DB {
+	if tx != nil {
+		if db, ok := tx.(*gorm.DB); ok {
+			return db
+		}
+	}
+	return r.db
+}
+
+// withRole selects workspaces together with the role userID holds in them.
+func withRole(db *gorm.DB, userID uint64) *gorm.DB {
+	return db.Model(&domain.Workspace{}).
+		Select("workspaces.*, workspace_members.role AS role").
+		Joins("JOIN workspace_members ON workspace_members.workspace_id = workspaces.id").
+		Where("workspace_members.user_id = ?", userID)
+}
+
+func (r *WorkspaceRepository) ListForUser(ctx context.Context, tx interface{}, userID uint64) ([]domain.Workspace, error) {
+	var workspaces []domain.Workspace
+	err := withRole(r.dbWith(tx).WithContext(ctx), userID).
+		Order("workspaces.personal_of IS NULL ASC, workspaces.name ASC").
+		Find(&workspaces).Error
+	return workspaces, err
+}
+
+func (r *WorkspaceRepository) GetForUser(ctx context.Context, tx interface{}, id, userID uint64) (*domain.Workspace, error) {
+	var w domain.Workspace
+	err := withRole(r.dbWith(tx).WithContext(ctx), userID).Where("workspaces.id = ?", id).First(&w).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+func (r *WorkspaceRepository) GetPersonal(ctx context.Context, tx interface{}, userID uint64) (*domain.Workspace, error) {
+	var w domain.Workspace
+	err := r.dbWith(tx).WithContext(ctx).Where("personal_of = ?", userID).First(&w).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+func (r *WorkspaceRepository) Create(ctx context.Context, tx interface{}, w *domain.Workspace) error {
+	return r.dbWith(tx).WithContext(ctx).Create(w).Error
+}
+
+func (r *WorkspaceRepository) Update(ctx context.Context, tx interface{}, w *domain.Workspace) error {
+	return r.dbWith(tx).WithContext(ctx).Model(w).Updates(map[string]any{"name": w.Name}).Error
+}
+
+func (r *WorkspaceRepository) Delete(ctx context.Context, tx interface{}, id uint64) error {
+	db := r.dbWith(tx).WithContext(ctx)
+	hooks := db.Model(&webhook.Subscription{}).Select("id").Where("workspace_id = ?", id)
+	deliveries := db.Model(&webhook.Delivery{}).Select("id").Where("webhook_id IN (?)", hooks)
+	if err := db.Where("delivery_id IN (?)", deliveries).Delete(&webhook.Attempt{}).Error; err != nil {
+		return err
+	}
+	if err := db.Where("webhook_id IN (?)", hooks).Delete(&webhook.Delivery{}).Error; err != nil {
+		return err
+	}
+	if err := db.Where("workspace_id = ?", id).Delete(&webhook.Subscription{}).Error; err != nil {
+		return err
+	}
+	if err := db.Where("workspace_id = ?", id).Delete(&domain.Invitation{}).Error; err != nil {
+		return err
+	}
+	if err := db.Where("workspace_id = ?", id).Delete(&domain.Member{}).Error; err != nil {
+		return err
+	}
+	if err := db.Where("workspace_id = ?", id).Delete(&tag.Tag{}).Error; err != nil {
+		return err
+	}
+	if err := db.Where("workspace_id = ?", id).Delete(&customfield.Field{}).Error; err != nil {
+		return err
+	}
+	if err := db.Where("workspace_id = ?", id).Delete(&template.Template{}).Error; err != nil {
+		return err
+	}
+	return db.Where("id = ?", id).Delete(&domain.Workspace{}).Error
+}
+
+func (r *WorkspaceRepository) CountContents(ctx context.Context, tx interface{}, id uint64) (int64, error) {
+	db := r.dbWith(tx).WithContext(ctx)
+	var tasks, projects int64
+	if err := db.Unscoped().Model(&task.Task{}).Where("workspace_id = ?", id).Count(&tasks).Error; err != nil {
+		return 0, err
+	}
+	if err := db.Unscoped().Model(&project.Project{}).Where("workspace_id = ?", id).Count(&projects).Error; err != nil {
+		return 0, err
+	}
+	return tasks + projects, nil
+}
+
+func (r *WorkspaceRepository) AdoptLegacy(ctx context.Context, tx interface{}, id, userID uint64) error {
+	db := r.dbWith(tx).WithContext(ctx)
+	if err := db.Unscoped().Model(&task.Task{}).
+		Where("workspace_id = 0 AND owner_id = ?", userID).
+		Update("workspace_id", id).Error; err != nil {
+		return err
+	}
+	if err := db.Model(&task.TimeEntry{}).
+		Where("user_id = 0 AND task_uuid IN (SELECT uuid FROM tasks WHERE owner_id = ?)", userID).
+		Update("user_id", userID).Error; err != nil {
+		return err
+	}
+
+	// Projects follow the tasks filed in them. A project the tasks of several
+	// users were filed in goes to whoever is provisioned first; the others get
+	// a copy of it in their own workspace.
+	referenced := db.Unscoped().Model(&task.Task{}).
+		Select("project_uuid").
+		Where("workspace_id = ? AND project_uuid IS NOT NULL", id)
+	if err := db.Unscoped().Model(&project.Project{}).
+		Where("workspace_id = 0 AND uuid IN (?)", referenced).
+		Update("workspace_id", id).Error; err != nil {
+		return err
+	}
+	var shared []project.Project
+	if err := db.Unscoped().
+		Where("workspace_id <> ? AND uuid IN (?)", id, referenced).
+		Find(&shared).Error; err != nil {
+		return err
+	}
+	for _, p := range shared {
+		copied := p
+		copied.ID = 0
+		copied.UUID = uuid.NewString()
+		copied.WorkspaceID = id
+		if err := db.Create(&copied).Error; err != nil {
+			return err
+		}
+		if err := db.Unscoped().Model(&task.Task{}).
+			Where("workspace_id = ? AND project_uuid = ?", id, p.UUID).
+			UpdateColumn("project_uuid", copied.UUID).Error; err != nil {
+			return err
+		}
+	}
+
+	// Tags and custom fields follow the tasks using them the same way.
+	tasks := db.Unscoped().Model(&task.Task{}).Select("uuid").Where("workspace_id = ?", id)
+	tagged := db.Model(&tag.TaskTag{}).Select("tag_id").Where("task_uuid IN (?)", tasks)
+	if err := db.Model(&tag.Tag{}).
+		Where("workspace_id = 0 AND id IN (?)", tagged).
+		Update("workspace_id", id).Error; err != nil {
+		return err
+	}
+	var sharedTags []tag.Tag
+	if err := db.Where("workspace_id <> ? AND id IN (?)", id, tagged).Find(&sharedTags).Error; err != nil {
+		return err
+	}
+	for _, t := range sharedTags {
+		copied := tag.Tag{WorkspaceID: id, Name: t.Name}
+		if err := db.Create(&copied).Error; err != nil {
+			return err
+		}
+		if err := db.Model(&tag.TaskTag{}).
+			Where("tag_id = ? AND task_uuid IN (?)", t.ID, tasks).
+			Update("tag_id", copied.ID).Error; err != nil {
+			return err
+		}
+	}
+
+	valued := db.Model(&customfield.Value{}).Select("field_id").Where("task_uuid IN (?)", tasks)
+	if err := db.Model(&customfield.Field{}).
+		Where("workspace_id = 0 AND id IN (?)", valued).
+		Update("workspace_id", id).Error; err != nil {
+		return err
+	}
+	var sharedFields []customfield.Field
+	if err := db.Where("workspace_id <> ? AND id IN (?)", id, valued).Find(&sharedFields).Error; err != nil {
+		return err
+	}
+	for _, f := range sharedFields {
+		copied := f
+		copied.ID = 0
+		copied.WorkspaceID = id
+		if err := db.Create(&copied).Error; err != nil {
+			return err
+		}
+		if err := db.Model(&customfield.Value{}).
+			Where("field_id = ? AND task_uuid IN (?)", f.ID, tasks).
+			Update("field_id", copied.ID).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *WorkspaceRepository) GetMember(ctx context.Context, tx interface{}, id, userID uint64) (*domain.Member, error) {
+	var m domain.Member
+	err := r.dbWith(tx).WithContext(ctx).Where("workspace_id = ? AND user_id = ?", id, userID).First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *WorkspaceRepository) ListMembers(ctx context.Context, tx interface{}, id uint64) ([]domain.Member, error) {
+	var members []domain.Member
+	err := r.dbWith(tx).WithContext(ctx).
+		Select("workspace_members.*, users.username AS username").
+		Joins("JOIN users ON users.id = workspace_members.user_id").
+		Where("workspace_members.workspace_id = ?", id).
+		Order("workspace_members.created_at ASC, workspace_members.user_id ASC").
+		Find(&members).Error
+	return members, err
+}
+
+func (r *WorkspaceRepository) CountOwners(ctx context.Context, tx interface{}, id uint64) (int64, error) {
+	var count int64
+	err := r.dbWith(tx).WithContext(ctx).Model(&domain.Member{}).
+		Where("workspace_id = ? AND role = ?", id, domain.RoleOwner).
+		Count(&count).Error
+	return count, err
+}
+
+// SaveMember inserts a membership or updates the role of an existing one.
+func (r *WorkspaceRepository) SaveMember(ctx context.Context, tx interface{}, m *domain.Member) error {
+	return r.dbWith(tx).WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "workspace_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"role"}),
+		}).
+		Create(m).Error
+}
+
+// DeleteMember removes the membership and unassigns the user's tasks in the
+// workspace.
+func (r *WorkspaceRepository) DeleteMember(ctx context.Context, tx interface{}, id, userID uint64) error {
+	db := r.dbWith(tx).WithContext(ctx)
+	if err := db.Unscoped().Model(&task.Task{}).
+		Where("workspace_id = ? AND assignee_id = ?", id, userID).
+		UpdateColumn("assignee_id", nil).Error; err != nil {
+		return err
+	}
+	return db.Where("workspace_id = ? AND user_id = ?", id, userID).Delete(&domain.Member{}).Error
+}
+
+func (r *WorkspaceRepository) FindUserID(ctx context.Context, tx interface{}, username string) (uint64, error) {
+	var ids []uint64
+	err := r.dbWith(tx).WithContext(ctx).Model(&user.User{}).Where("username = ?", username).Limit(1).Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	return ids[0], nil
+}
+
+func (r *WorkspaceRepository) CreateInvitation(ctx context.Context, tx interface{}, inv *domain.Invitation) error {
+	return r.dbWith(tx).WithContext(ctx).Create(inv).Error
+}
+
+func (r *WorkspaceRepository) GetInvitation(ctx context.Context, tx interface{}, invitationID uint64) (*domain.Invitation, error) {
+	var inv domain.Invitation
+	err := r.dbWith(tx).WithContext(ctx).Where("id = ?", invitationID).First(&inv).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
+
+func (r *WorkspaceRepository) FindInvitation(ctx context.Context, tx interface{}, id, inviteeID uint64) (*domain.Invitation, error) {
+	var inv domain.Invitation
+	err := r.dbWith(tx).WithContext(ctx).Where("workspace_id = ? AND invitee_id = ?", id, inviteeID).First(&inv).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
+
+// invitations selects invitations together with their workspace name and
+// invitee username.
+func invitations(db *gorm.DB) *gorm.DB {
+	return db.Model(&domain.Invitation{}).
+		Select(invitationColumns).
+		Joins("JOIN workspaces ON workspaces.id = workspace_invitations.workspace_id").
+		Joins("JOIN users ON users.id = workspace_invitations.invitee_id")
+}
+
+func (r *WorkspaceRepository) ListInvitations(ctx context.Context, tx interface{}, id uint64) ([]domain.Invitation, error) {
+	var list []domain.Invitation
+	err := invitations(r.dbWith(tx).WithContext(ctx)).
+		Where("workspace_invitations.workspace_id = ? AND workspace_invitations.expires_at > ?", id, time.Now()).
+		Order("workspace_invitations.created_at DESC").
+		Find(&list).Error
+	return list, err
+}
+
+func (r *WorkspaceRepository) ListInvitationsFor(ctx context.Context, tx interface{}, inviteeID uint64) ([]domain.Invitation, error) {
+	var list []domain.Invitation
+	err := invitations(r.dbWith(tx).WithContext(ctx)).
+		Where("workspace_invitations.invitee_id = ? AND workspace_invitations.expires_at > ?", inviteeID, time.Now()).
+		Order("workspace_invitations.created_at DESC").
+		Find(&list).Error
+	return list, err
+}
+
+func (r *WorkspaceRepository) DeleteInvitation(ctx context.Context, tx interface{}, invitationID uint64) error {
+	return r.dbWith(tx).WithContext(ctx).Where("id = ?", invitationID).Delete(&domain.Invitation{}).Error
+}
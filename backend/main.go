@@ -15,6 +15,7 @@ import (
 	"todolist/backend/internal/infra/config"
 	"todolist/backend/internal/infra/db"
 	"todolist/backend/internal/infra/logger"
+	"todolist/backend/internal/pkg/events"
 )
 
 func main() {
@@ -35,9 +36,14 @@ func main() {
 		logg.Fatal("failed to run migrations", zapError(err))
 	}
 
-	engine := routes.SetupRouter(cfg, logg, dbConn)
+	bus := events.NewBus(cfg.Events.BufferSize)
+	workers, stopWorkers := context.WithCancel(context.Background())
+	engine := routes.SetupRouter(workers, cfg, logg, dbConn, bus)
 
 	srv := serverConfig(cfg, engine)
+	// Open event streams would otherwise hold up the shutdown.
+	srv.RegisterOnShutdown(bus.Close)
+	srv.RegisterOnShutdown(stopWorkers)
 
 	go func() {
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {